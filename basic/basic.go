@@ -0,0 +1,94 @@
+// Package basic runs BASIC programs from Go code using in-memory input and output
+// It is meant for tests and for embedding the interpreter without touching the file system
+package basic
+
+import (
+	"basic-interpreter/internal/cli"
+	"basic-interpreter/internal/interpreter"
+	"basic-interpreter/internal/parser"
+	"context"
+	"errors"
+	"io"
+)
+
+// Options changes how Run executes a program; the zero value uses the defaults
+type Options struct {
+	MaxSteps      int             // Statements the program may execute; 0 for the default limit, -1 for no limit
+	Deterministic bool            // Seed RND with a fixed seed, so every run draws the same numbers
+	Context       context.Context // Cancelling it stops the program before its next statement; nil never does
+}
+
+// ErrorKind tells at which stage a program failed
+type ErrorKind int
+
+const (
+	SyntaxError  ErrorKind = iota // The program was rejected before it ran
+	RuntimeError                  // A statement failed while the program ran
+	Interrupted                   // The program was stopped by cancelling Options.Context
+)
+
+// Error is returned by Run when a program cannot be parsed or fails while running
+type Error struct {
+	Kind ErrorKind
+	Line int   // BASIC line number where the program failed, 0 when it is not known
+	Err  error // Underlying error, whose message describes the failure in full
+}
+
+// Error returns the message of the underlying error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run executes a BASIC program given as source text
+// INPUT reads lines from in and everything the program prints is written to out.
+// A program that fails returns an *Error telling whether it failed to parse or to run
+func Run(source string, in io.Reader, out io.Writer, opts Options) error {
+	config := cli.ExecutorConfig{
+		MaxSteps:      opts.MaxSteps,
+		Deterministic: opts.Deterministic,
+		Context:       opts.Context,
+	}
+	executor := cli.NewFileExecutorWithConfig(cli.NewStreamInputReader(in), cli.NewStreamOutputWriter(out), config)
+
+	if err := executor.ExecuteSource(source, false); err != nil {
+		return classifyError(err)
+	}
+	return nil
+}
+
+// classifyError turns an error from the executor into an *Error
+// Errors raised while statements run carry their line; anything else stopped the program before it ran
+func classifyError(err error) *Error {
+	var interrupted *interpreter.InterruptedError
+	if errors.As(err, &interrupted) {
+		return &Error{Kind: Interrupted, Line: interrupted.Line, Err: err}
+	}
+
+	var runtimeErr *interpreter.RuntimeError
+	if errors.As(err, &runtimeErr) {
+		return &Error{Kind: RuntimeError, Line: runtimeErr.Line, Err: err}
+	}
+
+	var syntaxErr *parser.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &Error{Kind: SyntaxError, Line: syntaxErr.Line, Err: err}
+	}
+	return &Error{Kind: SyntaxError, Err: err}
+}
+
+// RunString executes a BASIC program given as source text
+// Each element of inputs answers one INPUT statement, in order.
+// The returned outputs hold one entry per line written by the program;
+// they are returned even when execution fails, up to the point of the error
+func RunString(source string, inputs []string) ([]string, error) {
+	input := cli.NewMemoryInputReader(inputs)
+	output := cli.NewMemoryOutputWriter()
+
+	err := cli.NewFileExecutor(input, output).ExecuteSource(source, false)
+	return output.Lines(), err
+}
@@ -0,0 +1,116 @@
+package basic
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunString(t *testing.T) {
+	t.Run("collects program output", func(t *testing.T) {
+		outputs, err := RunString(`10 PRINT "Hello"
+20 PRINT 2 + 3
+30 END`, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Hello", " 5"}, outputs)
+	})
+
+	t.Run("answers INPUT from the inputs in order", func(t *testing.T) {
+		outputs, err := RunString(`10 INPUT "Name"; N$
+20 INPUT A
+30 PRINT N$, A * 2`, []string{"Ada", "21"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Name", "? ", "Ada            42"}, outputs)
+	})
+
+	t.Run("reports syntax errors", func(t *testing.T) {
+		_, err := RunString(`ABC PRINT "Hello"`, nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "syntax error")
+	})
+
+	t.Run("returns output produced before a runtime error", func(t *testing.T) {
+		outputs, err := RunString(`10 PRINT "Before"
+20 PRINT 1 / 0`, nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "runtime error")
+		assert.Equal(t, []string{"Before"}, outputs)
+	})
+
+	t.Run("running out of inputs is an error", func(t *testing.T) {
+		_, err := RunString(`10 INPUT A`, nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("writes program output to the writer", func(t *testing.T) {
+		var out strings.Builder
+		err := Run(`10 PRINT "Hello"
+20 PRINT 2 + 3`, strings.NewReader(""), &out, Options{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Hello\n 5\n", out.String())
+	})
+
+	t.Run("reads INPUT lines from the reader", func(t *testing.T) {
+		var out strings.Builder
+		err := Run(`10 INPUT "Name"; N$
+20 INPUT A
+30 PRINT N$, A * 2`, strings.NewReader("Ada\n21\n"), &out, Options{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Name\n? \nAda            42\n", out.String())
+	})
+
+	t.Run("reports syntax errors with their line", func(t *testing.T) {
+		var out strings.Builder
+		err := Run(`10 PRINT "Hello"
+20 PRINT (`, strings.NewReader(""), &out, Options{})
+
+		var basicErr *Error
+		require.ErrorAs(t, err, &basicErr)
+		assert.Equal(t, SyntaxError, basicErr.Kind)
+		assert.Equal(t, 20, basicErr.Line)
+		assert.Empty(t, out.String(), "nothing runs when the program does not parse")
+	})
+
+	t.Run("reports runtime errors with their line", func(t *testing.T) {
+		var out strings.Builder
+		err := Run(`10 PRINT "Before"
+20 PRINT 1 / 0`, strings.NewReader(""), &out, Options{})
+
+		var basicErr *Error
+		require.ErrorAs(t, err, &basicErr)
+		assert.Equal(t, RuntimeError, basicErr.Kind)
+		assert.Equal(t, 20, basicErr.Line)
+		assert.Contains(t, err.Error(), "division by zero")
+		assert.Equal(t, "Before\n", out.String())
+	})
+
+	t.Run("stops infinite loops at the step limit", func(t *testing.T) {
+		err := Run(`10 GOTO 10`, strings.NewReader(""), io.Discard, Options{MaxSteps: 100})
+
+		var basicErr *Error
+		require.ErrorAs(t, err, &basicErr)
+		assert.Equal(t, RuntimeError, basicErr.Kind)
+		assert.Contains(t, err.Error(), "execution exceeded 100 steps")
+	})
+
+	t.Run("deterministic runs draw the same random numbers", func(t *testing.T) {
+		source := `10 PRINT RND, RND`
+		var first, second strings.Builder
+		require.NoError(t, Run(source, strings.NewReader(""), &first, Options{Deterministic: true}))
+		require.NoError(t, Run(source, strings.NewReader(""), &second, Options{Deterministic: true}))
+
+		assert.Equal(t, first.String(), second.String())
+	})
+}
@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 
 	"basic-interpreter/internal/cli"
+	"basic-interpreter/internal/interpreter"
 )
 
+// interruptedExitCode is the conventional exit code of a program stopped by Ctrl-C
+const interruptedExitCode = 130
+
 func main() {
 	// Create CLI instance
 	cliInstance := cli.NewCLI()
@@ -34,8 +41,52 @@ func main() {
 	}
 	
 	// Create I/O interfaces
-	input := cli.NewStdInputReader()
-	output := cli.NewStdOutputWriter()
+	var input cli.InputReader = cli.NewStdInputReader()
+	var output cli.OutputWriter = cli.NewStdOutputWriter()
+	
+	// Replay a recorded interactive session instead of reading the keyboard
+	if config.ReplayFile != "" {
+		replayFile, err := os.Open(config.ReplayFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening session: %s\n", err.Error())
+			os.Exit(1)
+		}
+		input, err = cli.LoadSession(replayFile)
+		replayFile.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading session: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	
+	// Save every input line of an interactive session so it can be replayed
+	var recorder *cli.SessionRecorder
+	if config.RecordFile != "" {
+		recordFile, err := os.Create(config.RecordFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating session recording: %s\n", err.Error())
+			os.Exit(1)
+		}
+		defer recordFile.Close()
+		
+		recorder = cli.NewSessionRecorder(input, recordFile)
+		input = recorder
+	}
+	
+	// Record output and consumed input when a transcript is requested
+	var transcript *cli.Transcript
+	if config.TranscriptFile != "" {
+		transcriptFile, err := os.Create(config.TranscriptFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating transcript: %s\n", err.Error())
+			os.Exit(1)
+		}
+		defer transcriptFile.Close()
+		
+		transcript = cli.NewTranscript(transcriptFile)
+		input = cli.NewRecordingInputReader(input, transcript)
+		output = cli.NewRecordingOutputWriter(output, transcript)
+	}
 	
 	// Execute based on mode
 	if config.Interactive {
@@ -45,12 +96,65 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error in interactive mode: %s\n", err.Error())
 			os.Exit(1)
 		}
+	} else if config.CheckOnly {
+		// Check mode: report syntax errors and missing jump targets without executing
+		fileExecutor := cli.NewFileExecutor(input, output)
+		if err := fileExecutor.CheckFile(config.InputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking file: %s\n", err.Error())
+			os.Exit(1)
+		}
+	} else if config.LintMode {
+		// Lint mode: report warnings without executing
+		fileExecutor := cli.NewFileExecutor(input, output)
+		if err := fileExecutor.LintFile(config.InputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error linting file: %s\n", err.Error())
+			os.Exit(1)
+		}
 	} else {
 		// File execution mode
-		fileExecutor := cli.NewFileExecutor(input, output)
-		if err := fileExecutor.ExecuteFile(config.InputFile, config.DebugMode); err != nil {
+		executorConfig := cli.ExecutorConfig{
+			Deterministic: config.Deterministic,
+			TraceMode:     config.TraceMode,
+			CleanNumbers:  config.CleanNumbers,
+			StrictNext:    config.StrictNext,
+			CoerceCompare: config.CoerceCompare,
+			DecimalComma:  config.DecimalComma,
+			Variables:     config.Variables,
+			MaxSteps:      config.MaxSteps,
+		}
+		if config.AllowShell {
+			executorConfig.CommandRunner = cli.ExecCommandRunner{}
+		}
+		
+		// Ctrl-C stops the program between statements; a second Ctrl-C, for example while
+		// INPUT waits for a line, kills the process as usual
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+		executorConfig.Context = ctx
+		
+		fileExecutor := cli.NewFileExecutorWithConfig(input, output, executorConfig)
+		err := fileExecutor.ExecuteFile(config.InputFile, config.DebugMode)
+		stop()
+		var interrupted *interpreter.InterruptedError
+		if errors.As(err, &interrupted) {
+			os.Exit(interruptedExitCode) // The executor has already reported where the program stopped
+		}
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error executing file: %s\n", err.Error())
 			os.Exit(1)
 		}
 	}
+	
+	if recorder != nil && recorder.Err() != nil {
+		fmt.Fprintf(os.Stderr, "Error writing session recording: %s\n", recorder.Err().Error())
+		os.Exit(1)
+	}
+	
+	if transcript != nil && transcript.Err() != nil {
+		fmt.Fprintf(os.Stderr, "Error writing transcript: %s\n", transcript.Err().Error())
+		os.Exit(1)
+	}
 }
\ No newline at end of file
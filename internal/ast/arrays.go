@@ -0,0 +1,121 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+)
+
+// Arrays
+// DIM A(10) declares A with indexes 0 to 10, and DIM M(3, 4) a grid indexed M(i, j).
+// Arrays live apart from plain variables, so A and A(3) are unrelated, and A$(n)
+// holds strings like A$ does.
+
+// ArrayDeclaration is one array of a DIM statement
+type ArrayDeclaration struct {
+	Name  string
+	Sizes []Expression // Highest index of each dimension
+}
+
+// DimStatement represents a DIM statement declaring one or more arrays
+type DimStatement struct {
+	Arrays []ArrayDeclaration
+}
+
+// NewDimStatement creates a new DIM statement for the given arrays
+func NewDimStatement(arrays []ArrayDeclaration) *DimStatement {
+	return &DimStatement{Arrays: arrays}
+}
+
+// Execute evaluates the sizes and allocates the arrays in the environment
+func (d *DimStatement) Execute(env *runtime.Environment) error {
+	for _, array := range d.Arrays {
+		sizes, err := evaluateArrayIndexes(array.Sizes, env, "DIM size")
+		if err != nil {
+			return err
+		}
+		if err := env.DimArray(array.Name, sizes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArrayElementExpression represents a reference to an array element, such as A(3) or M(I, J)
+type ArrayElementExpression struct {
+	Name    string
+	Indexes []Expression // One index per dimension
+}
+
+// NewArrayElementExpression creates a new reference to an element of the named array
+func NewArrayElementExpression(name string, indexes []Expression) *ArrayElementExpression {
+	return &ArrayElementExpression{Name: name, Indexes: indexes}
+}
+
+// Evaluate returns the element at the evaluated indexes
+func (a *ArrayElementExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
+	indexes, err := evaluateArrayIndexes(a.Indexes, env, "array index")
+	if err != nil {
+		return runtime.Value{}, err
+	}
+	return env.GetArrayElement(a.Name, indexes)
+}
+
+// ArrayListExpression represents A() in a PRINT list, which prints every element of array A
+// in the order ArrayElements gives them, separated by the separator written after A()
+type ArrayListExpression struct {
+	Name string
+}
+
+// NewArrayListExpression creates a new PRINT item listing the elements of the named array
+func NewArrayListExpression(name string) *ArrayListExpression {
+	return &ArrayListExpression{Name: name}
+}
+
+// Evaluate fails, since a whole array is not a value that expressions can use
+func (a *ArrayListExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	return runtime.Value{}, fmt.Errorf("%s() can only be used in PRINT", NormalizeVariableName(a.Name))
+}
+
+// ArrayAssignmentStatement represents an assignment to an array element, such as A(3) = 5
+type ArrayAssignmentStatement struct {
+	Name       string
+	Indexes    []Expression // One index per dimension
+	Expression Expression
+}
+
+// NewArrayAssignmentStatement creates a new assignment to an element of the named array
+func NewArrayAssignmentStatement(name string, indexes []Expression, expression Expression) *ArrayAssignmentStatement {
+	return &ArrayAssignmentStatement{Name: name, Indexes: indexes, Expression: expression}
+}
+
+// Execute evaluates the indexes and the value and stores the value in the array
+func (a *ArrayAssignmentStatement) Execute(env *runtime.Environment) error {
+	indexes, err := evaluateArrayIndexes(a.Indexes, env, "array index")
+	if err != nil {
+		return err
+	}
+
+	value, err := a.Expression.Evaluate(env)
+	if err != nil {
+		return fmt.Errorf("error evaluating expression for assignment: %w", err)
+	}
+	return env.SetArrayElement(a.Name, indexes, value)
+}
+
+// evaluateArrayIndexes evaluates array indexes or sizes, dropping any fraction
+func evaluateArrayIndexes(exprs []Expression, env *runtime.Environment, context string) ([]int, error) {
+	indexes := make([]int, len(exprs))
+	for i, expr := range exprs {
+		value, err := EvaluateNumericExpression(expr, env, context)
+		if err != nil {
+			return nil, err
+		}
+		indexes[i] = int(value)
+	}
+	return indexes, nil
+}
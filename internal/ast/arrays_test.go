@@ -0,0 +1,112 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDimStatement_Execute(t *testing.T) {
+	env := runtime.NewEnvironment()
+	dim := NewDimStatement([]ArrayDeclaration{
+		{Name: "A", Sizes: []Expression{num(10)}},
+		{Name: "B$", Sizes: []Expression{NewBinaryExpression(num(1), OpAdd, num(1.5))}},
+	})
+
+	require.NoError(t, dim.Execute(env))
+	assert.Len(t, env.Arrays["A"].Elements, 11, "indexes run from 0 to the size")
+	assert.Len(t, env.Arrays["B$"].Elements, 3, "the size is truncated")
+
+	err := NewDimStatement([]ArrayDeclaration{{Name: "C", Sizes: []Expression{str("ten")}}}).Execute(env)
+	assert.Error(t, err)
+}
+
+func TestArrayElements(t *testing.T) {
+	env := runtime.NewEnvironment()
+	require.NoError(t, env.DimArray("A", []int{10}))
+
+	require.NoError(t, NewArrayAssignmentStatement("A", []Expression{num(3)}, num(5)).Execute(env))
+
+	value, err := NewArrayElementExpression("a", []Expression{num(3)}).Evaluate(env)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, value.NumValue)
+
+	value, err = NewArrayElementExpression("A", []Expression{num(4)}).Evaluate(env)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, value.NumValue)
+
+	_, err = NewArrayElementExpression("A", []Expression{num(11)}).Evaluate(env)
+	assert.EqualError(t, err, "index 11 out of bounds for array A(10)")
+
+	err = NewArrayAssignmentStatement("A", []Expression{str("x")}, num(1)).Execute(env)
+	assert.Error(t, err)
+}
+
+func TestArrayElements_TwoDimensions(t *testing.T) {
+	env := runtime.NewEnvironment()
+	grid := NewDimStatement([]ArrayDeclaration{{Name: "M", Sizes: []Expression{num(3), num(4)}}})
+	require.NoError(t, grid.Execute(env))
+
+	for i := 0; i <= 3; i++ {
+		for j := 0; j <= 4; j++ {
+			indexes := []Expression{num(float64(i)), num(float64(j))}
+			value := num(float64(i*10 + j))
+			require.NoError(t, NewArrayAssignmentStatement("M", indexes, value).Execute(env))
+		}
+	}
+
+	for i := 0; i <= 3; i++ {
+		for j := 0; j <= 4; j++ {
+			value, err := NewArrayElementExpression("M", []Expression{num(float64(i)), num(float64(j))}).Evaluate(env)
+			require.NoError(t, err)
+			assert.Equal(t, float64(i*10+j), value.NumValue, "M(%d, %d)", i, j)
+		}
+	}
+
+	_, err := NewArrayElementExpression("M", []Expression{num(2), num(5)}).Evaluate(env)
+	assert.EqualError(t, err, "index 5 out of bounds in dimension 2 of array M(3,4)")
+
+	_, err = NewArrayElementExpression("M", []Expression{num(2)}).Evaluate(env)
+	assert.EqualError(t, err, "array M(3,4) has 2 dimension(s), got 1 index(es)")
+}
+
+func TestArrayListExpression_Print(t *testing.T) {
+	env := runtime.NewEnvironment()
+	require.NoError(t, env.DimArray("A", []int{2}))
+	require.NoError(t, env.DimArray("N$", []int{2}))
+	for i, name := range []string{"ann", "bob", "cy"} {
+		require.NoError(t, env.SetArrayElement("A", []int{i}, runtime.NewNumericValue(float64(i+1)*10)))
+		require.NoError(t, env.SetArrayElement("N$", []int{i}, runtime.NewStringValue(name)))
+	}
+
+	t.Run("elements are printed in order in zones", func(t *testing.T) {
+		line, err := FormatPrintLine([]Expression{NewArrayListExpression("A")}, nil, env)
+		require.NoError(t, err)
+		assert.Equal(t, " 10            20            30", line)
+	})
+
+	t.Run("a semicolon after the array runs its elements together", func(t *testing.T) {
+		line, err := FormatPrintLine([]Expression{NewArrayListExpression("N$"), str("!")}, []rune{';'}, env)
+		require.NoError(t, err)
+		assert.Equal(t, "annbobcy!", line)
+	})
+
+	t.Run("a trailing semicolon of PRINT counts", func(t *testing.T) {
+		output := &MockOutputWriter{}
+		print := NewPrintStatementWithSeparators([]Expression{NewArrayListExpression("N$")}, nil, true, output)
+		require.NoError(t, print.Execute(env))
+		assert.Equal(t, []string{"annbobcy"}, output.GetOutput())
+	})
+
+	t.Run("the array must be dimensioned", func(t *testing.T) {
+		_, err := FormatPrintLine([]Expression{NewArrayListExpression("B")}, nil, env)
+		assert.EqualError(t, err, "array B not dimensioned")
+	})
+
+	t.Run("outside PRINT it is an error", func(t *testing.T) {
+		_, err := NewArrayListExpression("a").Evaluate(env)
+		assert.EqualError(t, err, "A() can only be used in PRINT")
+	})
+}
@@ -2,27 +2,44 @@ package ast
 
 import (
 	"basic-interpreter/internal/runtime"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Operator constants for better maintainability and type safety
 const (
-	OpAdd      = "+"
-	OpSubtract = "-"
-	OpMultiply = "*"
-	OpDivide   = "/"
-	OpPower    = "^"
+	OpAdd       = "+"
+	OpSubtract  = "-"
+	OpMultiply  = "*"
+	OpDivide    = "/"
+	OpIntDivide = "\\"
+	OpPower     = "^"
+	OpModulo    = "MOD"
+)
+
+// Logical operator constants, which combine BASIC truth values
+const (
+	OpAnd = "AND"
+	OpOr  = "OR"
+	OpNot = "NOT"
 )
 
 // Statement represents any executable statement in BASIC
 type Statement interface {
 	Execute(env *runtime.Environment) error
+	String() string // Canonical BASIC source of the statement, see source.go
 }
 
 // Expression represents any expression that can be evaluated to a value
 type Expression interface {
 	Evaluate(env *runtime.Environment) (runtime.Value, error)
+	String() string // Canonical BASIC source of the expression, see source.go
 }
 
 // Program represents a complete BASIC program
@@ -64,16 +81,21 @@ type BinaryExpression struct {
 
 // Evaluate performs the binary operation by evaluating both operands and applying the operator
 func (b *BinaryExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
 	// Evaluate left operand
 	leftVal, err := b.Left.Evaluate(env)
 	if err != nil {
-		return runtime.Value{}, fmt.Errorf("error evaluating left operand: %w", err)
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating left operand")
 	}
 
 	// Evaluate right operand
 	rightVal, err := b.Right.Evaluate(env)
 	if err != nil {
-		return runtime.Value{}, fmt.Errorf("error evaluating right operand: %w", err)
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating right operand")
 	}
 
 	// Apply the operator using the extracted operation logic
@@ -92,8 +114,12 @@ func (b *BinaryExpression) applyOperation(left, right runtime.Value) (runtime.Va
 		return left.Multiply(right)
 	case OpDivide:
 		return left.Divide(right)
+	case OpIntDivide:
+		return left.IntDivide(right)
 	case OpPower:
 		return left.Power(right)
+	case OpModulo:
+		return left.Modulo(right)
 	default:
 		return runtime.Value{}, fmt.Errorf("unsupported operator: %s", b.Operator)
 	}
@@ -106,54 +132,100 @@ type ParenthesesExpression struct {
 }
 
 // BuiltinFunction interface represents a built-in function that can be called
+// ArgCount is the number of arguments a call must have; a negative count (VariadicArgCount)
+// accepts any number and leaves Call to validate them, for functions with optional arguments.
+// The args slice given to Call is reused by later calls, so Call must not keep it
 type BuiltinFunction interface {
 	Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error)
 	Name() string
 	ArgCount() int
 }
 
+// VariadicArgCount is the ArgCount of functions that validate their argument count in Call
+const VariadicArgCount = -1
+
 // FunctionCallExpression represents a function call in an expression
 type FunctionCallExpression struct {
 	Name string
 	Args []Expression
+	
+	// Function is the built-in function called, resolved by the parser so that calls in loops
+	// skip the registry lookup; when nil, it is looked up by name on every call
+	Function BuiltinFunction
 }
 
 // Evaluate evaluates the wrapped expression
 // Parentheses don't change the evaluation logic, only precedence during parsing
 func (p *ParenthesesExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
 	result, err := p.Expression.Evaluate(env)
 	if err != nil {
-		return runtime.Value{}, fmt.Errorf("error evaluating parenthesized expression: %w", err)
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating parenthesized expression")
 	}
 	return result, nil
 }
 
+// wrapEvaluationError adds context to an error from a nested expression
+// The depth-limit error is passed through as is, so deep nesting gives one short message
+func wrapEvaluationError(err error, context string) error {
+	if errors.Is(err, runtime.ErrExpressionTooComplex) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", context, err)
+}
+
 // Evaluate evaluates a function call by looking up the function and calling it with evaluated arguments
 func (f *FunctionCallExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
-	// Look up the built-in function
-	builtinFunc := GetBuiltinFunction(f.Name)
+	// Functions defined with DEF FN are looked up on every call, since DEF runs at run time
+	if IsUserFunctionName(f.Name) {
+		function, defined := env.LookupFunction(f.Name)
+		if !defined {
+			return runtime.Value{}, fmt.Errorf("undefined function: %s", f.Name)
+		}
+		return callUserFunction(function, f, env)
+	}
+
+	// Look up the built-in function, unless the parser already did
+	builtinFunc := f.Function
+	if builtinFunc == nil {
+		builtinFunc = GetBuiltinFunction(f.Name)
+	}
 	if builtinFunc == nil {
 		return runtime.Value{}, fmt.Errorf("unknown function: %s", f.Name)
 	}
+	if err := env.CheckFeature(f.Name); err != nil {
+		return runtime.Value{}, err
+	}
 
-	// Validate argument count
-	if len(f.Args) != builtinFunc.ArgCount() {
+	// Validate argument count, unless the function accepts a variable number
+	if builtinFunc.ArgCount() >= 0 && len(f.Args) != builtinFunc.ArgCount() {
 		return runtime.Value{}, fmt.Errorf("function %s expects %d argument(s), got %d", 
 			f.Name, builtinFunc.ArgCount(), len(f.Args))
 	}
 
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
 	// Evaluate all arguments
-	args := make([]runtime.Value, len(f.Args))
+	// Evaluate all arguments onto the environment's argument stack
+	height := env.ArgumentHeight()
 	for i, argExpr := range f.Args {
 		value, err := argExpr.Evaluate(env)
 		if err != nil {
-			return runtime.Value{}, fmt.Errorf("error evaluating argument %d for function %s: %w", i, f.Name, err)
+			env.PopArguments(height)
+			return runtime.Value{}, wrapEvaluationError(err, fmt.Sprintf("error evaluating argument %d for function %s", i, f.Name))
 		}
-		args[i] = value
+		env.PushArgument(value)
 	}
 
 	// Call the function
-	result, err := builtinFunc.Call(args, env)
+	result, err := builtinFunc.Call(env.PopArguments(height), env)
 	if err != nil {
 		return runtime.Value{}, fmt.Errorf("error calling function %s: %w", f.Name, err)
 	}
@@ -198,7 +270,7 @@ func NewFunctionCallExpression(name string, args []Expression) *FunctionCallExpr
 // IsValidOperator checks if the given operator is supported
 func IsValidOperator(op string) bool {
 	switch op {
-	case OpAdd, OpSubtract, OpMultiply, OpDivide, OpPower:
+	case OpAdd, OpSubtract, OpMultiply, OpDivide, OpIntDivide, OpPower, OpModulo:
 		return true
 	default:
 		return false
@@ -211,7 +283,7 @@ func GetOperatorPrecedence(op string) int {
 	switch op {
 	case OpPower:
 		return 3 // Highest precedence
-	case OpMultiply, OpDivide:
+	case OpMultiply, OpDivide, OpIntDivide, OpModulo:
 		return 2 // Medium precedence
 	case OpAdd, OpSubtract:
 		return 1 // Lowest precedence
@@ -234,6 +306,10 @@ func (a *AssignmentStatement) Execute(env *runtime.Environment) error {
 		return err
 	}
 
+	if suffix, ok := a.appendedExpression(); ok {
+		return a.executeAppend(env, suffix)
+	}
+
 	// Evaluate the expression
 	value, err := a.Expression.Evaluate(env)
 	if err != nil {
@@ -245,6 +321,45 @@ func (a *AssignmentStatement) Execute(env *runtime.Environment) error {
 	return nil
 }
 
+// appendedExpression returns X$ when the assignment has the form S$ = S$ + X$
+func (a *AssignmentStatement) appendedExpression() (Expression, bool) {
+	sum, ok := a.Expression.(*BinaryExpression)
+	if !ok || sum.Operator != OpAdd || !strings.HasSuffix(a.Variable, "$") {
+		return nil, false
+	}
+	left, ok := sum.Left.(*VariableExpression)
+	if !ok || !strings.EqualFold(left.Name, a.Variable) {
+		return nil, false
+	}
+	return sum.Right, true
+}
+
+// executeAppend runs S$ = S$ + X$, appending to the variable's buffer when both sides are
+// strings, so that a string built in a loop is not copied whole on every pass
+func (a *AssignmentStatement) executeAppend(env *runtime.Environment, suffix Expression) error {
+	if err := env.EnterExpression(); err != nil {
+		return fmt.Errorf("error evaluating expression for assignment: %w", err)
+	}
+	defer env.LeaveExpression()
+
+	current := env.GetVariable(a.Variable)
+	right, err := suffix.Evaluate(env)
+	if err != nil {
+		return fmt.Errorf("error evaluating expression for assignment: %w", wrapEvaluationError(err, "error evaluating right operand"))
+	}
+
+	if current.Type != runtime.StringValue || right.Type != runtime.StringValue {
+		value, err := current.Add(right)
+		if err != nil {
+			return fmt.Errorf("error evaluating expression for assignment: %w", err)
+		}
+		env.SetVariable(a.Variable, value)
+		return nil
+	}
+	env.AppendString(a.Variable, current.StrValue, right.StrValue)
+	return nil
+}
+
 // OutputWriter interface for output operations (allows mocking in tests)
 type OutputWriter interface {
 	WriteLine(line string) error
@@ -255,10 +370,19 @@ type InputReader interface {
 	ReadLine() (string, error)
 }
 
+// KeyReader is implemented by input readers that can deliver a single keypress
+// Readers without it fall back to ReadLine, treating a whole line as the key
+type KeyReader interface {
+	ReadKey() (rune, error)
+}
+
 // PrintStatement represents a PRINT statement that outputs expressions
 type PrintStatement struct {
 	Expressions []Expression
-	Output      OutputWriter
+	Separators  []rune // Separator after each expression but the last: ',' moves to the next zone, ';' adds nothing
+	// TrailingSemicolon leaves the line open, so the next PRINT continues it
+	TrailingSemicolon bool
+	Output            OutputWriter
 }
 
 // Execute performs the print operation by evaluating expressions and outputting them
@@ -274,34 +398,121 @@ func (p *PrintStatement) Execute(env *runtime.Environment) error {
 		return err
 	}
 
+	// Writers that cannot leave a line open end it anyway
+	if writer, ok := p.Output.(io.Writer); ok && p.TrailingSemicolon {
+		_, err := io.WriteString(writer, output)
+		return err
+	}
 	return p.Output.WriteLine(output)
 }
 
 // evaluateAndFormatExpressions evaluates all expressions and formats them for output
+// A trailing semicolon is passed on as the last separator, so that PRINT A(); runs the
+// elements of A together
 func (p *PrintStatement) evaluateAndFormatExpressions(env *runtime.Environment) (string, error) {
-	var parts []string
-	for _, expr := range p.Expressions {
-		value, err := expr.Evaluate(env)
-		if err != nil {
-			return "", fmt.Errorf("error evaluating expression for print: %w", err)
+	separators := p.Separators
+	if p.TrailingSemicolon && len(separators) == len(p.Expressions)-1 {
+		separators = append(slices.Clip(separators), ';')
+	}
+	return FormatPrintLine(p.Expressions, separators, env)
+}
+
+// FormatPrintLine evaluates a PRINT expression list and returns the line PRINT would emit
+// This is the shared PRINT formatting engine used by PRINT and SPRINT$
+// separators[i] follows expressions[i]; missing separators count as commas, and a
+// separator after the last expression only matters to an array listed with A()
+func FormatPrintLine(expressions []Expression, separators []rune, env *runtime.Environment) (string, error) {
+	line := &printLine{}
+	for i, expr := range expressions {
+		// A comma moves to the next zone and a semicolon adds nothing
+		if i > 0 && (i > len(separators) || separators[i-1] != ';') {
+			line.nextZone()
+		}
+
+		switch e := expr.(type) {
+		case *TabExpression:
+			column, err := EvaluateNumericExpression(e.Column, env, "TAB column")
+			if err != nil {
+				return "", err
+			}
+			line.tab(int(min(column, MaxPrintPosition)))
+		case *SpcExpression:
+			count, err := EvaluateNumericExpression(e.Count, env, "SPC count")
+			if err != nil {
+				return "", err
+			}
+			line.write(strings.Repeat(" ", max(int(min(count, MaxPrintPosition)), 0)))
+		case *ArrayListExpression:
+			elements, err := env.ArrayElements(e.Name)
+			if err != nil {
+				return "", err
+			}
+			// The elements are separated as the items around them: in zones, or run together after ;
+			zones := i >= len(separators) || separators[i] != ';'
+			for j, element := range elements {
+				if j > 0 && zones {
+					line.nextZone()
+				}
+				line.write(formatPrintValue(element, env))
+			}
+		default:
+			value, err := expr.Evaluate(env)
+			if err != nil {
+				return "", fmt.Errorf("error evaluating expression for print: %w", err)
+			}
+			line.write(formatPrintValue(value, env))
 		}
-		parts = append(parts, value.ToString())
 	}
 
-	return p.formatOutput(parts), nil
+	return line.String(), nil
 }
 
-// formatOutput formats the evaluated parts into a single output string
-func (p *PrintStatement) formatOutput(parts []string) string {
-	// Join with spaces (default separator for comma-separated expressions)
-	output := ""
-	for i, part := range parts {
-		if i > 0 {
-			output += " "
-		}
-		output += part
+// formatPrintValue formats a single PRINT item, with the space classic BASIC prints in place
+// of the sign of a non-negative number unless the environment asks for clean numbers
+func formatPrintValue(value runtime.Value, env *runtime.Environment) string {
+	if value.Type == runtime.NumericValue && !env.CleanNumbers && value.NumValue >= 0 {
+		return " " + value.Format(env.NumberFormat)
+	}
+	return value.Format(env.NumberFormat)
+}
+
+// PrintZoneWidth is the width of the column zones that a comma in PRINT advances to
+const PrintZoneWidth = 14
+
+// SprintExpression represents SPRINT$(...), which captures what PRINT would emit as a string
+// Its argument list is parsed like a PRINT list rather than as ordinary function arguments
+type SprintExpression struct {
+	Expressions []Expression
+	Separators  []rune // Separator after each expression but the last, as in PrintStatement
+}
+
+// Evaluate formats the expression list with the PRINT formatting engine without writing it
+func (s *SprintExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
+	line, err := FormatPrintLine(s.Expressions, s.Separators, env)
+	if err != nil {
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating SPRINT$")
+	}
+	return runtime.NewStringValue(line), nil
+}
+
+// NewSprintExpression creates a new SPRINT$ expression over the given PRINT list and its separators
+func NewSprintExpression(expressions []Expression, separators []rune) *SprintExpression {
+	return &SprintExpression{Expressions: expressions, Separators: separators}
+}
+
+// IsDisableableStatement reports whether the statement keyword checks runtime.CheckFeature,
+// so disabling it through Environment.DisableFeature has an effect
+func IsDisableableStatement(keyword string) bool {
+	switch strings.ToUpper(keyword) {
+	case "INPUT", "PAUSE", "SHELL":
+		return true
 	}
-	return output
+	return false
 }
 
 // InputStatement represents an INPUT statement that reads user input into a variable
@@ -310,42 +521,110 @@ type InputStatement struct {
 	Variable string
 	Input    InputReader
 	Output   OutputWriter
+	Range    *InputRange // Optional inclusive bounds for numeric input
+	Line     bool        // LINE INPUT: read the whole line verbatim into a string variable
+}
+
+// InputRange holds the inclusive bounds of INPUT X RANGE low TO high
+type InputRange struct {
+	Low  Expression
+	High Expression
 }
 
 // Execute performs the input operation by displaying prompt and reading input
 func (i *InputStatement) Execute(env *runtime.Environment) error {
+	if err := env.CheckFeature("INPUT"); err != nil {
+		return err
+	}
+	
 	// Validate variable name
 	if err := ValidateVariableName(i.Variable); err != nil {
 		return err
 	}
 
-	// Display prompt
-	if err := i.displayPrompt(); err != nil {
-		return fmt.Errorf("error displaying prompt: %w", err)
+	for {
+		// Display prompt
+		if err := i.displayPrompt(); err != nil {
+			return fmt.Errorf("error displaying prompt: %w", err)
+		}
+
+		// Read and process input
+		value, err := i.readAndConvertInput(env.NumberFormat)
+		if err != nil {
+			return err
+		}
+
+		// Reprompt until the value is within range
+		inRange, err := i.checkRange(value, env)
+		if err != nil {
+			return err
+		}
+		if !inRange {
+			continue
+		}
+
+		// Store the value in the environment
+		env.SetVariable(i.Variable, value)
+		return nil
 	}
+}
 
-	// Read and process input
-	value, err := i.readAndConvertInput()
+// checkRange reports whether value lies within the RANGE bounds, telling the user when it does not
+func (i *InputStatement) checkRange(value runtime.Value, env *runtime.Environment) (bool, error) {
+	if i.Range == nil {
+		return true, nil
+	}
+	
+	low, err := i.evaluateBound(i.Range.Low, env)
 	if err != nil {
-		return err
+		return false, err
+	}
+	high, err := i.evaluateBound(i.Range.High, env)
+	if err != nil {
+		return false, err
+	}
+	
+	if value.NumValue >= low.NumValue && value.NumValue <= high.NumValue {
+		return true, nil
+	}
+	message := fmt.Sprintf("Please enter a number from %s to %s", low.ToString(), high.ToString())
+	if err := i.Output.WriteLine(message); err != nil {
+		return false, fmt.Errorf("error displaying range message: %w", err)
 	}
+	return false, nil
+}
 
-	// Store the value in the environment
-	env.SetVariable(i.Variable, value)
-	return nil
+// evaluateBound evaluates one RANGE bound, which must be numeric
+func (i *InputStatement) evaluateBound(bound Expression, env *runtime.Environment) (runtime.Value, error) {
+	value, err := bound.Evaluate(env)
+	if err != nil {
+		return runtime.Value{}, fmt.Errorf("error evaluating INPUT range: %w", err)
+	}
+	if value.Type != runtime.NumericValue {
+		return runtime.Value{}, fmt.Errorf("INPUT range bounds must be numeric")
+	}
+	return value, nil
 }
 
 // displayPrompt displays the input prompt to the user
 func (i *InputStatement) displayPrompt() error {
 	prompt := i.Prompt
 	if prompt == "" {
+		if i.Line {
+			return nil // LINE INPUT shows no prompt of its own
+		}
 		prompt = "? " // Default BASIC prompt
 	}
 	return i.Output.WriteLine(prompt)
 }
 
 // readAndConvertInput reads input and converts it to the appropriate type
-func (i *InputStatement) readAndConvertInput() (runtime.Value, error) {
+// Numbers are read with the decimal separator of the given format
+func (i *InputStatement) readAndConvertInput(format runtime.NumberFormat) (runtime.Value, error) {
+	if i.Line && !IsStringVariable(i.Variable) {
+		return runtime.Value{}, fmt.Errorf("LINE INPUT requires a string variable, found %s", i.Variable)
+	}
+
 	input, err := i.Input.ReadLine()
 	if err != nil {
 		return runtime.Value{}, fmt.Errorf("error reading input: %w", err)
@@ -358,7 +637,7 @@ func (i *InputStatement) readAndConvertInput() (runtime.Value, error) {
 	}
 
 	// Numeric variable - try to convert input to number
-	if numValue, err := runtime.NewStringValue(input).ToNumber(); err == nil {
+	if numValue, err := format.Parse(input); err == nil {
 		return runtime.NewNumericValue(numValue), nil
 	} else {
 		return runtime.Value{}, fmt.Errorf("cannot convert input '%s' to number: %w", input, err)
@@ -388,6 +667,17 @@ func NewPrintStatement(expressions []Expression, output OutputWriter) *PrintStat
 	}
 }
 
+// NewPrintStatementWithSeparators creates a new print statement that keeps the separators
+// written between its expressions and whether it ended with a semicolon
+func NewPrintStatementWithSeparators(expressions []Expression, separators []rune, trailingSemicolon bool, output OutputWriter) *PrintStatement {
+	return &PrintStatement{
+		Expressions:       expressions,
+		Separators:        separators,
+		TrailingSemicolon: trailingSemicolon,
+		Output:            output,
+	}
+}
+
 // NewInputStatement creates a new input statement with the given parameters
 func NewInputStatement(variable string, input InputReader, output OutputWriter) *InputStatement {
 	return &InputStatement{
@@ -416,12 +706,26 @@ func NewGotoStatement(lineNumber int, program *Program) *GotoStatement {
 }
 
 // EndStatement represents an END statement that terminates program execution
+// END n terminates with exit code n
 type EndStatement struct {
+	ExitCode Expression // Optional exit code; nil means 0
 }
 
-// Execute terminates the program execution
+// Execute terminates the program execution by halting the environment
 func (e *EndStatement) Execute(env *runtime.Environment) error {
-	// END statement simply returns nil to indicate normal termination
+	exitCode := 0
+	if e.ExitCode != nil {
+		value, err := e.ExitCode.Evaluate(env)
+		if err != nil {
+			return fmt.Errorf("error evaluating END exit code: %w", err)
+		}
+		if value.Type != runtime.NumericValue {
+			return fmt.Errorf("END exit code must be numeric")
+		}
+		exitCode = int(value.NumValue)
+	}
+	
+	env.Halt(exitCode)
 	return nil
 }
 
@@ -430,6 +734,211 @@ func NewEndStatement() *EndStatement {
 	return &EndStatement{}
 }
 
+// NewEndStatementWithExitCode creates a new END statement that terminates with the given exit code
+func NewEndStatementWithExitCode(exitCode Expression) *EndStatement {
+	return &EndStatement{ExitCode: exitCode}
+}
+
+// StopStatement represents a STOP statement, which ends the run like END but marks where
+// the program was broken off, as a breakpoint or an abort
+type StopStatement struct{}
+
+// Execute stops the program
+func (s *StopStatement) Execute(env *runtime.Environment) error {
+	env.Stop()
+	return nil
+}
+
+// NewStopStatement creates a new STOP statement
+func NewStopStatement() *StopStatement {
+	return &StopStatement{}
+}
+
+// RandomizeStatement represents a RANDOMIZE statement that reseeds the random number generator
+// RANDOMIZE n seeds with the whole part of n; plain RANDOMIZE seeds from the clock
+type RandomizeStatement struct {
+	Seed Expression // Optional seed, such as TIMER; nil means the current time
+}
+
+// NewRandomizeStatement creates a new RANDOMIZE statement with an optional seed expression
+func NewRandomizeStatement(seed Expression) *RandomizeStatement {
+	return &RandomizeStatement{Seed: seed}
+}
+
+// Execute evaluates the seed and reseeds the environment's generator
+func (r *RandomizeStatement) Execute(env *runtime.Environment) error {
+	if r.Seed == nil {
+		env.SetRandomSeed(env.Now().UnixNano())
+		return nil
+	}
+	
+	seed, err := EvaluateNumericExpression(r.Seed, env, "RANDOMIZE seed")
+	if err != nil {
+		return err
+	}
+	env.SetRandomSeed(int64(seed))
+	return nil
+}
+
+// DefaultPausePrompt is shown by a PAUSE statement without a custom message
+const DefaultPausePrompt = "Press any key to continue"
+
+// PauseStatement represents a PAUSE statement that waits for a keypress
+type PauseStatement struct {
+	Prompt string
+	Input  InputReader
+	Output OutputWriter
+}
+
+// Execute displays the prompt and waits for a single key, which is discarded
+func (p *PauseStatement) Execute(env *runtime.Environment) error {
+	if err := env.CheckFeature("PAUSE"); err != nil {
+		return err
+	}
+	
+	prompt := p.Prompt
+	if prompt == "" {
+		prompt = DefaultPausePrompt
+	}
+	if err := p.Output.WriteLine(prompt); err != nil {
+		return fmt.Errorf("error displaying prompt: %w", err)
+	}
+
+	if err := p.waitForKey(); err != nil {
+		return fmt.Errorf("error reading key: %w", err)
+	}
+	return nil
+}
+
+// waitForKey reads one key, using ReadKey when the input supports it
+func (p *PauseStatement) waitForKey() error {
+	if keyReader, ok := p.Input.(KeyReader); ok {
+		_, err := keyReader.ReadKey()
+		return err
+	}
+	_, err := p.Input.ReadLine()
+	return err
+}
+
+// CompoundStatement represents several statements on one line separated by colons
+type CompoundStatement struct {
+	Statements []Statement
+}
+
+// Execute runs the statements in order, as the only statements of a line
+// Execution stops early when a statement transfers control elsewhere, since the rest of
+// the line must not run after a jump
+func (c *CompoundStatement) Execute(env *runtime.Environment) error {
+	return ExecuteLine(c, env, 0)
+}
+
+// NewCompoundStatement creates a statement that runs the given statements in order
+func NewCompoundStatement(statements []Statement) *CompoundStatement {
+	return &CompoundStatement{Statements: statements}
+}
+
+// AssertStatement represents an ASSERT statement that stops the program when its condition is false
+type AssertStatement struct {
+	Condition Expression
+	LineNum   int // Line number reported when the assertion fails
+}
+
+// Execute evaluates the condition with the same truthiness rules as IF
+func (a *AssertStatement) Execute(env *runtime.Environment) error {
+	value, err := a.Condition.Evaluate(env)
+	if err != nil {
+		return fmt.Errorf("error evaluating ASSERT condition: %w", err)
+	}
+	
+	if !IsTrue(value) {
+		return fmt.Errorf("assertion failed at line %d", a.LineNum)
+	}
+	return nil
+}
+
+// NewAssertStatement creates a new ASSERT statement for the given line
+func NewAssertStatement(condition Expression, lineNum int) *AssertStatement {
+	return &AssertStatement{
+		Condition: condition,
+		LineNum:   lineNum,
+	}
+}
+
+// CommandRunner runs external commands for SHELL (allows mocking in tests)
+type CommandRunner interface {
+	RunCommand(command string) (string, error)
+}
+
+// ShellStatement represents a SHELL statement that runs an external command
+// SHELL "cmd" prints the command's output; SHELL "cmd", A$ captures it into A$
+//
+// Running commands gives the BASIC program the full privileges of the host process,
+// so SHELL is off by default: it fails with runtime.ErrFeatureDisabled unless the
+// host supplies a Runner. Only enable it for trusted programs.
+type ShellStatement struct {
+	Command  Expression
+	Variable string // String variable receiving the output; empty to print it
+	Runner   CommandRunner
+	Output   OutputWriter
+}
+
+// Execute runs the command through the runner and prints or captures its output
+func (s *ShellStatement) Execute(env *runtime.Environment) error {
+	if err := env.CheckFeature("SHELL"); err != nil {
+		return err
+	}
+	if s.Runner == nil {
+		return fmt.Errorf("%w: SHELL", runtime.ErrFeatureDisabled)
+	}
+	
+	command, err := s.Command.Evaluate(env)
+	if err != nil {
+		return fmt.Errorf("error evaluating SHELL command: %w", err)
+	}
+	if command.Type != runtime.StringValue {
+		return fmt.Errorf("SHELL command must be a string")
+	}
+	
+	output, err := s.Runner.RunCommand(command.StrValue)
+	if err != nil {
+		return fmt.Errorf("error running command: %w", err)
+	}
+	output = strings.TrimRight(output, "\n")
+	
+	if s.Variable != "" {
+		env.SetVariable(s.Variable, runtime.NewStringValue(output))
+		return nil
+	}
+	if output == "" {
+		return nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if err := s.Output.WriteLine(line); err != nil {
+			return fmt.Errorf("error writing command output: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewShellStatement creates a new SHELL statement, capturing output into variable when it is not empty
+func NewShellStatement(command Expression, variable string, runner CommandRunner, output OutputWriter) *ShellStatement {
+	return &ShellStatement{
+		Command:  command,
+		Variable: variable,
+		Runner:   runner,
+		Output:   output,
+	}
+}
+
+// NewPauseStatement creates a new PAUSE statement with an optional custom prompt
+func NewPauseStatement(prompt string, input InputReader, output OutputWriter) *PauseStatement {
+	return &PauseStatement{
+		Prompt: prompt,
+		Input:  input,
+		Output: output,
+	}
+}
+
 // RemStatement represents a REM (comment) statement
 type RemStatement struct {
 	Comment string
@@ -458,19 +967,27 @@ type ComparisonExpression struct {
 // Evaluate performs the comparison operation and returns a boolean result as a numeric value
 // In BASIC, true is represented as -1 and false as 0
 func (c *ComparisonExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
 	// Evaluate left operand
 	leftVal, err := c.Left.Evaluate(env)
 	if err != nil {
-		return runtime.Value{}, fmt.Errorf("error evaluating left operand in comparison: %w", err)
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating left operand in comparison")
 	}
 
 	// Evaluate right operand
 	rightVal, err := c.Right.Evaluate(env)
 	if err != nil {
-		return runtime.Value{}, fmt.Errorf("error evaluating right operand in comparison: %w", err)
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating right operand in comparison")
 	}
 
-	// Perform the comparison
+	// Perform the comparison, converting a numeric string first when the program allows it
+	if env.CoerceComparisons {
+		leftVal, rightVal = coerceComparisonOperands(leftVal, rightVal)
+	}
 	result, err := c.performComparison(leftVal, rightVal)
 	if err != nil {
 		return runtime.Value{}, err
@@ -483,6 +1000,22 @@ func (c *ComparisonExpression) Evaluate(env *runtime.Environment) (runtime.Value
 	return runtime.NewNumericValue(0), nil
 }
 
+// coerceComparisonOperands converts the string side of a number-string comparison to a number
+// A string that does not hold a number is left as it is, so the comparison still fails with a
+// type mismatch: 5 = "abc" is an error rather than false, as the string is most likely a mistake
+func coerceComparisonOperands(left, right runtime.Value) (runtime.Value, runtime.Value) {
+	if left.Type == right.Type {
+		return left, right
+	}
+	if number, err := left.ToNumber(); err == nil {
+		left = runtime.NewNumericValue(number)
+	}
+	if number, err := right.ToNumber(); err == nil {
+		right = runtime.NewNumericValue(number)
+	}
+	return left, right
+}
+
 // performComparison performs the actual comparison based on the operator
 func (c *ComparisonExpression) performComparison(left, right runtime.Value) (bool, error) {
 	// Check for type compatibility
@@ -525,34 +1058,91 @@ func (c *ComparisonExpression) compareValues(left, right runtime.Value, numericC
 	return stringCompare(left.StrValue, right.StrValue), nil
 }
 
-// IfStatement represents an IF-THEN conditional statement
-type IfStatement struct {
-	Condition     Expression
-	ThenStatement Statement
+// LogicalExpression represents AND or OR between two conditions
+// Both operands are always evaluated; there is no short-circuiting
+type LogicalExpression struct {
+	Left     Expression
+	Operator string
+	Right    Expression
 }
 
-// Execute performs the conditional execution by evaluating the condition and executing the THEN statement if true
-func (i *IfStatement) Execute(env *runtime.Environment) error {
-	// Evaluate the condition
-	conditionValue, err := i.Condition.Evaluate(env)
+// Evaluate combines the truth values of both operands, returning -1 for true and 0 for false
+// like ComparisonExpression, so the result can be used in further conditions and arithmetic
+func (l *LogicalExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
+	leftVal, err := l.Left.Evaluate(env)
 	if err != nil {
-		return fmt.Errorf("error evaluating IF condition: %w", err)
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating left operand of "+l.Operator)
 	}
 
-	// Check if condition is true (non-zero for numeric values, non-empty for strings)
-	if i.isConditionTrue(conditionValue) {
-		// Execute the THEN statement
-		if err := i.ThenStatement.Execute(env); err != nil {
-			return fmt.Errorf("error executing THEN statement: %w", err)
-		}
+	rightVal, err := l.Right.Evaluate(env)
+	if err != nil {
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating right operand of "+l.Operator)
 	}
 
-	return nil
+	switch l.Operator {
+	case OpAnd:
+		return truthValue(IsTrue(leftVal) && IsTrue(rightVal)), nil
+	case OpOr:
+		return truthValue(IsTrue(leftVal) || IsTrue(rightVal)), nil
+	default:
+		return runtime.Value{}, fmt.Errorf("unsupported logical operator: %s", l.Operator)
+	}
+}
+
+// NotExpression represents the unary NOT of a condition
+type NotExpression struct {
+	Operand Expression
+}
+
+// Evaluate negates the truth value of the operand, returning -1 for true and 0 for false
+func (n *NotExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
+	value, err := n.Operand.Evaluate(env)
+	if err != nil {
+		return runtime.Value{}, wrapEvaluationError(err, "error evaluating operand of NOT")
+	}
+	return truthValue(!IsTrue(value)), nil
+}
+
+// truthValue converts a Go boolean to a BASIC truth value (true = -1, false = 0)
+func truthValue(b bool) runtime.Value {
+	if b {
+		return runtime.NewNumericValue(-1)
+	}
+	return runtime.NewNumericValue(0)
+}
+
+// IfStatement represents an IF-THEN conditional statement, with an optional ELSE branch
+type IfStatement struct {
+	Condition     Expression
+	ThenStatement Statement
+	ElseStatement Statement // Run when the condition is false; nil when there is no ELSE
+}
+
+// Execute performs the conditional execution by evaluating the condition and executing
+// the THEN statement if true, or the ELSE statement if false
+// The IF runs as the only statement of a line; see ExecuteLine
+func (i *IfStatement) Execute(env *runtime.Environment) error {
+	return ExecuteLine(i, env, 0)
 }
 
 // isConditionTrue determines if a condition value should be considered true
-// In BASIC, zero is false, non-zero is true for numbers; empty string is false, non-empty is true for strings
 func (i *IfStatement) isConditionTrue(value runtime.Value) bool {
+	return IsTrue(value)
+}
+
+// IsTrue reports whether a value counts as true when used as a condition
+// In BASIC, zero is false, non-zero is true for numbers; empty string is false, non-empty is true for strings
+func IsTrue(value runtime.Value) bool {
 	if value.Type == runtime.NumericValue {
 		return value.NumValue != 0
 	}
@@ -568,11 +1158,36 @@ func NewComparisonExpression(left Expression, operator string, right Expression)
 	}
 }
 
-// NewIfStatement creates a new IF-THEN statement with the given condition and THEN statement
-func NewIfStatement(condition Expression, thenStatement Statement) *IfStatement {
-	return &IfStatement{
-		Condition:     condition,
-		ThenStatement: thenStatement,
+// NewLogicalExpression creates a new AND or OR expression with the given operands
+func NewLogicalExpression(left Expression, operator string, right Expression) *LogicalExpression {
+	return &LogicalExpression{
+		Left:     left,
+		Operator: operator,
+		Right:    right,
+	}
+}
+
+// NewNotExpression creates a new NOT expression negating the given operand
+func NewNotExpression(operand Expression) *NotExpression {
+	return &NotExpression{
+		Operand: operand,
+	}
+}
+
+// NewIfStatement creates a new IF-THEN statement with the given condition and THEN statement
+func NewIfStatement(condition Expression, thenStatement Statement) *IfStatement {
+	return &IfStatement{
+		Condition:     condition,
+		ThenStatement: thenStatement,
+	}
+}
+
+// NewIfElseStatement creates a new IF-THEN-ELSE statement with the given condition and branches
+func NewIfElseStatement(condition Expression, thenStatement, elseStatement Statement) *IfStatement {
+	return &IfStatement{
+		Condition:     condition,
+		ThenStatement: thenStatement,
+		ElseStatement: elseStatement,
 	}
 }
 
@@ -623,6 +1238,7 @@ func (f *ForStatement) Execute(env *runtime.Environment) error {
 		End:      endValue,
 		Step:     stepValue,
 		LineNum:  f.LineNum,
+		Body:     env.Statement + 1,
 	}
 
 	env.ForLoops = append(env.ForLoops, loopState)
@@ -661,8 +1277,8 @@ func (n *NextStatement) Execute(env *runtime.Environment) error {
 
 	// Check if loop should continue
 	if n.shouldContinueLoop(loop) {
-		// Continue loop - set program counter to loop start
-		SetProgramCounter(env, loop.LineNum)
+		// Continue loop - go back to the statement after the FOR
+		env.ResumeAt(runtime.ResumePoint{Line: loop.LineNum, Statement: loop.Body})
 	} else {
 		// Loop completed - remove from stack
 		env.ForLoops = append(env.ForLoops[:loopIndex], env.ForLoops[loopIndex+1:]...)
@@ -735,10 +1351,10 @@ func IsStringVariable(name string) bool {
 
 // Program counter management helper functions
 
-// SetProgramCounter sets the program counter to the specified line number
+// SetProgramCounter transfers control to the start of the specified line
 // This centralizes program counter management for control flow statements
 func SetProgramCounter(env *runtime.Environment, lineNumber int) {
-	env.ProgramCounter = lineNumber
+	env.JumpTo(lineNumber)
 }
 
 // ValidateLineNumber checks if a line number exists in the program
@@ -795,50 +1411,111 @@ func (g *GotoStatement) Execute(env *runtime.Environment) error {
 	return nil
 }
 
-// Function registry for built-in functions
-var builtinFunctions map[string]BuiltinFunction
+// GosubStatement represents a GOSUB statement that calls the subroutine at a line number
+type GosubStatement struct {
+	LineNumber int
+	Program    *Program
+}
 
-// init initializes the built-in function registry
-func init() {
-	builtinFunctions = make(map[string]BuiltinFunction)
-	
-	// Register mathematical functions
-	registerFunction(&AbsFunction{})
-	registerFunction(&IntFunction{})
-	registerFunction(&RndFunction{})
+// NewGosubStatement creates a new GOSUB statement with the given line number and program reference
+func NewGosubStatement(lineNumber int, program *Program) *GosubStatement {
+	return &GosubStatement{
+		LineNumber: lineNumber,
+		Program:    program,
+	}
+}
+
+// Execute pushes the statement after the GOSUB on the call stack and jumps to the subroutine
+func (g *GosubStatement) Execute(env *runtime.Environment) error {
+	if err := ValidateLineNumber(g.Program, g.LineNumber); err != nil {
+		return err
+	}
 	
-	// Register string functions
-	registerFunction(&LenFunction{})
-	registerFunction(&MidFunction{})
-	registerFunction(&StrFunction{})
-	registerFunction(&ValFunction{})
+	env.CallStack = append(env.CallStack, env.NextStatement())
+	SetProgramCounter(env, g.LineNumber)
+	return nil
 }
 
-// registerFunction registers a built-in function in the registry
-func registerFunction(fn BuiltinFunction) {
-	builtinFunctions[fn.Name()] = fn
+// ReturnStatement represents a RETURN statement that ends a subroutine
+type ReturnStatement struct{}
+
+// NewReturnStatement creates a new RETURN statement
+func NewReturnStatement() *ReturnStatement {
+	return &ReturnStatement{}
+}
+
+// Execute pops the most recent GOSUB and resumes at the statement after it
+func (r *ReturnStatement) Execute(env *runtime.Environment) error {
+	if len(env.CallStack) == 0 {
+		return fmt.Errorf("RETURN without GOSUB")
+	}
+	
+	last := len(env.CallStack) - 1
+	resume := env.CallStack[last]
+	env.CallStack = env.CallStack[:last]
+	env.ResumeAt(resume)
+	return nil
 }
 
-// GetRegisteredFunctionNames returns a list of all registered function names
+// standardLibraries returns the math and string libraries every function registry starts with
+func standardLibraries() []*FunctionLibrary {
+	return []*FunctionLibrary{
+		NewFunctionLibrary("math",
+			&AbsFunction{},
+			&MaxFunction{},
+			&MinFunction{},
+			&IntFunction{},
+			&FixFunction{},
+			&SqrFunction{},
+			&LogFunction{},
+			&ExpFunction{},
+			&RndFunction{},
+			&FreFunction{},
+			&TimerFunction{},
+		),
+		NewFunctionLibrary("string",
+			&LenFunction{},
+			&MidFunction{},
+			&LeftFunction{},
+			&RightFunction{},
+			&UcaseFunction{},
+			&LcaseFunction{},
+			&TrimFunction{},
+			&LtrimFunction{},
+			&RtrimFunction{},
+			&InstrFunction{},
+			&StrFunction{},
+			&DigitsFunction{},
+			&ValFunction{},
+			&IsNumericFunction{},
+			&FormatNumFunction{},
+		),
+	}
+}
+
+// standardFunctions holds the functions of the standard libraries by name, for calls built
+// without a parser; it is filled once and only read afterwards, so it is safe to share
+var standardFunctions = NewFunctionRegistry().functions
+
+// GetRegisteredFunctionNames returns a list of the standard function names
 func GetRegisteredFunctionNames() []string {
-	names := make([]string, 0, len(builtinFunctions))
-	for name := range builtinFunctions {
+	names := make([]string, 0, len(standardFunctions))
+	for name := range standardFunctions {
 		names = append(names, name)
 	}
 	return names
 }
 
-// IsFunctionRegistered checks if a function is registered
+// IsFunctionRegistered checks if a function is one of the standard functions
 func IsFunctionRegistered(name string) bool {
-	normalizedName := strings.ToUpper(name)
-	_, exists := builtinFunctions[normalizedName]
+	_, exists := standardFunctions[strings.ToUpper(name)]
 	return exists
 }
 
-// GetBuiltinFunction retrieves a built-in function by name (case-insensitive)
+// GetBuiltinFunction retrieves a standard function by name (case-insensitive)
+// Programs calling functions from other libraries are resolved with a FunctionRegistry instead
 func GetBuiltinFunction(name string) BuiltinFunction {
-	normalizedName := strings.ToUpper(name)
-	return builtinFunctions[normalizedName]
+	return standardFunctions[strings.ToUpper(name)]
 }
 
 // Helper functions for common validation patterns
@@ -865,6 +1542,14 @@ func (v *FunctionValidator) ValidateArgumentCount(expected, actual int) error {
 	return nil
 }
 
+// ValidateArgumentRange validates the argument count of a function with optional arguments
+func (v *FunctionValidator) ValidateArgumentRange(min, max, actual int) error {
+	if actual < min || actual > max {
+		return fmt.Errorf("%s function expected %d to %d arguments, got %d", v.functionName, min, max, actual)
+	}
+	return nil
+}
+
 // ValidateNumericArgument validates that an argument is numeric
 func (v *FunctionValidator) ValidateNumericArgument(argIndex int, arg runtime.Value) error {
 	if arg.Type != runtime.NumericValue {
@@ -924,7 +1609,54 @@ func (f *AbsFunction) Call(args []runtime.Value, env *runtime.Environment) (runt
 	return runtime.NewNumericValue(result), nil
 }
 
-// IntFunction implements the INT function (integer part)
+// MaxFunction implements the MAX function (larger of two numbers)
+type MaxFunction struct{}
+
+func (f *MaxFunction) Name() string { return "MAX" }
+func (f *MaxFunction) ArgCount() int { return 2 }
+
+func (f *MaxFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("MAX")
+	
+	if err := validator.ValidateArgumentCount(2, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	if err := validator.ValidateNumericArgument(1, args[1]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewNumericValue(math.Max(args[0].NumValue, args[1].NumValue)), nil
+}
+
+// MinFunction implements the MIN function (smaller of two numbers)
+type MinFunction struct{}
+
+func (f *MinFunction) Name() string { return "MIN" }
+func (f *MinFunction) ArgCount() int { return 2 }
+
+func (f *MinFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("MIN")
+	
+	if err := validator.ValidateArgumentCount(2, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	if err := validator.ValidateNumericArgument(1, args[1]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewNumericValue(math.Min(args[0].NumValue, args[1].NumValue)), nil
+}
+
+// IntFunction implements the INT function (largest integer not above the argument)
+// INT rounds down, so INT(-3.7) is -4; FIX drops the fraction instead
 type IntFunction struct{}
 
 func (f *IntFunction) Name() string { return "INT" }
@@ -941,29 +1673,162 @@ func (f *IntFunction) Call(args []runtime.Value, env *runtime.Environment) (runt
 		return runtime.Value{}, err
 	}
 	
-	result := float64(int(args[0].NumValue))
-	return runtime.NewNumericValue(result), nil
+	return runtime.NewNumericValue(math.Floor(args[0].NumValue)), nil
+}
+
+// FixFunction implements the FIX function (integer part, truncating toward zero)
+type FixFunction struct{}
+
+func (f *FixFunction) Name() string { return "FIX" }
+func (f *FixFunction) ArgCount() int { return 1 }
+
+func (f *FixFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("FIX")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewNumericValue(math.Trunc(args[0].NumValue)), nil
+}
+
+// SqrFunction implements the SQR function (square root)
+type SqrFunction struct{}
+
+func (f *SqrFunction) Name() string { return "SQR" }
+func (f *SqrFunction) ArgCount() int { return 1 }
+
+func (f *SqrFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("SQR")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	// math.Sqrt would give NaN, which then spreads silently through the rest of the program
+	if args[0].NumValue < 0 {
+		return runtime.Value{}, fmt.Errorf("SQR of negative number: %s", args[0].ToString())
+	}
+	return runtime.NewNumericValue(math.Sqrt(args[0].NumValue)), nil
+}
+
+// LogFunction implements the LOG function (natural logarithm)
+type LogFunction struct{}
+
+func (f *LogFunction) Name() string { return "LOG" }
+func (f *LogFunction) ArgCount() int { return 1 }
+
+func (f *LogFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("LOG")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	// math.Log would give -Inf for zero and NaN for negative numbers
+	if args[0].NumValue <= 0 {
+		return runtime.Value{}, fmt.Errorf("LOG of non-positive number: %s", args[0].ToString())
+	}
+	return runtime.NewNumericValue(math.Log(args[0].NumValue)), nil
+}
+
+// ExpFunction implements the EXP function (e raised to a power)
+type ExpFunction struct{}
+
+func (f *ExpFunction) Name() string { return "EXP" }
+func (f *ExpFunction) ArgCount() int { return 1 }
+
+func (f *ExpFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("EXP")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewNumericValue(math.Exp(args[0].NumValue)), nil
 }
 
 // RndFunction implements the RND function (random number 0-1)
+// RND may be written with or without an argument; classic programs write RND(1)
 type RndFunction struct{}
 
 func (f *RndFunction) Name() string { return "RND" }
-func (f *RndFunction) ArgCount() int { return 1 }
+func (f *RndFunction) ArgCount() int { return VariadicArgCount }
 
 func (f *RndFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
 	validator := NewFunctionValidator("RND")
 	
-	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+	if err := validator.ValidateArgumentRange(0, 1, len(args)); err != nil {
 		return runtime.Value{}, err
 	}
+	if len(args) == 1 {
+		if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+			return runtime.Value{}, err
+		}
+	}
 	
-	// In classic BASIC, RND(1) returns a random number between 0 and 1
-	// The argument is typically ignored in this implementation
+	// RND returns a random number between 0 and 1 drawn from the environment's generator,
+	// so a fixed seed gives a fixed sequence; the argument, as in RND(1), is ignored
 	result := env.Random()
 	return runtime.NewNumericValue(result), nil
 }
 
+// TimerFunction implements the TIMER function
+// TIMER is written without arguments and returns the seconds elapsed since midnight,
+// read from the environment's clock
+type TimerFunction struct{}
+
+func (f *TimerFunction) Name() string { return "TIMER" }
+func (f *TimerFunction) ArgCount() int { return 0 }
+
+func (f *TimerFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("TIMER")
+	
+	if err := validator.ValidateArgumentCount(0, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	now := env.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return runtime.NewNumericValue(now.Sub(midnight).Seconds()), nil
+}
+
+// FreFunction implements the FRE function (free memory)
+// FRE(0), or any numeric argument, reports free value slots; FRE("") reports free string space
+type FreFunction struct{}
+
+func (f *FreFunction) Name() string { return "FRE" }
+func (f *FreFunction) ArgCount() int { return 1 }
+
+func (f *FreFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("FRE")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if args[0].Type == runtime.StringValue {
+		return runtime.NewNumericValue(float64(env.FreeStringSpace())), nil
+	}
+	return runtime.NewNumericValue(float64(env.FreeMemory())), nil
+}
+
 // String Functions
 
 // LenFunction implements the LEN function (string length)
@@ -1032,6 +1897,219 @@ func (f *MidFunction) extractSubstring(str string, start, length float64) runtim
 	return runtime.NewStringValue(result)
 }
 
+// LeftFunction implements the LEFT$ function (leading characters of a string)
+type LeftFunction struct{}
+
+func (f *LeftFunction) Name() string { return "LEFT$" }
+func (f *LeftFunction) ArgCount() int { return 2 }
+
+func (f *LeftFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("LEFT$")
+	
+	if err := validator.ValidateArgumentCount(2, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	if err := validator.ValidateNumericArgument(1, args[1]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	str := args[0].StrValue
+	// Clamp before converting, since huge lengths would overflow int
+	length := int(min(args[1].NumValue, float64(len(str))))
+	
+	// Zero or negative lengths give an empty string; lengths past the end give the whole string
+	if length <= 0 {
+		return runtime.NewStringValue(""), nil
+	}
+	return runtime.NewStringValue(str[:length]), nil
+}
+
+// RightFunction implements the RIGHT$ function (trailing characters of a string)
+type RightFunction struct{}
+
+func (f *RightFunction) Name() string { return "RIGHT$" }
+func (f *RightFunction) ArgCount() int { return 2 }
+
+func (f *RightFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("RIGHT$")
+	
+	if err := validator.ValidateArgumentCount(2, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	if err := validator.ValidateNumericArgument(1, args[1]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	str := args[0].StrValue
+	// Clamp before converting, since huge lengths would overflow int
+	length := int(min(args[1].NumValue, float64(len(str))))
+	
+	// Zero or negative lengths give an empty string; lengths past the start give the whole string
+	if length <= 0 {
+		return runtime.NewStringValue(""), nil
+	}
+	return runtime.NewStringValue(str[len(str)-length:]), nil
+}
+
+// UcaseFunction implements the UCASE$ function (string converted to upper case)
+type UcaseFunction struct{}
+
+func (f *UcaseFunction) Name() string { return "UCASE$" }
+func (f *UcaseFunction) ArgCount() int { return 1 }
+
+func (f *UcaseFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("UCASE$")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewStringValue(strings.ToUpper(args[0].StrValue)), nil
+}
+
+// LcaseFunction implements the LCASE$ function (string converted to lower case)
+type LcaseFunction struct{}
+
+func (f *LcaseFunction) Name() string { return "LCASE$" }
+func (f *LcaseFunction) ArgCount() int { return 1 }
+
+func (f *LcaseFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("LCASE$")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewStringValue(strings.ToLower(args[0].StrValue)), nil
+}
+
+// TrimFunction implements the TRIM$ function (string without leading and trailing spaces)
+type TrimFunction struct{}
+
+func (f *TrimFunction) Name() string { return "TRIM$" }
+func (f *TrimFunction) ArgCount() int { return 1 }
+
+func (f *TrimFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("TRIM$")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewStringValue(strings.Trim(args[0].StrValue, " ")), nil
+}
+
+// LtrimFunction implements the LTRIM$ function (string without leading spaces)
+type LtrimFunction struct{}
+
+func (f *LtrimFunction) Name() string { return "LTRIM$" }
+func (f *LtrimFunction) ArgCount() int { return 1 }
+
+func (f *LtrimFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("LTRIM$")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewStringValue(strings.TrimLeft(args[0].StrValue, " ")), nil
+}
+
+// RtrimFunction implements the RTRIM$ function (string without trailing spaces)
+type RtrimFunction struct{}
+
+func (f *RtrimFunction) Name() string { return "RTRIM$" }
+func (f *RtrimFunction) ArgCount() int { return 1 }
+
+func (f *RtrimFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("RTRIM$")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	return runtime.NewStringValue(strings.TrimRight(args[0].StrValue, " ")), nil
+}
+
+// InstrFunction implements the INSTR function (1-based position of a substring)
+// INSTR(haystack$, needle$) searches from the start; INSTR(start, haystack$, needle$)
+// searches from the given position, and still returns positions counted from the start
+type InstrFunction struct{}
+
+func (f *InstrFunction) Name() string { return "INSTR" }
+func (f *InstrFunction) ArgCount() int { return VariadicArgCount }
+
+func (f *InstrFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("INSTR")
+	
+	if err := validator.ValidateArgumentRange(2, 3, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	start := 1
+	offset := 0 // Argument positions in errors count the start argument when present
+	if len(args) == 3 {
+		if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+			return runtime.Value{}, err
+		}
+		start = int(args[0].NumValue)
+		if start < 1 {
+			return runtime.Value{}, fmt.Errorf("INSTR start position must be at least 1, got %d", start)
+		}
+		args = args[1:]
+		offset = 1
+	}
+	
+	if err := validator.ValidateStringArgument(offset, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	if err := validator.ValidateStringArgument(offset+1, args[1]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	haystack := args[0].StrValue
+	needle := args[1].StrValue
+	
+	// Like classic BASIC, an empty string or a start past its end contains nothing,
+	// and an empty needle is found at the start position
+	if haystack == "" || start > len(haystack) {
+		return runtime.NewNumericValue(0), nil
+	}
+	index := strings.Index(haystack[start-1:], needle)
+	if index < 0 {
+		return runtime.NewNumericValue(0), nil
+	}
+	return runtime.NewNumericValue(float64(start + index)), nil
+}
+
 // StrFunction implements the STR$ function (number to string conversion)
 type StrFunction struct{}
 
@@ -1049,10 +2127,33 @@ func (f *StrFunction) Call(args []runtime.Value, env *runtime.Environment) (runt
 		return runtime.Value{}, err
 	}
 	
-	result := args[0].ToString()
+	result := args[0].Format(env.NumberFormat)
 	return runtime.NewStringValue(result), nil
 }
 
+// DigitsFunction implements the DIGITS function (length of a number as STR$ writes it)
+// The count covers every character of STR$ except a leading minus sign, so the decimal
+// point and exponent are included: DIGITS(-120) = 3, DIGITS(3.25) = 4, DIGITS(1E+06) = 5
+type DigitsFunction struct{}
+
+func (f *DigitsFunction) Name() string { return "DIGITS" }
+func (f *DigitsFunction) ArgCount() int { return 1 }
+
+func (f *DigitsFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("DIGITS")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	text := strings.TrimPrefix(args[0].Format(env.NumberFormat), "-")
+	return runtime.NewNumericValue(float64(len(text))), nil
+}
+
 // ValFunction implements the VAL function (string to number conversion)
 type ValFunction struct{}
 
@@ -1070,10 +2171,97 @@ func (f *ValFunction) Call(args []runtime.Value, env *runtime.Environment) (runt
 		return runtime.Value{}, err
 	}
 	
-	numValue, err := args[0].ToNumber()
+	numValue, err := env.NumberFormat.Parse(args[0].StrValue)
 	if err != nil {
 		return runtime.Value{}, fmt.Errorf("VAL function cannot convert '%s' to number: %w", args[0].StrValue, err)
 	}
 	
 	return runtime.NewNumericValue(numValue), nil
-}
\ No newline at end of file
+}
+
+// IsNumericFunction implements ISNUMERIC(s$), which returns -1 when VAL can convert the
+// whole trimmed string and 0 otherwise
+type IsNumericFunction struct{}
+
+func (f *IsNumericFunction) Name() string { return "ISNUMERIC" }
+func (f *IsNumericFunction) ArgCount() int { return 1 }
+
+func (f *IsNumericFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("ISNUMERIC")
+	
+	if err := validator.ValidateArgumentCount(1, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateStringArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if _, err := env.NumberFormat.Parse(args[0].StrValue); err != nil {
+		return runtime.NewNumericValue(0), nil
+	}
+	return runtime.NewNumericValue(-1), nil
+}
+
+// FormatNumFunction implements FORMATNUM$(x, decimals), formatting a number for display
+// with thousands separators and a fixed number of decimals: FORMATNUM$(1234567.5, 2) = "1,234,567.50"
+type FormatNumFunction struct{}
+
+func (f *FormatNumFunction) Name() string { return "FORMATNUM$" }
+func (f *FormatNumFunction) ArgCount() int { return 2 }
+
+func (f *FormatNumFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	validator := NewFunctionValidator("FORMATNUM$")
+	
+	if err := validator.ValidateArgumentCount(2, len(args)); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	if err := validator.ValidateNumericArgument(0, args[0]); err != nil {
+		return runtime.Value{}, err
+	}
+	if err := validator.ValidateNumericArgument(1, args[1]); err != nil {
+		return runtime.Value{}, err
+	}
+	
+	value := args[0].NumValue
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return runtime.Value{}, fmt.Errorf("FORMATNUM$ function cannot format %v", value)
+	}
+	
+	// Compare as floats, so huge decimals cannot overflow the conversion to int
+	if args[1].NumValue < 0 {
+		return runtime.Value{}, fmt.Errorf("FORMATNUM$ function decimals cannot be negative, got %g", args[1].NumValue)
+	}
+	if args[1].NumValue >= maxFormatDecimals+1 {
+		return runtime.Value{}, fmt.Errorf("FORMATNUM$ function decimals cannot be more than %d, got %g", maxFormatDecimals, args[1].NumValue)
+	}
+	
+	return runtime.NewStringValue(f.format(value, int(args[1].NumValue))), nil
+}
+
+// maxFormatDecimals is the most decimals FORMATNUM$ writes, beyond the precision of any number
+const maxFormatDecimals = 20
+
+// format rounds to the given decimals and groups the integer digits by thousands
+func (f *FormatNumFunction) format(value float64, decimals int) string {
+	text := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integerPart, fraction := text, ""
+	if dot := strings.IndexByte(text, '.'); dot >= 0 {
+		integerPart, fraction = text[:dot], text[dot:]
+	}
+	
+	var grouped strings.Builder
+	// Small negative values that round to zero are written without a sign
+	if value < 0 && strings.Trim(text, "0.") != "" {
+		grouped.WriteByte('-')
+	}
+	for i, digit := range integerPart {
+		if i > 0 && (len(integerPart)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	grouped.WriteString(fraction)
+	return grouped.String()
+}
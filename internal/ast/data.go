@@ -0,0 +1,163 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+)
+
+// DATA, READ and RESTORE
+// The values of every DATA statement are gathered into the environment's data pool
+// before the program runs, so READ takes them in program order wherever the DATA
+// lines are; executing a DATA statement does nothing.
+
+// DataStatement represents a DATA statement holding constant values for READ
+type DataStatement struct {
+	Values []runtime.Value
+}
+
+// NewDataStatement creates a new DATA statement with the given values
+func NewDataStatement(values []runtime.Value) *DataStatement {
+	return &DataStatement{Values: values}
+}
+
+// Execute does nothing, since the values were loaded into the data pool before the run
+func (d *DataStatement) Execute(env *runtime.Environment) error {
+	return nil
+}
+
+// ReadStatement represents a READ statement that assigns the next DATA values to variables
+type ReadStatement struct {
+	Variables []string
+}
+
+// NewReadStatement creates a new READ statement for the given variables
+func NewReadStatement(variables []string) *ReadStatement {
+	return &ReadStatement{Variables: variables}
+}
+
+// Execute reads one value from the data pool for each variable, in order
+// A numeric variable takes a string value only if it holds a number; a string
+// variable takes a number as the text PRINT would show for it
+func (r *ReadStatement) Execute(env *runtime.Environment) error {
+	for _, variable := range r.Variables {
+		if err := ValidateVariableName(variable); err != nil {
+			return err
+		}
+
+		value, err := env.ReadData()
+		if err != nil {
+			return err
+		}
+
+		value, err = convertDataValue(variable, value)
+		if err != nil {
+			return err
+		}
+		env.SetVariable(variable, value)
+	}
+	return nil
+}
+
+// ReadArrayStatement represents READ A(), n, which reads the next n DATA values into
+// consecutive elements of array A, starting from its first element (index 0)
+// For arrays of more than one dimension the elements are filled in row-major order
+type ReadArrayStatement struct {
+	Array string
+	Count Expression
+}
+
+// NewReadArrayStatement creates a new READ statement filling the named array
+func NewReadArrayStatement(array string, count Expression) *ReadArrayStatement {
+	return &ReadArrayStatement{Array: array, Count: count}
+}
+
+// Execute reads Count values from the data pool into the array, converting each to the
+// array's type as READ does for variables. Elements read before running out of DATA keep
+// their new values
+func (r *ReadArrayStatement) Execute(env *runtime.Environment) error {
+	value, err := EvaluateNumericExpression(r.Count, env, "READ count")
+	if err != nil {
+		return err
+	}
+	count := int(value)
+	if count < 0 {
+		return fmt.Errorf("READ count cannot be negative, got %d", count)
+	}
+
+	elements, err := env.ArrayElements(r.Array)
+	if err != nil {
+		return err
+	}
+	if count > len(elements) {
+		return fmt.Errorf("subscript out of range: cannot READ %d values into array %s of %d elements",
+			count, NormalizeVariableName(r.Array), len(elements))
+	}
+
+	for position := 0; position < count; position++ {
+		value, err := env.ReadData()
+		if err != nil {
+			return err
+		}
+		value, err = convertDataValue(r.Array, value)
+		if err != nil {
+			return err
+		}
+		if err := env.SetArrayElementAt(r.Array, position, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertDataValue converts a DATA value to the type of the variable it is read into
+func convertDataValue(variable string, value runtime.Value) (runtime.Value, error) {
+	if IsStringVariable(variable) {
+		if value.Type == runtime.NumericValue {
+			return runtime.NewStringValue(value.ToString()), nil
+		}
+		return value, nil
+	}
+
+	if value.Type == runtime.StringValue {
+		number, err := value.ToNumber()
+		if err != nil {
+			return runtime.Value{}, fmt.Errorf("cannot READ DATA '%s' into numeric variable %s", value.StrValue, variable)
+		}
+		return runtime.NewNumericValue(number), nil
+	}
+	return value, nil
+}
+
+// RestoreStatement represents a RESTORE statement that moves the READ cursor back
+// RESTORE goes back to the first DATA value; RESTORE n to the first value on or after line n
+type RestoreStatement struct {
+	LineNumber int // 0 means the start of the program
+}
+
+// NewRestoreStatement creates a new RESTORE statement; pass 0 to restore to the first DATA value
+func NewRestoreStatement(lineNumber int) *RestoreStatement {
+	return &RestoreStatement{LineNumber: lineNumber}
+}
+
+// Execute moves the data pool's cursor
+func (r *RestoreStatement) Execute(env *runtime.Environment) error {
+	env.RestoreData(r.LineNumber)
+	return nil
+}
+
+// LoadData fills the environment's data pool from the program's DATA statements, in line order
+// Any previous data is discarded and the read cursor starts at the first value
+func LoadData(program *Program, env *runtime.Environment) {
+	env.ClearData()
+	if program == nil {
+		return
+	}
+
+	for _, lineNumber := range program.Order {
+		for _, stmt := range lineStatements(program.Lines[lineNumber]) {
+			if data, ok := stmt.(*DataStatement); ok {
+				env.AddData(lineNumber, data.Values...)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadData(t *testing.T) {
+	program := newTestProgram(map[int]Statement{
+		10: NewReadStatement([]string{"A"}),
+		20: NewCompoundStatement([]Statement{
+			NewDataStatement([]runtime.Value{runtime.NewNumericValue(1), runtime.NewNumericValue(2)}),
+			printLiteral("x"),
+		}),
+		30: NewDataStatement([]runtime.Value{runtime.NewStringValue("three")}),
+	}, []int{10, 20, 30})
+	env := runtime.NewEnvironment()
+	env.AddData(5, runtime.NewNumericValue(99))
+
+	LoadData(program, env)
+
+	assert.Equal(t, []runtime.Value{runtime.NewNumericValue(1), runtime.NewNumericValue(2), runtime.NewStringValue("three")}, env.Data, "earlier data is discarded")
+	env.RestoreData(30)
+	value, err := env.ReadData()
+	require.NoError(t, err)
+	assert.Equal(t, runtime.NewStringValue("three"), value)
+}
+
+func TestReadStatement_Execute(t *testing.T) {
+	newEnv := func(values ...runtime.Value) *runtime.Environment {
+		env := runtime.NewEnvironment()
+		env.AddData(100, values...)
+		return env
+	}
+
+	t.Run("reads values into variables in order", func(t *testing.T) {
+		env := newEnv(runtime.NewNumericValue(1), runtime.NewStringValue("two"))
+		err := NewReadStatement([]string{"A", "B$"}).Execute(env)
+		require.NoError(t, err)
+		assert.Equal(t, runtime.NewNumericValue(1), env.GetVariable("A"))
+		assert.Equal(t, runtime.NewStringValue("two"), env.GetVariable("B$"))
+	})
+
+	t.Run("numeric strings are converted for numeric variables", func(t *testing.T) {
+		env := newEnv(runtime.NewStringValue(" 42 "))
+		require.NoError(t, NewReadStatement([]string{"A"}).Execute(env))
+		assert.Equal(t, runtime.NewNumericValue(42), env.GetVariable("A"))
+	})
+
+	t.Run("numbers are read into string variables as text", func(t *testing.T) {
+		env := newEnv(runtime.NewNumericValue(2.5))
+		require.NoError(t, NewReadStatement([]string{"A$"}).Execute(env))
+		assert.Equal(t, runtime.NewStringValue("2.5"), env.GetVariable("A$"))
+	})
+
+	t.Run("non-numeric string into numeric variable is an error", func(t *testing.T) {
+		err := NewReadStatement([]string{"A"}).Execute(newEnv(runtime.NewStringValue("abc")))
+		assert.EqualError(t, err, "cannot READ DATA 'abc' into numeric variable A")
+	})
+
+	t.Run("reading past the last value is an error", func(t *testing.T) {
+		err := NewReadStatement([]string{"A", "B"}).Execute(newEnv(runtime.NewNumericValue(1)))
+		assert.EqualError(t, err, "out of DATA")
+	})
+}
+
+func TestReadArrayStatement_Execute(t *testing.T) {
+	newEnv := func(values ...runtime.Value) *runtime.Environment {
+		env := runtime.NewEnvironment()
+		env.AddData(100, values...)
+		require.NoError(t, env.DimArray("A", []int{4}))
+		require.NoError(t, env.DimArray("N$", []int{1}))
+		return env
+	}
+	count := func(n float64) Expression { return NewLiteralExpression(runtime.NewNumericValue(n)) }
+
+	t.Run("fills consecutive elements from the first", func(t *testing.T) {
+		env := newEnv(runtime.NewNumericValue(1), runtime.NewNumericValue(2), runtime.NewNumericValue(3), runtime.NewNumericValue(4))
+		require.NoError(t, NewReadArrayStatement("A", count(3)).Execute(env))
+
+		elements, err := env.ArrayElements("A")
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 2, 3, 0, 0}, []float64{elements[0].NumValue, elements[1].NumValue,
+			elements[2].NumValue, elements[3].NumValue, elements[4].NumValue})
+		value, err := env.ReadData()
+		require.NoError(t, err)
+		assert.Equal(t, runtime.NewNumericValue(4), value, "the next READ goes on after the filled values")
+	})
+
+	t.Run("values are converted to the array type", func(t *testing.T) {
+		env := newEnv(runtime.NewNumericValue(2.5), runtime.NewStringValue("b"))
+		require.NoError(t, NewReadArrayStatement("N$", count(2)).Execute(env))
+		value, err := env.GetArrayElement("N$", []int{0})
+		require.NoError(t, err)
+		assert.Equal(t, runtime.NewStringValue("2.5"), value)
+	})
+
+	t.Run("running out of DATA is an error", func(t *testing.T) {
+		env := newEnv(runtime.NewNumericValue(1), runtime.NewNumericValue(2))
+		err := NewReadArrayStatement("A", count(3)).Execute(env)
+		assert.EqualError(t, err, "out of DATA")
+		value, _ := env.GetArrayElement("A", []int{1})
+		assert.Equal(t, 2.0, value.NumValue, "values read before running out are kept")
+	})
+
+	t.Run("reading past the last element is an error", func(t *testing.T) {
+		err := NewReadArrayStatement("A", count(6)).Execute(newEnv(runtime.NewNumericValue(1)))
+		assert.EqualError(t, err, "subscript out of range: cannot READ 6 values into array A of 5 elements")
+	})
+
+	t.Run("the array must be dimensioned", func(t *testing.T) {
+		err := NewReadArrayStatement("B", count(1)).Execute(newEnv(runtime.NewNumericValue(1)))
+		assert.EqualError(t, err, "array B not dimensioned")
+	})
+
+	t.Run("negative counts are an error", func(t *testing.T) {
+		err := NewReadArrayStatement("A", count(-1)).Execute(newEnv())
+		assert.EqualError(t, err, "READ count cannot be negative, got -1")
+	})
+}
+
+func TestRestoreStatement_Execute(t *testing.T) {
+	env := runtime.NewEnvironment()
+	env.AddData(100, runtime.NewNumericValue(1))
+	env.AddData(200, runtime.NewNumericValue(2))
+	read := NewReadStatement([]string{"A"})
+
+	require.NoError(t, read.Execute(env))
+	require.NoError(t, NewRestoreStatement(150).Execute(env))
+	require.NoError(t, read.Execute(env))
+	assert.Equal(t, runtime.NewNumericValue(2), env.GetVariable("A"))
+
+	require.NoError(t, NewRestoreStatement(0).Execute(env))
+	require.NoError(t, read.Execute(env))
+	assert.Equal(t, runtime.NewNumericValue(1), env.GetVariable("A"))
+}
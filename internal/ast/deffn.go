@@ -0,0 +1,66 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+	"strings"
+)
+
+// DEF FN
+// DEF FNSQ(X) = X * X defines a function of one parameter whose name starts with FN.
+// The definition takes effect when the DEF line runs; FNSQ(5) then evaluates X * X with
+// X set to 5, and any variable X keeps its own value once the call is over.
+
+// DefFnStatement represents a DEF FN statement defining a single-expression function
+type DefFnStatement struct {
+	Name      string
+	Parameter string
+	Body      Expression
+}
+
+// NewDefFnStatement creates a new DEF FN statement
+func NewDefFnStatement(name, parameter string, body Expression) *DefFnStatement {
+	return &DefFnStatement{
+		Name:      name,
+		Parameter: parameter,
+		Body:      body,
+	}
+}
+
+// Execute registers the function in the environment, replacing any earlier definition
+func (d *DefFnStatement) Execute(env *runtime.Environment) error {
+	env.DefineFunction(runtime.UserFunction{
+		Name:      d.Name,
+		Parameter: d.Parameter,
+		Body:      d.Body,
+	})
+	return nil
+}
+
+// IsUserFunctionName reports whether a name is that of a DEF FN function, which starts with FN
+func IsUserFunctionName(name string) bool {
+	return len(name) > 2 && strings.HasPrefix(strings.ToUpper(name), "FN")
+}
+
+// callUserFunction calls a DEF FN function with the arguments of a function call
+func callUserFunction(function runtime.UserFunction, f *FunctionCallExpression, env *runtime.Environment) (runtime.Value, error) {
+	if len(f.Args) != 1 {
+		return runtime.Value{}, fmt.Errorf("function %s expects 1 argument(s), got %d", f.Name, len(f.Args))
+	}
+
+	if err := env.EnterExpression(); err != nil {
+		return runtime.Value{}, err
+	}
+	defer env.LeaveExpression()
+
+	argument, err := f.Args[0].Evaluate(env)
+	if err != nil {
+		return runtime.Value{}, wrapEvaluationError(err, fmt.Sprintf("error evaluating argument 0 for function %s", f.Name))
+	}
+
+	result, err := function.Call(argument, env)
+	if err != nil {
+		return runtime.Value{}, wrapEvaluationError(err, fmt.Sprintf("error calling function %s", f.Name))
+	}
+	return result, nil
+}
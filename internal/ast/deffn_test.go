@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefFnStatement_Execute(t *testing.T) {
+	square := NewBinaryExpression(NewVariableExpression("X"), "*", NewVariableExpression("X"))
+
+	t.Run("defined function is used in an expression", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		require.NoError(t, NewDefFnStatement("FNSQ", "X", square).Execute(env))
+
+		call := NewBinaryExpression(NewFunctionCallExpression("fnsq", []Expression{num(5)}), "+", num(1))
+		value, err := call.Evaluate(env)
+		require.NoError(t, err)
+		assert.Equal(t, 26.0, value.NumValue)
+	})
+
+	t.Run("parameter shadows a global of the same name", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.SetVariable("X", runtime.NewNumericValue(7))
+		require.NoError(t, NewDefFnStatement("FNSQ", "X", square).Execute(env))
+
+		value, err := NewFunctionCallExpression("FNSQ", []Expression{num(3)}).Evaluate(env)
+		require.NoError(t, err)
+		assert.Equal(t, 9.0, value.NumValue)
+		assert.Equal(t, 7.0, env.GetVariable("X").NumValue, "the global gets its value back")
+
+		_, err = NewFunctionCallExpression("FNSQ", []Expression{str("a")}).Evaluate(env)
+		assert.Error(t, err)
+		assert.Equal(t, 7.0, env.GetVariable("X").NumValue, "the global gets its value back after an error too")
+	})
+
+	t.Run("parameter does not leave a variable behind", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		require.NoError(t, NewDefFnStatement("FNSQ", "X", square).Execute(env))
+
+		_, err := NewFunctionCallExpression("FNSQ", []Expression{num(3)}).Evaluate(env)
+		require.NoError(t, err)
+		assert.NotContains(t, env.Variables, "X")
+	})
+
+	t.Run("undefined function", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+
+		_, err := NewFunctionCallExpression("FNSQ", []Expression{num(3)}).Evaluate(env)
+		assert.EqualError(t, err, "undefined function: FNSQ")
+	})
+
+	t.Run("wrong number of arguments", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		require.NoError(t, NewDefFnStatement("FNSQ", "X", square).Execute(env))
+
+		_, err := NewFunctionCallExpression("FNSQ", []Expression{num(3), num(4)}).Evaluate(env)
+		assert.EqualError(t, err, "function FNSQ expects 1 argument(s), got 2")
+	})
+}
@@ -0,0 +1,120 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+)
+
+// DO and LOOP
+// A DO loop is tested at the top with DO WHILE cond or DO UNTIL cond, at the bottom
+// with LOOP WHILE cond or LOOP UNTIL cond, or not at all, leaving GOTO or END to leave
+// it. A loop tested at the bottom always runs its body at least once.
+
+// DoStatement represents a DO statement opening a loop, with an optional condition tested before each pass
+type DoStatement struct {
+	Condition Expression // nil when the loop is not tested at the top
+	Until     bool       // DO UNTIL rather than DO WHILE
+	LineNum   int
+	Program   *Program // Searched for the matching LOOP when the condition ends the loop at once
+}
+
+// NewDoStatement creates a new DO statement on the given line; pass a nil condition for a plain DO
+func NewDoStatement(condition Expression, until bool, lineNum int) *DoStatement {
+	return &DoStatement{
+		Condition: condition,
+		Until:     until,
+		LineNum:   lineNum,
+	}
+}
+
+// Execute enters the loop, or jumps past the matching LOOP when the condition ends it before the first pass
+func (d *DoStatement) Execute(env *runtime.Environment) error {
+	if d.Condition != nil {
+		repeat, err := loopRepeats(d.Condition, d.Until, env)
+		if err != nil {
+			return fmt.Errorf("error evaluating DO condition: %w", err)
+		}
+		if !repeat {
+			exit, found := loopExit(d.Program, d.LineNum, d, isDo, isLoop)
+			if !found {
+				return fmt.Errorf("DO without LOOP")
+			}
+			env.ResumeAt(exit)
+			return nil
+		}
+	}
+
+	env.DoLoops = append(env.DoLoops, runtime.DoLoopState{
+		LineNum:   d.LineNum,
+		Body:      env.Statement + 1,
+		Condition: d.Condition,
+		Until:     d.Until,
+	})
+	return nil
+}
+
+// LoopStatement represents a LOOP statement closing the innermost DO loop, with an optional condition
+type LoopStatement struct {
+	Condition Expression // nil when the loop is not tested at the bottom
+	Until     bool       // LOOP UNTIL rather than LOOP WHILE
+}
+
+// NewLoopStatement creates a new LOOP statement; pass a nil condition for a plain LOOP
+func NewLoopStatement(condition Expression, until bool) *LoopStatement {
+	return &LoopStatement{
+		Condition: condition,
+		Until:     until,
+	}
+}
+
+// Execute tests the loop's condition, wherever it was written, and jumps back to the
+// start of its body while it lets the loop go on; a loop without a condition always goes on
+func (l *LoopStatement) Execute(env *runtime.Environment) error {
+	if len(env.DoLoops) == 0 {
+		return fmt.Errorf("LOOP without DO")
+	}
+
+	loop := env.DoLoops[len(env.DoLoops)-1]
+	condition, until, context := loop.Condition, loop.Until, "DO"
+	if l.Condition != nil {
+		if loop.Condition != nil {
+			return fmt.Errorf("DO at line %d and its LOOP cannot both have a condition", loop.LineNum)
+		}
+		condition, until, context = l.Condition, l.Until, "LOOP"
+	}
+
+	repeat := true
+	if condition != nil {
+		var err error
+		repeat, err = loopRepeats(condition, until, env)
+		if err != nil {
+			return fmt.Errorf("error evaluating %s condition: %w", context, err)
+		}
+	}
+
+	if repeat {
+		env.ResumeAt(runtime.ResumePoint{Line: loop.LineNum, Statement: loop.Body})
+	} else {
+		env.DoLoops = env.DoLoops[:len(env.DoLoops)-1]
+	}
+	return nil
+}
+
+// MatchingLoop returns the line of the LOOP that closes the DO on the given line
+// DO loops nested inside are skipped along with their own LOOP
+func MatchingLoop(program *Program, doLine int) (int, bool) {
+	exit, found := loopExit(program, doLine, nil, isDo, isLoop)
+	return exit.Line, found
+}
+
+func isDo(stmt Statement) bool   { _, ok := stmt.(*DoStatement); return ok }
+func isLoop(stmt Statement) bool { _, ok := stmt.(*LoopStatement); return ok }
+
+// loopRepeats reports whether a DO loop goes on: WHILE while the condition holds, UNTIL until it does
+func loopRepeats(condition runtime.LoopCondition, until bool, env *runtime.Environment) (bool, error) {
+	value, err := condition.Evaluate(env)
+	if err != nil {
+		return false, err
+	}
+	return IsTrue(value) != until, nil
+}
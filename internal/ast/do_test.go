@@ -0,0 +1,88 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoStatement_Execute(t *testing.T) {
+	below3 := NewComparisonExpression(NewVariableExpression("I"), "<", num(3))
+
+	t.Run("plain DO enters the loop", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		require.NoError(t, NewDoStatement(nil, false, 20).Execute(env))
+		require.Len(t, env.DoLoops, 1)
+		assert.Equal(t, 20, env.DoLoops[0].LineNum)
+		assert.Nil(t, env.DoLoops[0].Condition)
+	})
+
+	t.Run("DO UNTIL with a true condition jumps past the matching LOOP", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		do := NewDoStatement(below3, true, 20)
+		newTestProgram(map[int]Statement{
+			20: do,
+			30: NewDoStatement(nil, false, 30),
+			40: NewLoopStatement(nil, false),
+			50: NewLoopStatement(nil, false),
+			60: printLiteral("after"),
+		}, []int{20, 30, 40, 50, 60})
+		env.ProgramCounter = 20
+
+		require.NoError(t, do.Execute(env))
+		assert.Empty(t, env.DoLoops)
+		assert.True(t, env.Jumped)
+		assert.Equal(t, runtime.ResumePoint{Line: 50, Statement: 1}, env.JumpTarget, "goes on after the outer LOOP")
+	})
+
+	t.Run("DO WHILE with a false condition and no LOOP", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.SetVariable("I", runtime.NewNumericValue(3))
+		do := NewDoStatement(below3, false, 20)
+		newTestProgram(map[int]Statement{20: do}, []int{20})
+
+		assert.EqualError(t, do.Execute(env), "DO without LOOP")
+	})
+}
+
+func TestLoopStatement_Execute(t *testing.T) {
+	below3 := NewComparisonExpression(NewVariableExpression("I"), "<", num(3))
+
+	t.Run("LOOP UNTIL", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		require.NoError(t, NewDoStatement(nil, false, 20).Execute(env))
+
+		env.ProgramCounter = 50
+		require.NoError(t, NewLoopStatement(NewComparisonExpression(NewVariableExpression("I"), ">=", num(3)), true).Execute(env))
+		assert.Equal(t, 20, env.ProgramCounter, "jumps back until the condition holds")
+
+		env.SetVariable("I", runtime.NewNumericValue(3))
+		env.ProgramCounter = 50
+		require.NoError(t, NewLoopStatement(NewComparisonExpression(NewVariableExpression("I"), ">=", num(3)), true).Execute(env))
+		assert.Equal(t, 50, env.ProgramCounter)
+		assert.Empty(t, env.DoLoops)
+	})
+
+	t.Run("LOOP tests the condition written after DO", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		require.NoError(t, NewDoStatement(below3, false, 20).Execute(env))
+
+		env.ProgramCounter = 50
+		require.NoError(t, NewLoopStatement(nil, false).Execute(env))
+		assert.Equal(t, 20, env.ProgramCounter)
+
+		env.SetVariable("I", runtime.NewNumericValue(3))
+		require.NoError(t, NewLoopStatement(nil, false).Execute(env))
+		assert.Empty(t, env.DoLoops)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		assert.EqualError(t, NewLoopStatement(nil, false).Execute(env), "LOOP without DO")
+
+		require.NoError(t, NewDoStatement(below3, false, 20).Execute(env))
+		assert.EqualError(t, NewLoopStatement(below3, true).Execute(env), "DO at line 20 and its LOOP cannot both have a condition")
+	})
+}
@@ -653,4 +653,47 @@ func TestImprovedErrorMessages(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "error evaluating parenthesized expression")
 	})
-}
\ No newline at end of file
+}
+
+func TestExpressionDepthLimit(t *testing.T) {
+	// nest wraps a literal in the given number of parentheses
+	nest := func(depth int) Expression {
+		var expr Expression = NewLiteralExpression(runtime.NewNumericValue(1))
+		for i := 0; i < depth; i++ {
+			expr = NewParenthesesExpression(expr)
+		}
+		return expr
+	}
+
+	t.Run("too deep nesting returns a clean error", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+
+		_, err := nest(runtime.DefaultMaxExpressionDepth + 1).Evaluate(env)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, runtime.ErrExpressionTooComplex)
+		assert.Equal(t, "expression too complex", err.Error())
+	})
+
+	t.Run("nesting up to the limit evaluates", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+
+		result, err := nest(runtime.DefaultMaxExpressionDepth).Evaluate(env)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, result.NumValue)
+	})
+
+	t.Run("limit is configurable and depth is restored after an error", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.MaxExpressionDepth = 3
+
+		sum := NewBinaryExpression(nest(1), OpAdd, nest(3)) // the right operand reaches depth 4
+		_, err := sum.Evaluate(env)
+		assert.ErrorIs(t, err, runtime.ErrExpressionTooComplex)
+
+		result, err := nest(3).Evaluate(env)
+		require.NoError(t, err, "a failed evaluation should not leave the depth counter raised")
+		assert.Equal(t, 1.0, result.NumValue)
+	})
+}
@@ -0,0 +1,59 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"strings"
+)
+
+// FormatExpression renders an expression in canonical, fully parenthesized form
+// Every binary operation and comparison is wrapped in parentheses, so the output shows
+// exactly how the parser grouped the operands: 2+3*4 renders as (2 + (3 * 4)).
+// Source parentheses are not repeated, since the grouping is already explicit.
+func FormatExpression(expr Expression) string {
+	switch e := expr.(type) {
+	case *LiteralExpression:
+		if e.Value.Type == runtime.StringValue {
+			return "\"" + e.Value.StrValue + "\""
+		}
+		return e.Value.String()
+	case *VariableExpression:
+		return e.Name
+	case *ArrayElementExpression:
+		return e.Name + formatArgumentList(e.Indexes)
+	case *BinaryExpression:
+		return "(" + FormatExpression(e.Left) + " " + e.Operator + " " + FormatExpression(e.Right) + ")"
+	case *ComparisonExpression:
+		return "(" + FormatExpression(e.Left) + " " + e.Operator + " " + FormatExpression(e.Right) + ")"
+	case *LogicalExpression:
+		return "(" + FormatExpression(e.Left) + " " + e.Operator + " " + FormatExpression(e.Right) + ")"
+	case *NotExpression:
+		return "(NOT " + FormatExpression(e.Operand) + ")"
+	case *ParenthesesExpression:
+		return FormatExpression(e.Expression)
+	case *FunctionCallExpression:
+		return e.Name + formatArgumentList(e.Args)
+	case *SprintExpression:
+		return "SPRINT$" + formatArgumentList(e.Expressions)
+	case *ArrayListExpression:
+		return e.Name + "()"
+	case *TabExpression:
+		return "TAB(" + FormatExpression(e.Column) + ")"
+	case *SpcExpression:
+		return "SPC(" + FormatExpression(e.Count) + ")"
+	default:
+		return "?"
+	}
+}
+
+// formatArgumentList renders a parenthesized, comma separated argument list
+// Functions without arguments (such as RND) render without parentheses
+func formatArgumentList(args []Expression) string {
+	if len(args) == 0 {
+		return ""
+	}
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = FormatExpression(arg)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
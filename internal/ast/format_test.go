@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatExpression(t *testing.T) {
+	t.Run("string literals are quoted", func(t *testing.T) {
+		expr := NewLiteralExpression(runtime.NewStringValue("HI"))
+		assert.Equal(t, `"HI"`, FormatExpression(expr))
+	})
+
+	t.Run("source parentheses are not repeated", func(t *testing.T) {
+		sum := NewBinaryExpression(
+			NewLiteralExpression(runtime.NewNumericValue(1)), OpAdd, NewVariableExpression("A"))
+		assert.Equal(t, "(1 + A)", FormatExpression(NewParenthesesExpression(NewParenthesesExpression(sum))))
+	})
+
+	t.Run("function calls list their arguments", func(t *testing.T) {
+		call := NewFunctionCallExpression("MID$", []Expression{
+			NewVariableExpression("S$"),
+			NewLiteralExpression(runtime.NewNumericValue(2)),
+			NewLiteralExpression(runtime.NewNumericValue(3)),
+		})
+		assert.Equal(t, "MID$(S$, 2, 3)", FormatExpression(call))
+		assert.Equal(t, "RND", FormatExpression(NewFunctionCallExpression("RND", nil)))
+	})
+}
@@ -8,6 +8,49 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// joinFunction accepts any number of arguments and joins them, to test variadic calls
+type joinFunction struct{}
+
+func (f *joinFunction) Name() string  { return "JOIN$" }
+func (f *joinFunction) ArgCount() int { return VariadicArgCount }
+func (f *joinFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	joined := ""
+	for _, arg := range args {
+		joined += arg.ToString()
+	}
+	return runtime.NewStringValue(joined), nil
+}
+
+func TestFunctionCallExpression_Variadic(t *testing.T) {
+	env := runtime.NewEnvironment()
+
+	for _, count := range []int{0, 1, 4} {
+		args := make([]Expression, count)
+		for i := range args {
+			args[i] = NewLiteralExpression(runtime.NewNumericValue(float64(i + 1)))
+		}
+		expr := &FunctionCallExpression{Name: "JOIN$", Args: args, Function: &joinFunction{}}
+
+		result, err := expr.Evaluate(env)
+
+		require.NoError(t, err, "a variadic function accepts %d arguments", count)
+		assert.Equal(t, "1234"[:count], result.StrValue, "all arguments are passed to Call in order")
+	}
+
+	t.Run("INSTR takes an optional start position", func(t *testing.T) {
+		expr := NewFunctionCallExpression("INSTR", []Expression{
+			NewLiteralExpression(runtime.NewNumericValue(3)),
+			NewLiteralExpression(runtime.NewStringValue("BANANA")),
+			NewLiteralExpression(runtime.NewStringValue("AN")),
+		})
+
+		result, err := expr.Evaluate(env)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4.0, result.NumValue)
+	})
+}
+
 // Test for FunctionCallExpression struct and BuiltinFunction interface
 func TestFunctionCallExpression_Evaluate(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -240,7 +283,7 @@ func TestBuiltinFunctionInterface(t *testing.T) {
 		require.NotNil(t, fn)
 		
 		assert.Equal(t, "RND", fn.Name())
-		assert.Equal(t, 0, fn.ArgCount())
+		assert.Equal(t, VariadicArgCount, fn.ArgCount())
 		
 		// Test function call
 		args := []runtime.Value{}
@@ -0,0 +1,103 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FunctionLibrary is a named collection of built-in functions that are registered together
+type FunctionLibrary struct {
+	Name      string
+	Functions []BuiltinFunction
+}
+
+// NewFunctionLibrary creates a library with the given name and functions
+func NewFunctionLibrary(name string, functions ...BuiltinFunction) *FunctionLibrary {
+	return &FunctionLibrary{
+		Name:      name,
+		Functions: functions,
+	}
+}
+
+// FunctionRegistry holds the built-in functions programs may call, by upper-case name
+// Each interpreter owns one, so a library registered for one interpreter is not seen by others.
+// The parser resolves calls with it, so running a program never looks functions up
+type FunctionRegistry struct {
+	mu        sync.RWMutex // Lets libraries be registered while another goroutine parses
+	functions map[string]BuiltinFunction
+	libraries map[string]string // Library each function was registered by
+}
+
+// NewFunctionRegistry creates a registry holding the standard math and string libraries
+func NewFunctionRegistry() *FunctionRegistry {
+	registry := &FunctionRegistry{
+		functions: make(map[string]BuiltinFunction),
+		libraries: make(map[string]string),
+	}
+	for _, library := range standardLibraries() {
+		if err := registry.RegisterLibrary(library); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// RegisterLibrary adds every function in the library to the registry
+// Nothing is registered if any function name is already taken, so a library is never half-installed
+func (r *FunctionRegistry) RegisterLibrary(library *FunctionLibrary) error {
+	if library == nil || library.Name == "" {
+		return fmt.Errorf("function library must have a name")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, fn := range library.Functions {
+		name := strings.ToUpper(fn.Name())
+		if owner, exists := r.libraries[name]; exists {
+			return fmt.Errorf("function %s is already registered by library %s", name, owner)
+		}
+		if seen[name] {
+			return fmt.Errorf("function %s appears twice in library %s", name, library.Name)
+		}
+		seen[name] = true
+	}
+
+	for _, fn := range library.Functions {
+		name := strings.ToUpper(fn.Name())
+		r.functions[name] = fn
+		r.libraries[name] = library.Name
+	}
+	return nil
+}
+
+// Lookup returns the function with the given name (case-insensitive), or nil if there is none
+func (r *FunctionRegistry) Lookup(name string) BuiltinFunction {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.functions[strings.ToUpper(name)]
+}
+
+// Library returns the name of the library a function was registered by
+// The second result is false if no function with that name is registered
+func (r *FunctionRegistry) Library(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	library, exists := r.libraries[strings.ToUpper(name)]
+	return library, exists
+}
+
+// Names returns the names of all registered functions in alphabetical order
+func (r *FunctionRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.functions))
+	for name := range r.functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
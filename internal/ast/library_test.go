@@ -0,0 +1,120 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scaleFunction multiplies its single numeric argument by a fixed factor
+type scaleFunction struct {
+	name   string
+	factor float64
+}
+
+func (f *scaleFunction) Name() string  { return f.name }
+func (f *scaleFunction) ArgCount() int { return 1 }
+func (f *scaleFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	return runtime.NewNumericValue(args[0].NumValue * f.factor), nil
+}
+
+func TestRegisterLibrary(t *testing.T) {
+	env := runtime.NewEnvironment()
+	registry := NewFunctionRegistry()
+	library := NewFunctionLibrary("math2",
+		&scaleFunction{name: "DOUBLE", factor: 2},
+		&scaleFunction{name: "TRIPLE", factor: 3},
+	)
+
+	require.NoError(t, registry.RegisterLibrary(library))
+
+	for name, expected := range map[string]float64{"DOUBLE": 14, "triple": 21} {
+		call := NewFunctionCallExpression(name, []Expression{NewLiteralExpression(runtime.NewNumericValue(7))})
+		call.Function = registry.Lookup(name)
+		result, err := call.Evaluate(env)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result.NumValue)
+
+		owner, found := registry.Library(name)
+		assert.True(t, found)
+		assert.Equal(t, "math2", owner)
+	}
+}
+
+func TestRegisterLibrary_BuiltinLibraries(t *testing.T) {
+	registry := NewFunctionRegistry()
+
+	owner, found := registry.Library("abs")
+	assert.True(t, found)
+	assert.Equal(t, "math", owner)
+
+	owner, _ = registry.Library("mid$")
+	assert.Equal(t, "string", owner)
+
+	_, found = registry.Library("NOSUCH")
+	assert.False(t, found)
+}
+
+func TestRegisterLibrary_Conflicts(t *testing.T) {
+	t.Run("name already registered leaves the registry unchanged", func(t *testing.T) {
+		registry := NewFunctionRegistry()
+		library := NewFunctionLibrary("clash",
+			&scaleFunction{name: "HALVE", factor: 0.5},
+			&scaleFunction{name: "ABS", factor: -1},
+		)
+
+		err := registry.RegisterLibrary(library)
+		assert.EqualError(t, err, "function ABS is already registered by library math")
+		assert.Nil(t, registry.Lookup("HALVE"))
+		owner, _ := registry.Library("ABS")
+		assert.Equal(t, "math", owner)
+	})
+
+	t.Run("duplicate name within a library", func(t *testing.T) {
+		registry := NewFunctionRegistry()
+		library := NewFunctionLibrary("twice",
+			&scaleFunction{name: "QUAD", factor: 4},
+			&scaleFunction{name: "QUAD", factor: 4},
+		)
+
+		assert.Error(t, registry.RegisterLibrary(library))
+		assert.Nil(t, registry.Lookup("QUAD"))
+	})
+
+	t.Run("library without a name", func(t *testing.T) {
+		registry := NewFunctionRegistry()
+		assert.Error(t, registry.RegisterLibrary(NewFunctionLibrary("")))
+		assert.Error(t, registry.RegisterLibrary(nil))
+	})
+}
+
+func TestFunctionRegistry_PerInstance(t *testing.T) {
+	extended := NewFunctionRegistry()
+	require.NoError(t, extended.RegisterLibrary(NewFunctionLibrary("math2", &scaleFunction{name: "DOUBLE", factor: 2})))
+
+	assert.NotNil(t, extended.Lookup("DOUBLE"))
+	assert.Nil(t, NewFunctionRegistry().Lookup("DOUBLE"), "a new registry only has the standard libraries")
+	assert.False(t, IsFunctionRegistered("DOUBLE"), "the standard functions are not changed")
+	assert.Contains(t, extended.Names(), "ABS")
+}
+
+// Libraries may be registered while another goroutine parses with the registry; run with -race
+func TestRegisterLibrary_WhileLookingUp(t *testing.T) {
+	registry := NewFunctionRegistry()
+	library := NewFunctionLibrary("parallel", &scaleFunction{name: "QUADRUPLE", factor: 4})
+
+	done := make(chan error)
+	go func() {
+		done <- registry.RegisterLibrary(library)
+	}()
+
+	for i := 0; i < 100; i++ {
+		assert.NotNil(t, registry.Lookup("ABS"))
+		registry.Lookup("QUADRUPLE")
+	}
+
+	require.NoError(t, <-done)
+	assert.NotNil(t, registry.Lookup("QUADRUPLE"))
+}
@@ -0,0 +1,140 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+)
+
+// Running a line
+// A line may hold several statements, after colons and inside IF branches. They are
+// numbered as runtime.ResumePoint describes, so that NEXT, WEND, LOOP and RETURN can
+// continue with the statement after the one that started the loop or called the
+// subroutine, even when it is on the same line.
+
+// ExecuteLine runs the statements of a line, starting at the given statement number
+// It clears env.Jumped first; when a statement transfers control, the rest of the line
+// is skipped and env.JumpTarget says where to go on
+func ExecuteLine(stmt Statement, env *runtime.Environment, from int) error {
+	env.Jumped = false
+	run := &lineRun{env: env, from: from}
+	return run.execute(stmt)
+}
+
+// lineRun runs the statements of one line, numbering them as it meets them
+type lineRun struct {
+	env  *runtime.Environment
+	next int // Number of the next statement met
+	from int // Statements numbered before this one are skipped
+}
+
+// execute runs a statement and any statements nested in it
+func (r *lineRun) execute(stmt Statement) error {
+	switch s := stmt.(type) {
+	case *CompoundStatement:
+		for _, inner := range s.Statements {
+			if err := r.execute(inner); err != nil {
+				return err
+			}
+			if r.env.Jumped || r.env.Halted {
+				return nil
+			}
+		}
+		return nil
+	case *IfStatement:
+		return r.executeIf(s)
+	}
+
+	number := r.next
+	r.next++
+	if number < r.from {
+		return nil
+	}
+	r.env.Statement = number
+	return stmt.Execute(r.env)
+}
+
+// executeIf tests the condition and runs the chosen branch; when the line resumes inside
+// a branch, that branch goes on without testing the condition again
+func (r *lineRun) executeIf(s *IfStatement) error {
+	number := r.next
+	elseNumber := number + 1 + StatementCount(s.ThenStatement)
+	end := elseNumber
+	if s.ElseStatement != nil {
+		end += 1 + StatementCount(s.ElseStatement)
+	}
+	defer func() { r.next = end }()
+
+	switch {
+	case number >= r.from:
+		r.env.Statement = number
+		condition, err := s.Condition.Evaluate(r.env)
+		if err != nil {
+			return fmt.Errorf("error evaluating IF condition: %w", err)
+		}
+		if s.isConditionTrue(condition) {
+			return r.executeThen(s, number+1)
+		}
+		return r.executeElse(s, elseNumber+1)
+	case r.from < elseNumber:
+		return r.executeThen(s, number+1)
+	case r.from > elseNumber:
+		return r.executeElse(s, elseNumber+1)
+	}
+	return nil // Resuming at ELSE, which ends the THEN branch
+}
+
+// executeThen runs the THEN branch, whose first statement has the given number
+func (r *lineRun) executeThen(s *IfStatement, first int) error {
+	r.next = first
+	if err := r.execute(s.ThenStatement); err != nil {
+		return fmt.Errorf("error executing THEN statement: %w", err)
+	}
+	return nil
+}
+
+// executeElse runs the ELSE branch, if any, whose first statement has the given number
+func (r *lineRun) executeElse(s *IfStatement, first int) error {
+	if s.ElseStatement == nil {
+		return nil
+	}
+	r.next = first
+	if err := r.execute(s.ElseStatement); err != nil {
+		return fmt.Errorf("error executing ELSE statement: %w", err)
+	}
+	return nil
+}
+
+// StatementCount returns how many statement numbers a statement takes on its line,
+// counting those nested in it as runtime.ResumePoint describes
+func StatementCount(stmt Statement) int {
+	switch s := stmt.(type) {
+	case nil:
+		return 0
+	case *CompoundStatement:
+		count := 0
+		for _, inner := range s.Statements {
+			count += StatementCount(inner)
+		}
+		return count
+	case *IfStatement:
+		count := 1 + StatementCount(s.ThenStatement)
+		if s.ElseStatement != nil {
+			count += 1 + StatementCount(s.ElseStatement)
+		}
+		return count
+	}
+	return 1
+}
+
+// statementAfter returns where execution goes on after a top-level statement of a line,
+// as found by lineStatements
+func statementAfter(program *Program, lineNumber int, target Statement) runtime.ResumePoint {
+	number := 0
+	for _, stmt := range lineStatements(program.Lines[lineNumber]) {
+		number += StatementCount(stmt)
+		if stmt == target {
+			break
+		}
+	}
+	return runtime.ResumePoint{Line: lineNumber, Statement: number}
+}
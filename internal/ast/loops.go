@@ -0,0 +1,106 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FOR/NEXT pairing analysis
+// ForNextLinks pre-scans a parsed Program to find which NEXT closes each FOR,
+// without executing it, so mispaired loops can be reported up front.
+
+// ForNextLinks maps each FOR line to the line of the NEXT that closes it
+// A NEXT without a variable closes the innermost open FOR; NEXT I closes the
+// innermost open FOR I, leaving any loops opened inside it unclosed
+func ForNextLinks(program *Program) (map[int]int, error) {
+	links := make(map[int]int)
+	if program == nil {
+		return links, nil
+	}
+
+	var open []openLoop
+	var unclosed []int
+
+	for _, lineNumber := range program.Order {
+		for _, stmt := range lineStatements(program.Lines[lineNumber]) {
+			switch s := stmt.(type) {
+			case *ForStatement:
+				open = append(open, openLoop{variable: NormalizeVariableName(s.Variable), line: lineNumber})
+			case *NextStatement:
+				index := closedLoop(open, s)
+				if index < 0 {
+					return nil, fmt.Errorf("NEXT without FOR at line %d", lineNumber)
+				}
+				links[open[index].line] = lineNumber
+				for _, inner := range open[index+1:] {
+					unclosed = append(unclosed, inner.line)
+				}
+				open = open[:index]
+			}
+		}
+	}
+
+	for _, loop := range open {
+		unclosed = append(unclosed, loop.line)
+	}
+	if len(unclosed) > 0 {
+		sort.Ints(unclosed)
+		return nil, fmt.Errorf("FOR without NEXT at line %d", unclosed[0])
+	}
+	return links, nil
+}
+
+// CheckExplicitNext reports the first NEXT written without a variable
+// A bare NEXT silently closes the innermost open loop, which hides mistakes in
+// nesting; strict mode rejects it, naming the loop it would have closed
+func CheckExplicitNext(program *Program) error {
+	if program == nil {
+		return nil
+	}
+
+	var open []openLoop
+	for _, lineNumber := range program.Order {
+		for _, stmt := range lineStatements(program.Lines[lineNumber]) {
+			switch s := stmt.(type) {
+			case *ForStatement:
+				open = append(open, openLoop{variable: NormalizeVariableName(s.Variable), line: lineNumber})
+			case *NextStatement:
+				index := closedLoop(open, s)
+				if s.Variable == "" && index >= 0 {
+					return fmt.Errorf("NEXT without a variable at line %d closes FOR %s at line %d; write NEXT %s",
+						lineNumber, open[index].variable, open[index].line, open[index].variable)
+				}
+				if index >= 0 {
+					open = open[:index]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// openLoop is a FOR whose NEXT has not been seen yet during a scan
+type openLoop struct {
+	variable string
+	line     int
+}
+
+// closedLoop returns the index in open of the loop a NEXT closes, or -1 if it closes none
+// A NEXT without a variable closes the innermost loop; NEXT I the innermost FOR I
+func closedLoop(open []openLoop, next *NextStatement) int {
+	index := len(open) - 1
+	if next.Variable != "" {
+		for index >= 0 && open[index].variable != NormalizeVariableName(next.Variable) {
+			index--
+		}
+	}
+	return index
+}
+
+// lineStatements returns the statements of a line in order, looking inside colon-separated lines
+func lineStatements(stmt Statement) []Statement {
+	if compound, ok := stmt.(*CompoundStatement); ok {
+		return compound.Statements
+	}
+	return []Statement{stmt}
+}
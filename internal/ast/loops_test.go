@@ -0,0 +1,101 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func forLoop(variable string, line int) Statement {
+	one := NewLiteralExpression(runtime.NewNumericValue(1))
+	return NewForStatement(variable, one, one, one, line)
+}
+
+func TestForNextLinks(t *testing.T) {
+	t.Run("nested loops pair inside out", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: forLoop("I", 10),
+			20: forLoop("J", 20),
+			30: printLiteral("x"),
+			40: NewNextStatement("J"),
+			50: forLoop("K", 50),
+			60: NewNextStatement(""),
+			70: NewNextStatement("I"),
+		}, []int{10, 20, 30, 40, 50, 60, 70})
+
+		links, err := ForNextLinks(program)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{10: 70, 20: 40, 50: 60}, links)
+	})
+
+	t.Run("loops on a colon-separated line", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: NewCompoundStatement([]Statement{forLoop("I", 10), printLiteral("x"), NewNextStatement("I")}),
+		}, []int{10})
+
+		links, err := ForNextLinks(program)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{10: 10}, links)
+	})
+
+	t.Run("NEXT without FOR", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: forLoop("I", 10),
+			20: NewNextStatement("J"),
+		}, []int{10, 20})
+
+		_, err := ForNextLinks(program)
+
+		assert.EqualError(t, err, "NEXT without FOR at line 20")
+	})
+
+	t.Run("FOR without NEXT", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: forLoop("I", 10),
+			20: forLoop("J", 20),
+			30: NewNextStatement("I"),
+		}, []int{10, 20, 30})
+
+		_, err := ForNextLinks(program)
+
+		assert.EqualError(t, err, "FOR without NEXT at line 20")
+	})
+}
+
+func TestCheckExplicitNext(t *testing.T) {
+	bareNested := newTestProgram(map[int]Statement{
+		10: forLoop("I", 10),
+		20: forLoop("J", 20),
+		30: printLiteral("x"),
+		40: NewNextStatement(""),
+		50: NewNextStatement(""),
+	}, []int{10, 20, 30, 40, 50})
+
+	t.Run("lenient pairing accepts bare NEXTs", func(t *testing.T) {
+		links, err := ForNextLinks(bareNested)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{10: 50, 20: 40}, links)
+	})
+
+	t.Run("strict check rejects the first bare NEXT and names its loop", func(t *testing.T) {
+		err := CheckExplicitNext(bareNested)
+
+		assert.EqualError(t, err, "NEXT without a variable at line 40 closes FOR J at line 20; write NEXT J")
+	})
+
+	t.Run("explicit variables pass", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: forLoop("I", 10),
+			20: NewCompoundStatement([]Statement{forLoop("j", 20), NewNextStatement("J")}),
+			30: NewNextStatement("I"),
+		}, []int{10, 20, 30})
+
+		assert.NoError(t, CheckExplicitNext(program))
+		assert.NoError(t, CheckExplicitNext(nil))
+	})
+}
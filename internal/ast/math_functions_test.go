@@ -2,7 +2,10 @@ package ast
 
 import (
 	"basic-interpreter/internal/runtime"
+	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -106,6 +109,67 @@ func TestAbsFunction_ErrorCases(t *testing.T) {
 	})
 }
 
+// Test MAX and MIN function implementations
+func TestMaxMinFunctions_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	maxFn := GetBuiltinFunction("MAX")
+	require.NotNil(t, maxFn)
+	minFn := GetBuiltinFunction("MIN")
+	require.NotNil(t, minFn)
+
+	testCases := []struct {
+		name    string
+		a, b    float64
+		larger  float64
+		smaller float64
+	}{
+		{name: "first is larger", a: 5, b: 3, larger: 5, smaller: 3},
+		{name: "second is larger", a: 2.5, b: 7.5, larger: 7.5, smaller: 2.5},
+		{name: "equal arguments", a: 4, b: 4, larger: 4, smaller: 4},
+		{name: "negative values", a: -2, b: -8, larger: -2, smaller: -8},
+		{name: "negative and positive", a: -1, b: 1, larger: 1, smaller: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{runtime.NewNumericValue(tc.a), runtime.NewNumericValue(tc.b)}
+
+			result, err := maxFn.Call(args, env)
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.Equal(t, tc.larger, result.NumValue)
+
+			result, err = minFn.Call(args, env)
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.Equal(t, tc.smaller, result.NumValue)
+		})
+	}
+}
+
+func TestMaxMinFunctions_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+
+	for _, name := range []string{"MAX", "MIN"} {
+		fn := GetBuiltinFunction(name)
+		require.NotNil(t, fn)
+
+		t.Run(name+" wrong argument count", func(t *testing.T) {
+			_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1)}, env)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected 2 arguments")
+		})
+
+		t.Run(name+" wrong argument type - string", func(t *testing.T) {
+			_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1), runtime.NewStringValue("2")}, env)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "second argument must be numeric")
+		})
+	}
+}
+
 // Test INT function implementation
 func TestIntFunction_Call(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -138,14 +202,24 @@ func TestIntFunction_Call(t *testing.T) {
 			expected: -5.0,
 		},
 		{
-			name:     "negative decimal",
+			name:     "negative decimal rounds down",
 			input:    -5.7,
-			expected: -5.0,
+			expected: -6.0,
+		},
+		{
+			name:     "positive half",
+			input:    2.5,
+			expected: 2.0,
+		},
+		{
+			name:     "negative half rounds down",
+			input:    -2.5,
+			expected: -3.0,
 		},
 		{
 			name:     "negative decimal close to next integer",
 			input:    -5.99,
-			expected: -5.0,
+			expected: -6.0,
 		},
 		{
 			name:     "zero",
@@ -160,7 +234,7 @@ func TestIntFunction_Call(t *testing.T) {
 		{
 			name:     "negative small decimal",
 			input:    -0.9,
-			expected: 0.0,
+			expected: -1.0,
 		},
 		{
 			name:     "large positive number",
@@ -170,7 +244,7 @@ func TestIntFunction_Call(t *testing.T) {
 		{
 			name:     "large negative number",
 			input:    -999999.999,
-			expected: -999999.0,
+			expected: -1000000.0,
 		},
 	}
 
@@ -186,6 +260,46 @@ func TestIntFunction_Call(t *testing.T) {
 	}
 }
 
+// Test FIX function implementation, contrasted with INT
+func TestFixFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fix := GetBuiltinFunction("FIX")
+	require.NotNil(t, fix)
+	intFn := GetBuiltinFunction("INT")
+	require.NotNil(t, intFn)
+
+	testCases := []struct {
+		name  string
+		input float64
+		fix   float64
+		int   float64
+	}{
+		{name: "positive decimal", input: 3.7, fix: 3, int: 3},
+		{name: "negative decimal", input: -3.7, fix: -3, int: -4},
+		{name: "negative small decimal", input: -0.2, fix: 0, int: -1},
+		{name: "negative integer", input: -3, fix: -3, int: -3},
+		{name: "zero", input: 0, fix: 0, int: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{runtime.NewNumericValue(tc.input)}
+
+			result, err := fix.Call(args, env)
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.Equal(t, tc.fix, result.NumValue, "FIX")
+
+			result, err = intFn.Call(args, env)
+			require.NoError(t, err)
+			assert.Equal(t, tc.int, result.NumValue, "INT")
+		})
+	}
+
+	_, err := fix.Call([]runtime.Value{runtime.NewStringValue("1")}, env)
+	assert.ErrorContains(t, err, "argument must be numeric")
+}
+
 func TestIntFunction_ErrorCases(t *testing.T) {
 	env := runtime.NewEnvironment()
 	fn := GetBuiltinFunction("INT")
@@ -219,6 +333,164 @@ func TestIntFunction_ErrorCases(t *testing.T) {
 	})
 }
 
+func TestSqrFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("SQR")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		input    float64
+		expected float64
+	}{
+		{
+			name:     "perfect square",
+			input:    16,
+			expected: 4,
+		},
+		{
+			name:     "large perfect square",
+			input:    1000000,
+			expected: 1000,
+		},
+		{
+			name:     "non-integer result",
+			input:    2,
+			expected: 1.4142135623730951,
+		},
+		{
+			name:     "fraction",
+			input:    0.25,
+			expected: 0.5,
+		},
+		{
+			name:     "zero",
+			input:    0,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := fn.Call([]runtime.Value{runtime.NewNumericValue(tc.input)}, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.InDelta(t, tc.expected, result.NumValue, 1e-12)
+		})
+	}
+}
+
+func TestSqrFunction_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("SQR")
+	require.NotNil(t, fn)
+
+	t.Run("negative argument", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(-4)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "SQR of negative number")
+	})
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 1 argument")
+	})
+
+	t.Run("wrong argument type - string", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("4")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "argument must be numeric")
+	})
+}
+
+func TestLogFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("LOG")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		input    float64
+		expected float64
+	}{
+		{name: "one", input: 1, expected: 0},
+		{name: "e", input: math.E, expected: 1},
+		{name: "ten", input: 10, expected: 2.302585092994046},
+		{name: "fraction", input: 0.5, expected: -0.6931471805599453},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := fn.Call([]runtime.Value{runtime.NewNumericValue(tc.input)}, env)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, result.NumValue, 1e-12)
+		})
+	}
+
+	for _, input := range []float64{0, -1} {
+		t.Run(fmt.Sprintf("non-positive argument %g", input), func(t *testing.T) {
+			_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(input)}, env)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "LOG of non-positive number")
+		})
+	}
+}
+
+func TestExpFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("EXP")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		input    float64
+		expected float64
+	}{
+		{name: "zero", input: 0, expected: 1},
+		{name: "one", input: 1, expected: math.E},
+		{name: "negative", input: -1, expected: 0.36787944117144233},
+		{name: "two", input: 2, expected: 7.38905609893065},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := fn.Call([]runtime.Value{runtime.NewNumericValue(tc.input)}, env)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, result.NumValue, 1e-12)
+		})
+	}
+
+	t.Run("wrong argument type - string", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("1")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "argument must be numeric")
+	})
+}
+
+func TestLogExpRoundTrip(t *testing.T) {
+	for _, x := range []float64{0.001, 0.5, 1, 2, 42, 12345.678} {
+		t.Run(fmt.Sprintf("EXP(LOG(%g))", x), func(t *testing.T) {
+			expr := NewFunctionCallExpression("EXP", []Expression{
+				NewFunctionCallExpression("LOG", []Expression{NewLiteralExpression(runtime.NewNumericValue(x))}),
+			})
+
+			result, err := expr.Evaluate(runtime.NewEnvironment())
+
+			require.NoError(t, err)
+			assert.InDelta(t, x, result.NumValue, x*1e-12)
+		})
+	}
+}
+
 // Test RND function implementation and random number generator state management
 func TestRndFunction_Call(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -235,6 +507,19 @@ func TestRndFunction_Call(t *testing.T) {
 		assert.Less(t, result.NumValue, 1.0)
 	})
 
+	t.Run("accepts and ignores an argument, as in RND(1)", func(t *testing.T) {
+		withArg := runtime.NewEnvironment()
+		withArg.SetRandomSeed(12345)
+		withoutArg := runtime.NewEnvironment()
+		withoutArg.SetRandomSeed(12345)
+
+		result, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1)}, withArg)
+		require.NoError(t, err)
+		expected, err := fn.Call(nil, withoutArg)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
 	t.Run("generates different values on multiple calls", func(t *testing.T) {
 		args := []runtime.Value{}
 		
@@ -314,11 +599,17 @@ func TestRndFunction_ErrorCases(t *testing.T) {
 	require.NotNil(t, fn)
 
 	t.Run("wrong argument count - too many", func(t *testing.T) {
-		args := []runtime.Value{runtime.NewNumericValue(5)}
+		args := []runtime.Value{runtime.NewNumericValue(1), runtime.NewNumericValue(5)}
 		_, err := fn.Call(args, env)
 
+		assert.EqualError(t, err, "RND function expected 0 to 1 arguments, got 2")
+	})
+
+	t.Run("string argument", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("1")}, env)
+
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "expected 0 arguments")
+		assert.Contains(t, err.Error(), "must be numeric")
 	})
 }
 
@@ -420,7 +711,7 @@ func TestMathematicalFunctionsBoundaryValues(t *testing.T) {
 			{"large positive", 1e10, 1e10},
 			{"large negative", -1e10, -1e10},
 			{"just below 1", 0.9999999, 0.0},
-			{"just above -1", -0.9999999, 0.0},
+			{"just above -1", -0.9999999, -1.0},
 		}
 
 		for _, tc := range testCases {
@@ -445,7 +736,6 @@ func TestMathematicalFunctionArgumentValidation(t *testing.T) {
 	}{
 		{"ABS", 1},
 		{"INT", 1},
-		{"RND", 0},
 	}
 
 	for _, tc := range testCases {
@@ -456,29 +746,100 @@ func TestMathematicalFunctionArgumentValidation(t *testing.T) {
 			// Test correct argument count
 			assert.Equal(t, tc.expectedArgs, fn.ArgCount())
 
-			// Test with wrong number of arguments
-			if tc.expectedArgs > 0 {
-				// Test with no arguments when some are expected
-				args := []runtime.Value{}
-				_, err := fn.Call(args, env)
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), "expected")
-
-				// Test with too many arguments
-				args = make([]runtime.Value, tc.expectedArgs+1)
-				for i := range args {
-					args[i] = runtime.NewNumericValue(1.0)
-				}
-				_, err = fn.Call(args, env)
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), "expected")
-			} else {
-				// Test RND with arguments when none are expected
-				args := []runtime.Value{runtime.NewNumericValue(1.0)}
-				_, err := fn.Call(args, env)
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), "expected 0 arguments")
+			// Test with no arguments when some are expected
+			args := []runtime.Value{}
+			_, err := fn.Call(args, env)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected")
+
+			// Test with too many arguments
+			args = make([]runtime.Value, tc.expectedArgs+1)
+			for i := range args {
+				args[i] = runtime.NewNumericValue(1.0)
 			}
+			_, err = fn.Call(args, env)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected")
 		})
 	}
-}
\ No newline at end of file
+}
+
+// Test FRE function implementation
+func TestFreFunction_Call(t *testing.T) {
+	fn := GetBuiltinFunction("FRE")
+	require.NotNil(t, fn)
+
+	fre := func(t *testing.T, env *runtime.Environment, arg runtime.Value) float64 {
+		result, err := fn.Call([]runtime.Value{arg}, env)
+		require.NoError(t, err)
+		assert.Equal(t, runtime.NumericValue, result.Type)
+		return result.NumValue
+	}
+
+	t.Run("reflects the configured budget", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.MemoryBudget = 100
+		env.StringSpace = 50
+
+		assert.Equal(t, 100.0, fre(t, env, runtime.NewNumericValue(0)))
+		assert.Equal(t, 50.0, fre(t, env, runtime.NewStringValue("")))
+	})
+
+	t.Run("decreases as variables are added", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		before := fre(t, env, runtime.NewNumericValue(0))
+
+		env.SetVariable("A", runtime.NewNumericValue(1))
+		env.SetVariable("B", runtime.NewNumericValue(2))
+		assert.Equal(t, before-2, fre(t, env, runtime.NewNumericValue(0)))
+
+		env.SetVariable("A", runtime.NewNumericValue(3))
+		assert.Equal(t, before-2, fre(t, env, runtime.NewNumericValue(0)), "reassigning does not use more space")
+	})
+
+	t.Run("string space decreases with string contents", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		before := fre(t, env, runtime.NewStringValue(""))
+
+		env.SetVariable("S$", runtime.NewStringValue("HELLO"))
+		env.SetVariable("N", runtime.NewNumericValue(12345))
+		assert.Equal(t, before-5, fre(t, env, runtime.NewStringValue("")))
+	})
+
+	t.Run("arrays count every element", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		memory := fre(t, env, runtime.NewNumericValue(0))
+		stringSpace := fre(t, env, runtime.NewStringValue(""))
+
+		require.NoError(t, env.DimArray("B", []int{1000}))
+		require.NoError(t, env.DimArray("A$", []int{2}))
+		require.NoError(t, env.SetArrayElement("A$", []int{1}, runtime.NewStringValue("HELLO")))
+
+		assert.Equal(t, memory-1001-3, fre(t, env, runtime.NewNumericValue(0)), "DIM B(1000) holds 1001 values")
+		assert.Equal(t, stringSpace-5, fre(t, env, runtime.NewStringValue("")))
+	})
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{}, runtime.NewEnvironment())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 1 argument")
+	})
+}
+
+func TestTimerFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	env.Clock = func() time.Time {
+		return time.Date(2024, 3, 15, 1, 2, 3, 500_000_000, time.Local)
+	}
+	fn := GetBuiltinFunction("TIMER")
+	require.NotNil(t, fn)
+
+	result, err := fn.Call([]runtime.Value{}, env)
+
+	require.NoError(t, err)
+	assert.Equal(t, runtime.NumericValue, result.Type)
+	assert.Equal(t, 3723.5, result.NumValue, "seconds since midnight")
+
+	_, err = fn.Call([]runtime.Value{runtime.NewNumericValue(1)}, env)
+	assert.Error(t, err)
+}
@@ -0,0 +1,255 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Control-flow reference analysis helpers
+// These functions walk a parsed Program without executing it, so they can be
+// shared by static checks such as the linter and jump-target validation.
+
+// JumpTargets returns the line numbers a statement may transfer control to
+// Nested statements (such as the THEN and ELSE branches of an IF) are included
+func JumpTargets(stmt Statement) []int {
+	switch s := stmt.(type) {
+	case *GotoStatement:
+		return []int{s.LineNumber}
+	case *GosubStatement:
+		return []int{s.LineNumber}
+	case *IfStatement:
+		var targets []int
+		if s.ThenStatement != nil {
+			targets = append(targets, JumpTargets(s.ThenStatement)...)
+		}
+		if s.ElseStatement != nil {
+			targets = append(targets, JumpTargets(s.ElseStatement)...)
+		}
+		return targets
+	case *CompoundStatement:
+		var targets []int
+		for _, inner := range s.Statements {
+			targets = append(targets, JumpTargets(inner)...)
+		}
+		return targets
+	}
+	return nil
+}
+
+// FallsThrough reports whether execution can continue with the next line after the statement
+// Unconditional jumps, RETURN, END and STOP never fall through; everything else may,
+// including GOSUB, whose subroutine returns to the next line
+func FallsThrough(stmt Statement) bool {
+	switch s := stmt.(type) {
+	case *GotoStatement, *ReturnStatement, *EndStatement, *StopStatement:
+		return false
+	case *IfStatement:
+		// Without ELSE a false condition continues with the next line;
+		// with ELSE the line falls through if either branch does
+		if s.ElseStatement == nil || s.ThenStatement == nil {
+			return true
+		}
+		return FallsThrough(s.ThenStatement) || FallsThrough(s.ElseStatement)
+	case *CompoundStatement:
+		// The line ends at its first unconditional jump
+		for _, inner := range s.Statements {
+			if !FallsThrough(inner) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// CollectReferences builds a map from each jump target line to the lines that reference it
+// Source lines are listed in program order
+func CollectReferences(program *Program) map[int][]int {
+	references := make(map[int][]int)
+	if program == nil {
+		return references
+	}
+
+	for _, lineNumber := range program.Order {
+		for _, target := range JumpTargets(program.Lines[lineNumber]) {
+			references[target] = append(references[target], lineNumber)
+		}
+	}
+	return references
+}
+
+// MissingJump is a GOTO or GOSUB to a line that is not in the program
+type MissingJump struct {
+	Line   int // Line with the jump
+	Target int // Line it jumps to
+}
+
+// Error describes the jump, in the words used wherever a missing line is reported
+func (m MissingJump) Error() string {
+	return fmt.Sprintf("line %d jumps to line %d, which does not exist", m.Line, m.Target)
+}
+
+// MissingJumpTargets returns the jumps to lines that are not in the program, in program order
+func MissingJumpTargets(program *Program) []MissingJump {
+	if program == nil {
+		return nil
+	}
+
+	var missing []MissingJump
+	for _, lineNumber := range program.Order {
+		for _, target := range JumpTargets(program.Lines[lineNumber]) {
+			if _, exists := program.Lines[target]; !exists {
+				missing = append(missing, MissingJump{Line: lineNumber, Target: target})
+			}
+		}
+	}
+	return missing
+}
+
+// UnreachableLines returns the lines that no execution path starting at the first line can reach
+// A line is reachable if it is the first line, if a reachable line jumps to it,
+// or if the reachable line before it falls through
+func UnreachableLines(program *Program) []int {
+	if program == nil || len(program.Order) == 0 {
+		return nil
+	}
+
+	reachable := make(map[int]bool)
+	pending := []int{program.Order[0]}
+
+	for len(pending) > 0 {
+		lineNumber := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if reachable[lineNumber] {
+			continue
+		}
+		stmt, exists := program.Lines[lineNumber]
+		if !exists {
+			continue
+		}
+		reachable[lineNumber] = true
+
+		pending = append(pending, JumpTargets(stmt)...)
+		if FallsThrough(stmt) {
+			if next, ok := nextLineNumber(program, lineNumber); ok {
+				pending = append(pending, next)
+			}
+		}
+	}
+
+	var unreachable []int
+	for _, lineNumber := range program.Order {
+		if !reachable[lineNumber] {
+			unreachable = append(unreachable, lineNumber)
+		}
+	}
+	return unreachable
+}
+
+// Subroutine is a line that at least one GOSUB calls
+type Subroutine struct {
+	Line    int    // Line the subroutine starts at
+	Name    string // Label from the REM on the line or just before it; empty when there is none
+	Callers []int  // Lines with a GOSUB to it, in program order
+}
+
+// Subroutines returns the GOSUB targets of a program in line order
+// A subroutine is named by a REM comment either on its first line or on the line just
+// before it, with decorations such as dashes trimmed: 500 REM -- PrintReport names it PrintReport
+func Subroutines(program *Program) []Subroutine {
+	if program == nil {
+		return nil
+	}
+
+	callers := make(map[int][]int)
+	for _, lineNumber := range program.Order {
+		for _, target := range gosubTargets(program.Lines[lineNumber]) {
+			callers[target] = append(callers[target], lineNumber)
+		}
+	}
+
+	subroutines := make([]Subroutine, 0, len(callers))
+	for line, from := range callers {
+		subroutines = append(subroutines, Subroutine{Line: line, Name: subroutineName(program, line), Callers: from})
+	}
+	sort.Slice(subroutines, func(i, j int) bool { return subroutines[i].Line < subroutines[j].Line })
+	return subroutines
+}
+
+// SubroutineFallThrough is a line that runs on into a subroutine without calling it
+type SubroutineFallThrough struct {
+	Line       int // Last line before the subroutine that lets execution continue
+	Subroutine int // First line of the subroutine
+}
+
+// SubroutineFallThroughs finds subroutines that the code before them can run into
+// without a GOSUB, typically a main program missing its END. REM lines directly
+// before a subroutine are treated as its label, so the line before them is checked
+func SubroutineFallThroughs(program *Program) []SubroutineFallThrough {
+	var fallThroughs []SubroutineFallThrough
+	for _, sub := range Subroutines(program) {
+		idx := sort.SearchInts(program.Order, sub.Line)
+		for idx > 0 {
+			if _, isRem := program.Lines[program.Order[idx-1]].(*RemStatement); !isRem {
+				break
+			}
+			idx--
+		}
+		if idx > 0 && FallsThrough(program.Lines[program.Order[idx-1]]) {
+			fallThroughs = append(fallThroughs, SubroutineFallThrough{Line: program.Order[idx-1], Subroutine: sub.Line})
+		}
+	}
+	return fallThroughs
+}
+
+// gosubTargets returns the lines a statement calls with GOSUB, including inside IF branches and compounds
+func gosubTargets(stmt Statement) []int {
+	switch s := stmt.(type) {
+	case *GosubStatement:
+		return []int{s.LineNumber}
+	case *IfStatement:
+		targets := gosubTargets(s.ThenStatement)
+		if s.ElseStatement != nil {
+			targets = append(targets, gosubTargets(s.ElseStatement)...)
+		}
+		return targets
+	case *CompoundStatement:
+		var targets []int
+		for _, inner := range s.Statements {
+			targets = append(targets, gosubTargets(inner)...)
+		}
+		return targets
+	}
+	return nil
+}
+
+// subroutineName returns the label of the REM on the given line, or else on the line before it
+func subroutineName(program *Program, line int) string {
+	if rem, ok := program.Lines[line].(*RemStatement); ok {
+		return remLabel(rem)
+	}
+	idx := sort.SearchInts(program.Order, line)
+	if idx > 0 {
+		if rem, ok := program.Lines[program.Order[idx-1]].(*RemStatement); ok {
+			return remLabel(rem)
+		}
+	}
+	return ""
+}
+
+// remLabel returns the text of a comment without the dashes, stars and such that decorate it
+func remLabel(rem *RemStatement) string {
+	return strings.Trim(rem.Comment, " -=*#:")
+}
+
+// nextLineNumber returns the line that follows the given line in program order
+func nextLineNumber(program *Program, lineNumber int) (int, bool) {
+	idx := sort.SearchInts(program.Order, lineNumber)
+	if idx+1 < len(program.Order) && program.Order[idx] == lineNumber {
+		return program.Order[idx+1], true
+	}
+	return 0, false
+}
@@ -0,0 +1,223 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestProgram builds a program from line/statement pairs and wires GOTO references
+func newTestProgram(lines map[int]Statement, order []int) *Program {
+	program := &Program{Lines: lines, Order: order}
+	for _, stmt := range lines {
+		switch s := stmt.(type) {
+		case *GotoStatement:
+			s.Program = program
+		case *WhileStatement:
+			s.Program = program
+		case *DoStatement:
+			s.Program = program
+		}
+	}
+	return program
+}
+
+func printLiteral(text string) Statement {
+	return NewPrintStatement([]Expression{NewLiteralExpression(runtime.NewStringValue(text))}, nil)
+}
+
+func TestJumpTargets(t *testing.T) {
+	t.Run("GOTO targets its line", func(t *testing.T) {
+		assert.Equal(t, []int{50}, JumpTargets(NewGotoStatement(50, nil)))
+	})
+
+	t.Run("IF THEN GOTO targets its line", func(t *testing.T) {
+		condition := NewLiteralExpression(runtime.NewNumericValue(1))
+		stmt := NewIfStatement(condition, NewGotoStatement(70, nil))
+		assert.Equal(t, []int{70}, JumpTargets(stmt))
+	})
+
+	t.Run("GOSUB targets its subroutine", func(t *testing.T) {
+		assert.Equal(t, []int{200}, JumpTargets(NewGosubStatement(200, nil)))
+		assert.Empty(t, JumpTargets(NewReturnStatement()))
+	})
+
+	t.Run("IF THEN ELSE targets both branches", func(t *testing.T) {
+		condition := NewLiteralExpression(runtime.NewNumericValue(1))
+		stmt := NewIfElseStatement(condition, NewGotoStatement(70, nil), NewGotoStatement(90, nil))
+		assert.Equal(t, []int{70, 90}, JumpTargets(stmt))
+	})
+
+	t.Run("plain statements have no targets", func(t *testing.T) {
+		assert.Empty(t, JumpTargets(printLiteral("x")))
+		assert.Empty(t, JumpTargets(NewEndStatement()))
+	})
+}
+
+func TestFallsThrough(t *testing.T) {
+	assert.False(t, FallsThrough(NewGotoStatement(10, nil)))
+	assert.False(t, FallsThrough(NewEndStatement()))
+	assert.False(t, FallsThrough(NewStopStatement()))
+	assert.True(t, FallsThrough(printLiteral("x")))
+	assert.True(t, FallsThrough(NewGosubStatement(10, nil)), "the subroutine returns to the next line")
+	assert.False(t, FallsThrough(NewReturnStatement()))
+
+	condition := NewLiteralExpression(runtime.NewNumericValue(1))
+	assert.True(t, FallsThrough(NewIfStatement(condition, NewGotoStatement(10, nil))),
+		"a conditional jump may fall through when the condition is false")
+	assert.False(t, FallsThrough(NewIfElseStatement(condition, NewGotoStatement(10, nil), NewEndStatement())),
+		"neither branch continues with the next line")
+	assert.True(t, FallsThrough(NewIfElseStatement(condition, NewGotoStatement(10, nil), printLiteral("x"))))
+}
+
+func TestCollectReferences(t *testing.T) {
+	condition := NewLiteralExpression(runtime.NewNumericValue(1))
+	program := newTestProgram(map[int]Statement{
+		10: NewGotoStatement(40, nil),
+		20: NewIfStatement(condition, NewGotoStatement(40, nil)),
+		30: NewGotoStatement(10, nil),
+		40: NewEndStatement(),
+	}, []int{10, 20, 30, 40})
+
+	references := CollectReferences(program)
+
+	assert.Equal(t, []int{10, 20}, references[40])
+	assert.Equal(t, []int{30}, references[10])
+	assert.Empty(t, references[20])
+}
+
+func TestMissingJumpTargets(t *testing.T) {
+	condition := NewLiteralExpression(runtime.NewNumericValue(1))
+	program := newTestProgram(map[int]Statement{
+		10: NewGosubStatement(100, nil),
+		20: NewIfElseStatement(condition, NewGotoStatement(10, nil), NewGotoStatement(99, nil)),
+		30: NewGotoStatement(20, nil),
+		40: NewEndStatement(),
+	}, []int{10, 20, 30, 40})
+
+	assert.Equal(t, []MissingJump{{Line: 10, Target: 100}, {Line: 20, Target: 99}}, MissingJumpTargets(program))
+	assert.Empty(t, MissingJumpTargets(nil))
+}
+
+func TestUnreachableLines(t *testing.T) {
+	t.Run("line after GOTO without inbound reference is flagged", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: printLiteral("start"),
+			20: NewGotoStatement(40, nil),
+			30: printLiteral("skipped"),
+			40: printLiteral("end"),
+		}, []int{10, 20, 30, 40})
+
+		assert.Equal(t, []int{30}, UnreachableLines(program))
+	})
+
+	t.Run("line after END without inbound reference is flagged", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: printLiteral("start"),
+			20: NewEndStatement(),
+			30: printLiteral("dead"),
+		}, []int{10, 20, 30})
+
+		assert.Equal(t, []int{30}, UnreachableLines(program))
+	})
+
+	t.Run("GOTO target after a GOTO is not flagged", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: NewGotoStatement(30, nil),
+			20: NewGotoStatement(40, nil),
+			30: NewGotoStatement(20, nil),
+			40: NewEndStatement(),
+		}, []int{10, 20, 30, 40})
+
+		assert.Empty(t, UnreachableLines(program))
+	})
+
+	t.Run("IF THEN target is not flagged", func(t *testing.T) {
+		condition := NewLiteralExpression(runtime.NewNumericValue(1))
+		program := newTestProgram(map[int]Statement{
+			10: NewIfStatement(condition, NewGotoStatement(40, nil)),
+			20: NewEndStatement(),
+			30: printLiteral("dead"),
+			40: printLiteral("target"),
+		}, []int{10, 20, 30, 40})
+
+		assert.Equal(t, []int{30}, UnreachableLines(program))
+	})
+
+	t.Run("whole dead block is flagged", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: NewGotoStatement(50, nil),
+			20: printLiteral("dead 1"),
+			30: printLiteral("dead 2"),
+			40: NewGotoStatement(20, nil),
+			50: NewEndStatement(),
+		}, []int{10, 20, 30, 40, 50})
+
+		assert.Equal(t, []int{20, 30, 40}, UnreachableLines(program),
+			"lines referenced only from unreachable code are unreachable too")
+	})
+
+	t.Run("empty program has no warnings", func(t *testing.T) {
+		assert.Empty(t, UnreachableLines(&Program{Lines: map[int]Statement{}}))
+		assert.Empty(t, UnreachableLines(nil))
+	})
+}
+
+func TestCompoundStatementReferences(t *testing.T) {
+	condition := NewLiteralExpression(runtime.NewNumericValue(1))
+	compound := NewCompoundStatement([]Statement{
+		printLiteral("x"),
+		NewIfStatement(condition, NewGotoStatement(30, nil)),
+		NewGotoStatement(40, nil),
+	})
+
+	assert.Equal(t, []int{30, 40}, JumpTargets(compound))
+	assert.False(t, FallsThrough(compound), "the line ends with an unconditional GOTO")
+	assert.True(t, FallsThrough(NewCompoundStatement([]Statement{printLiteral("a"), printLiteral("b")})))
+}
+
+func TestSubroutines(t *testing.T) {
+	program := newTestProgram(map[int]Statement{
+		10:  NewGosubStatement(500, nil),
+		20:  NewCompoundStatement([]Statement{NewGosubStatement(600, nil), NewGosubStatement(500, nil)}),
+		30:  NewGotoStatement(40, nil),
+		40:  NewEndStatement(),
+		500: NewRemStatement("-- PrintReport --"),
+		510: NewReturnStatement(),
+		600: NewReturnStatement(),
+	}, []int{10, 20, 30, 40, 500, 510, 600})
+
+	assert.Equal(t, []Subroutine{
+		{Line: 500, Name: "PrintReport", Callers: []int{10, 20}},
+		{Line: 600, Name: "", Callers: []int{20}},
+	}, Subroutines(program), "GOTO targets are not subroutines, and a RETURN before the line is not a label")
+}
+
+func TestSubroutineFallThroughs(t *testing.T) {
+	t.Run("main program without END runs into a labelled subroutine", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10:  NewGosubStatement(500, nil),
+			20:  printLiteral("done"),
+			490: NewRemStatement("-- PrintReport"),
+			500: printLiteral("report"),
+			510: NewReturnStatement(),
+		}, []int{10, 20, 490, 500, 510})
+
+		assert.Equal(t, []SubroutineFallThrough{{Line: 20, Subroutine: 500}}, SubroutineFallThroughs(program))
+	})
+
+	t.Run("END and RETURN keep subroutines apart", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10:  NewGosubStatement(500, nil),
+			20:  NewGosubStatement(600, nil),
+			30:  NewEndStatement(),
+			500: printLiteral("first"),
+			510: NewReturnStatement(),
+			600: printLiteral("second"),
+			610: NewReturnStatement(),
+		}, []int{10, 20, 30, 500, 510, 600, 610})
+
+		assert.Empty(t, SubroutineFallThroughs(program))
+	})
+}
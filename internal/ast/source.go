@@ -0,0 +1,420 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Source text
+// Every statement and expression renders itself back to BASIC in canonical form: keywords and names in
+// upper case, single spaces around operators and ", " between list items. Expressions
+// keep the parentheses written in the source and gain only those needed to parse back
+// to the same tree, so parsing a statement's String() gives an equivalent statement.
+
+// Operator precedence, from loosest to tightest, as the parser applies it
+const (
+	precedenceOr = iota + 1
+	precedenceAnd
+	precedenceNot
+	precedenceComparison
+	precedenceAdditive
+	precedenceMultiplicative
+	precedencePower
+	precedencePrimary // Literals, names, calls, parentheses and unary minus
+)
+
+// SourceExpression renders an expression as it would be written in a program
+func SourceExpression(expr Expression) string {
+	text, _ := sourceExpression(expr)
+	return text
+}
+
+// String renders the literal as a constant
+func (l *LiteralExpression) String() string { return SourceExpression(l) }
+
+// String renders the variable name
+func (v *VariableExpression) String() string { return SourceExpression(v) }
+
+// String renders the array element with its indexes
+func (a *ArrayElementExpression) String() string { return SourceExpression(a) }
+
+// String renders the array name followed by empty parentheses
+func (a *ArrayListExpression) String() string { return SourceExpression(a) }
+
+// String renders the expression in its parentheses
+func (p *ParenthesesExpression) String() string { return SourceExpression(p) }
+
+// String renders the function call with its arguments
+func (f *FunctionCallExpression) String() string { return SourceExpression(f) }
+
+// String renders SPRINT$ with its print list
+func (s *SprintExpression) String() string { return SourceExpression(s) }
+
+// String renders TAB with its column
+func (t *TabExpression) String() string { return SourceExpression(t) }
+
+// String renders SPC with its count
+func (s *SpcExpression) String() string { return SourceExpression(s) }
+
+// String renders the arithmetic operation, or -x for a negation
+func (b *BinaryExpression) String() string { return SourceExpression(b) }
+
+// String renders the comparison
+func (c *ComparisonExpression) String() string { return SourceExpression(c) }
+
+// String renders the AND or OR operation
+func (l *LogicalExpression) String() string { return SourceExpression(l) }
+
+// String renders NOT with its operand
+func (n *NotExpression) String() string { return SourceExpression(n) }
+
+// sourceExpression renders an expression and reports the precedence of its outermost operator
+func sourceExpression(expr Expression) (string, int) {
+	switch e := expr.(type) {
+	case *LiteralExpression:
+		return sourceValue(e.Value), precedencePrimary
+	case *VariableExpression:
+		return NormalizeVariableName(e.Name), precedencePrimary
+	case *ArrayElementExpression:
+		return NormalizeVariableName(e.Name) + sourceArgumentList(e.Indexes), precedencePrimary
+	case *ParenthesesExpression:
+		return "(" + SourceExpression(e.Expression) + ")", precedencePrimary
+	case *FunctionCallExpression:
+		name := strings.ToUpper(e.Name)
+		if len(e.Args) == 0 && e.Function == nil && !IsFunctionRegistered(name) {
+			return name + "()", precedencePrimary // Without parentheses it would read as a variable
+		}
+		return name + sourceArgumentList(e.Args), precedencePrimary
+	case *SprintExpression:
+		return "SPRINT$(" + sourcePrintList(e.Expressions, e.Separators) + ")", precedencePrimary
+	case *ArrayListExpression:
+		return NormalizeVariableName(e.Name) + "()", precedencePrimary
+	case *TabExpression:
+		return "TAB(" + SourceExpression(e.Column) + ")", precedencePrimary
+	case *SpcExpression:
+		return "SPC(" + SourceExpression(e.Count) + ")", precedencePrimary
+	case *BinaryExpression:
+		if operand, ok := unaryMinusOperand(e); ok {
+			return "-" + operand, precedencePrimary
+		}
+		precedence := precedenceAdditive
+		switch e.Operator {
+		case OpMultiply, OpDivide, OpIntDivide, OpModulo:
+			precedence = precedenceMultiplicative
+		case OpPower:
+			precedence = precedencePower
+		}
+		return sourceOperation(e.Left, e.Operator, e.Right, precedence), precedence
+	case *ComparisonExpression:
+		return sourceOperation(e.Left, e.Operator, e.Right, precedenceComparison), precedenceComparison
+	case *LogicalExpression:
+		precedence := precedenceOr
+		if e.Operator == OpAnd {
+			precedence = precedenceAnd
+		}
+		return sourceOperation(e.Left, e.Operator, e.Right, precedence), precedence
+	case *NotExpression:
+		return OpNot + " " + sourceOperand(e.Operand, precedenceNot), precedenceNot
+	default:
+		return "?", precedencePrimary
+	}
+}
+
+// unaryMinusOperand recognizes the 0 - x the parser builds for -x, returning x when -x
+// parses back to the same tree, which holds when x is itself a primary expression
+func unaryMinusOperand(e *BinaryExpression) (string, bool) {
+	zero, ok := e.Left.(*LiteralExpression)
+	if e.Operator != OpSubtract || !ok || zero.Value.Type != runtime.NumericValue || zero.Value.NumValue != 0 {
+		return "", false
+	}
+	operand, precedence := sourceExpression(e.Right)
+	return operand, precedence == precedencePrimary
+}
+
+// sourceOperation renders a binary operation, parenthesizing operands that bind more loosely
+// Power groups to the right and every other operator to the left, so an operand of the same
+// precedence on the other side needs parentheses; comparisons do not chain at all
+func sourceOperation(left Expression, operator string, right Expression, precedence int) string {
+	leftMin, rightMin := precedence, precedence+1
+	switch precedence {
+	case precedencePower:
+		leftMin, rightMin = precedence+1, precedence
+	case precedenceComparison:
+		leftMin = precedence + 1
+	}
+	return sourceOperand(left, leftMin) + " " + operator + " " + sourceOperand(right, rightMin)
+}
+
+// sourceOperand renders an operand, in parentheses when it binds more loosely than minimum
+func sourceOperand(expr Expression, minimum int) string {
+	text, precedence := sourceExpression(expr)
+	if precedence < minimum {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+// sourceArgumentList renders a parenthesized, comma separated argument list
+// Functions without arguments (such as RND) render without parentheses
+func sourceArgumentList(args []Expression) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return "(" + sourceExpressionList(args) + ")"
+}
+
+// sourceExpressionList renders expressions separated by ", "
+func sourceExpressionList(exprs []Expression) string {
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = SourceExpression(expr)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sourcePrintList renders the items of a PRINT or SPRINT$ list with the separator after each
+// A separator after the last item is kept, since it changes where the next output goes
+func sourcePrintList(exprs []Expression, separators []rune) string {
+	var text strings.Builder
+	for i, expr := range exprs {
+		if i > 0 {
+			text.WriteString(" ")
+		}
+		text.WriteString(SourceExpression(expr))
+		if i < len(separators) {
+			text.WriteRune(separators[i])
+		}
+	}
+	return text.String()
+}
+
+// sourceValue renders a constant: strings in quotes, numbers in full precision
+// Numbers of ordinary size are written without an exponent, as they would be typed
+func sourceValue(value runtime.Value) string {
+	if value.Type == runtime.StringValue {
+		return "\"" + value.StrValue + "\""
+	}
+	magnitude := math.Abs(value.NumValue)
+	if magnitude != 0 && (magnitude < 1e-6 || magnitude >= 1e21) {
+		return strings.ToUpper(strconv.FormatFloat(value.NumValue, 'g', -1, 64))
+	}
+	return strconv.FormatFloat(value.NumValue, 'f', -1, 64)
+}
+
+// String renders the assignment, without the optional LET
+func (a *AssignmentStatement) String() string {
+	return NormalizeVariableName(a.Variable) + " = " + SourceExpression(a.Expression)
+}
+
+// String renders the assignment to an array element
+func (a *ArrayAssignmentStatement) String() string {
+	return NormalizeVariableName(a.Name) + sourceArgumentList(a.Indexes) + " = " + SourceExpression(a.Expression)
+}
+
+// String renders the PRINT statement with its separators
+func (p *PrintStatement) String() string {
+	if len(p.Expressions) == 0 {
+		return "PRINT"
+	}
+	text := "PRINT " + sourcePrintList(p.Expressions, p.Separators)
+	if p.TrailingSemicolon {
+		text += ";"
+	}
+	return text
+}
+
+// String renders the INPUT or LINE INPUT statement with its prompt and range
+func (i *InputStatement) String() string {
+	text := "INPUT "
+	if i.Line {
+		text = "LINE INPUT "
+	}
+	if i.Prompt != "" {
+		text += "\"" + i.Prompt + "\"; "
+	}
+	text += NormalizeVariableName(i.Variable)
+	if i.Range != nil {
+		text += " RANGE " + SourceExpression(i.Range.Low) + " TO " + SourceExpression(i.Range.High)
+	}
+	return text
+}
+
+// String renders END with its exit code, if any
+func (e *EndStatement) String() string {
+	if e.ExitCode == nil {
+		return "END"
+	}
+	return "END " + SourceExpression(e.ExitCode)
+}
+
+// String renders STOP
+func (s *StopStatement) String() string {
+	return "STOP"
+}
+
+// String renders RANDOMIZE with its seed, if any
+func (r *RandomizeStatement) String() string {
+	if r.Seed == nil {
+		return "RANDOMIZE"
+	}
+	return "RANDOMIZE " + SourceExpression(r.Seed)
+}
+
+// String renders PAUSE with its prompt, if any
+func (p *PauseStatement) String() string {
+	if p.Prompt == "" {
+		return "PAUSE"
+	}
+	return "PAUSE \"" + p.Prompt + "\""
+}
+
+// String renders the statements separated by colons
+func (c *CompoundStatement) String() string {
+	parts := make([]string, len(c.Statements))
+	for i, stmt := range c.Statements {
+		parts[i] = stmt.String()
+	}
+	return strings.Join(parts, ": ")
+}
+
+// String renders ASSERT with its condition
+func (a *AssertStatement) String() string {
+	return "ASSERT " + SourceExpression(a.Condition)
+}
+
+// String renders SHELL with its command and the variable capturing the output, if any
+func (s *ShellStatement) String() string {
+	text := "SHELL " + SourceExpression(s.Command)
+	if s.Variable != "" {
+		text += ", " + NormalizeVariableName(s.Variable)
+	}
+	return text
+}
+
+// String renders the comment as a REM statement
+func (r *RemStatement) String() string {
+	if r.Comment == "" {
+		return "REM"
+	}
+	return "REM " + r.Comment
+}
+
+// String renders IF with its THEN branch and its ELSE branch, if any
+func (i *IfStatement) String() string {
+	text := "IF " + SourceExpression(i.Condition) + " THEN " + i.ThenStatement.String()
+	if i.ElseStatement != nil {
+		text += " ELSE " + i.ElseStatement.String()
+	}
+	return text
+}
+
+// String renders FOR, leaving out the default STEP of 1
+func (f *ForStatement) String() string {
+	text := fmt.Sprintf("FOR %s = %s TO %s", NormalizeVariableName(f.Variable), SourceExpression(f.StartExpr), SourceExpression(f.EndExpr))
+	if step, ok := f.StepExpr.(*LiteralExpression); ok && step.Value.Type == runtime.NumericValue && step.Value.NumValue == 1 {
+		return text
+	}
+	return text + " STEP " + SourceExpression(f.StepExpr)
+}
+
+// String renders NEXT with its variable, if any
+func (n *NextStatement) String() string {
+	if n.Variable == "" {
+		return "NEXT"
+	}
+	return "NEXT " + NormalizeVariableName(n.Variable)
+}
+
+// String renders GOTO with its target line
+func (g *GotoStatement) String() string {
+	return fmt.Sprintf("GOTO %d", g.LineNumber)
+}
+
+// String renders GOSUB with its target line
+func (g *GosubStatement) String() string {
+	return fmt.Sprintf("GOSUB %d", g.LineNumber)
+}
+
+// String renders RETURN
+func (r *ReturnStatement) String() string {
+	return "RETURN"
+}
+
+// String renders DIM with each array and its sizes
+func (d *DimStatement) String() string {
+	parts := make([]string, len(d.Arrays))
+	for i, array := range d.Arrays {
+		parts[i] = NormalizeVariableName(array.Name) + sourceArgumentList(array.Sizes)
+	}
+	return "DIM " + strings.Join(parts, ", ")
+}
+
+// String renders DATA with its constants
+func (d *DataStatement) String() string {
+	parts := make([]string, len(d.Values))
+	for i, value := range d.Values {
+		parts[i] = sourceValue(value)
+	}
+	return "DATA " + strings.Join(parts, ", ")
+}
+
+// String renders READ with its variables
+func (r *ReadStatement) String() string {
+	parts := make([]string, len(r.Variables))
+	for i, variable := range r.Variables {
+		parts[i] = NormalizeVariableName(variable)
+	}
+	return "READ " + strings.Join(parts, ", ")
+}
+
+// String renders READ with the array and count it fills
+func (r *ReadArrayStatement) String() string {
+	return "READ " + NormalizeVariableName(r.Array) + "(), " + SourceExpression(r.Count)
+}
+
+// String renders RESTORE with its line, if any
+func (r *RestoreStatement) String() string {
+	if r.LineNumber == 0 {
+		return "RESTORE"
+	}
+	return fmt.Sprintf("RESTORE %d", r.LineNumber)
+}
+
+// String renders the function definition
+func (d *DefFnStatement) String() string {
+	return fmt.Sprintf("DEF %s(%s) = %s", strings.ToUpper(d.Name), NormalizeVariableName(d.Parameter), SourceExpression(d.Body))
+}
+
+// String renders DO with its condition, if any
+func (d *DoStatement) String() string {
+	return "DO" + sourceLoopCondition(d.Condition, d.Until)
+}
+
+// String renders LOOP with its condition, if any
+func (l *LoopStatement) String() string {
+	return "LOOP" + sourceLoopCondition(l.Condition, l.Until)
+}
+
+// sourceLoopCondition renders the WHILE or UNTIL clause of DO or LOOP, or nothing without a condition
+func sourceLoopCondition(condition Expression, until bool) string {
+	if condition == nil {
+		return ""
+	}
+	if until {
+		return " UNTIL " + SourceExpression(condition)
+	}
+	return " WHILE " + SourceExpression(condition)
+}
+
+// String renders WHILE with its condition
+func (w *WhileStatement) String() string {
+	return "WHILE " + SourceExpression(w.Condition)
+}
+
+// String renders WEND
+func (w *WendStatement) String() string {
+	return "WEND"
+}
@@ -0,0 +1,70 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceExpression(t *testing.T) {
+	t.Run("operands that bind more loosely are parenthesized", func(t *testing.T) {
+		leftPower := NewBinaryExpression(NewBinaryExpression(num(2), OpPower, num(3)), OpPower, num(2))
+		assert.Equal(t, "(2 ^ 3) ^ 2", SourceExpression(leftPower))
+
+		rightSubtraction := NewBinaryExpression(num(10), OpSubtract, NewBinaryExpression(num(4), OpSubtract, num(3)))
+		assert.Equal(t, "10 - (4 - 3)", SourceExpression(rightSubtraction))
+
+		product := NewBinaryExpression(NewBinaryExpression(num(1), OpAdd, num(2)), OpMultiply, NewVariableExpression("a"))
+		assert.Equal(t, "(1 + 2) * A", SourceExpression(product))
+
+		chained := NewComparisonExpression(NewComparisonExpression(num(1), "<", num(2)), "=", num(-1))
+		assert.Equal(t, "(1 < 2) = -1", SourceExpression(chained))
+
+		negated := NewNotExpression(NewLogicalExpression(num(1), OpAnd, num(0)))
+		assert.Equal(t, "NOT (1 AND 0)", SourceExpression(negated))
+	})
+
+	t.Run("zero minus a primary reads as unary minus", func(t *testing.T) {
+		assert.Equal(t, "-X", SourceExpression(NewBinaryExpression(num(0), OpSubtract, NewVariableExpression("X"))))
+		assert.Equal(t, "0 - X * 2", SourceExpression(NewBinaryExpression(num(0), OpSubtract,
+			NewBinaryExpression(NewVariableExpression("X"), OpMultiply, num(2)))))
+	})
+
+	t.Run("numbers keep their full precision", func(t *testing.T) {
+		assert.Equal(t, "123456789012", SourceExpression(num(123456789012)))
+		assert.Equal(t, "0.1", SourceExpression(num(0.1)))
+		assert.Equal(t, "1E-07", SourceExpression(num(1e-7)))
+		assert.Equal(t, "1E+21", SourceExpression(num(1e21)))
+	})
+
+	t.Run("calls of unknown functions keep their parentheses", func(t *testing.T) {
+		assert.Equal(t, "RND", SourceExpression(NewFunctionCallExpression("rnd", nil)))
+		assert.Equal(t, "FOO()", SourceExpression(NewFunctionCallExpression("foo", nil)))
+	})
+
+	t.Run("expressions render themselves", func(t *testing.T) {
+		sum := NewBinaryExpression(NewVariableExpression("a"), OpAdd,
+			NewBinaryExpression(NewVariableExpression("b"), OpMultiply, num(2)))
+		assert.Equal(t, "A + B * 2", sum.String())
+		assert.Equal(t, `LEFT$(N$, 1)`, NewFunctionCallExpression("left$", []Expression{NewVariableExpression("n$"), num(1)}).String())
+		assert.Equal(t, `"HI"`, str("HI").String())
+	})
+}
+
+func TestStatementString(t *testing.T) {
+	loop := NewForStatement("i", num(1), num(10), num(1), 10)
+	assert.Equal(t, "FOR I = 1 TO 10", loop.String(), "the default step is left out")
+
+	stmt := NewIfElseStatement(
+		NewComparisonExpression(NewVariableExpression("A"), "=", str("yes")),
+		NewCompoundStatement([]Statement{
+			NewPrintStatement([]Expression{str("ok")}, nil),
+			NewGotoStatement(100, nil),
+		}),
+		NewEndStatement())
+	assert.Equal(t, `IF A = "yes" THEN PRINT "ok": GOTO 100 ELSE END`, stmt.String())
+
+	data := NewDataStatement([]runtime.Value{runtime.NewNumericValue(-1.5), runtime.NewStringValue("x")})
+	assert.Equal(t, `DATA -1.5, "x"`, data.String())
+}
@@ -3,9 +3,12 @@ package ast
 import (
 	"basic-interpreter/internal/runtime"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestAssignmentStatement_Execute_NumericVariable tests assignment of numeric values to variables
@@ -227,6 +230,42 @@ func TestAssignmentStatement_Execute_SelfReference(t *testing.T) {
 	assert.Equal(t, 6.0, value.NumValue)
 }
 
+func TestAssignmentStatement_Execute_Append(t *testing.T) {
+	appendTo := func(name string, suffix Expression) *AssignmentStatement {
+		return NewAssignmentStatement(name, NewBinaryExpression(NewVariableExpression(name), OpAdd, suffix))
+	}
+	text := func(value string) Expression { return NewLiteralExpression(runtime.NewStringValue(value)) }
+
+	t.Run("strings built in a loop", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		var snapshots []string
+		for _, piece := range []string{"a", "b", "c"} {
+			require.NoError(t, appendTo("S$", text(piece)).Execute(env))
+			snapshots = append(snapshots, env.GetVariable("S$").StrValue)
+		}
+		assert.Equal(t, []string{"a", "ab", "abc"}, snapshots, "earlier values are not changed by later appends")
+	})
+
+	t.Run("a copy of the variable keeps its value", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		require.NoError(t, appendTo("s$", text("ab")).Execute(env))
+		require.NoError(t, NewAssignmentStatement("T$", NewVariableExpression("S$")).Execute(env))
+		require.NoError(t, appendTo("T$", text("x")).Execute(env))
+		require.NoError(t, appendTo("S$", text("y")).Execute(env))
+
+		assert.Equal(t, "abx", env.GetVariable("T$").StrValue)
+		assert.Equal(t, "aby", env.GetVariable("S$").StrValue)
+	})
+
+	t.Run("non-string operands are added as usual", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.SetVariable("S$", runtime.NewStringValue("abc"))
+		err := appendTo("S$", NewLiteralExpression(runtime.NewNumericValue(1))).Execute(env)
+		assert.ErrorContains(t, err, "error evaluating expression for assignment")
+		assert.Equal(t, "abc", env.GetVariable("S$").StrValue)
+	})
+}
+
 // MockOutputWriter is a test double for capturing output during tests
 type MockOutputWriter struct {
 	outputs []string
@@ -265,7 +304,56 @@ func TestPrintStatement_Execute_SingleExpression(t *testing.T) {
 	
 	err := stmt.Execute(env)
 	assert.NoError(t, err)
-	assert.Equal(t, "42", output.GetLastOutput())
+	assert.Equal(t, " 42", output.GetLastOutput())
+}
+
+// TestPrintStatement_Execute_CleanNumbers tests the leading space before non-negative numbers and its clean mode
+func TestPrintStatement_Execute_CleanNumbers(t *testing.T) {
+	expressions := []Expression{
+		NewLiteralExpression(runtime.NewStringValue("X=")),
+		NewLiteralExpression(runtime.NewNumericValue(42)),
+		NewLiteralExpression(runtime.NewNumericValue(-3)),
+	}
+	
+	t.Run("authentic sign space by default", func(t *testing.T) {
+		output := &MockOutputWriter{}
+		err := NewPrintStatement(expressions, output).Execute(runtime.NewEnvironment())
+		assert.NoError(t, err)
+		assert.Equal(t, "X=             42           -3", output.GetLastOutput(), "strings and negative numbers are unchanged")
+	})
+	
+	t.Run("clean numbers without the space", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.CleanNumbers = true
+		output := &MockOutputWriter{}
+		err := NewPrintStatement(expressions, output).Execute(env)
+		assert.NoError(t, err)
+		assert.Equal(t, "X=            42            -3", output.GetLastOutput())
+	})
+}
+
+// TestPrintStatement_Execute_NumberFormat tests the configurable exponential notation thresholds
+func TestPrintStatement_Execute_NumberFormat(t *testing.T) {
+	expressions := []Expression{
+		NewLiteralExpression(runtime.NewNumericValue(1000000)),
+		NewLiteralExpression(runtime.NewNumericValue(0.00001)),
+	}
+	
+	t.Run("classic thresholds by default", func(t *testing.T) {
+		output := &MockOutputWriter{}
+		err := NewPrintStatement(expressions, output).Execute(runtime.NewEnvironment())
+		assert.NoError(t, err)
+		assert.Equal(t, " 1e+06         1e-05", output.GetLastOutput())
+	})
+	
+	t.Run("wider thresholds keep plain notation", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.NumberFormat = runtime.NumberFormat{ExponentAbove: 1e9, ExponentBelow: 1e-9}
+		output := &MockOutputWriter{}
+		err := NewPrintStatement(expressions, output).Execute(env)
+		assert.NoError(t, err)
+		assert.Equal(t, " 1000000       0.00001", output.GetLastOutput())
+	})
 }
 
 // TestPrintStatement_Execute_StringExpression tests printing a string expression
@@ -301,7 +389,7 @@ func TestPrintStatement_Execute_MultipleExpressions(t *testing.T) {
 	
 	err := stmt.Execute(env)
 	assert.NoError(t, err)
-	assert.Equal(t, "Value: 42 End", output.GetLastOutput())
+	assert.Equal(t, "Value:         42           End", output.GetLastOutput())
 }
 
 // TestPrintStatement_Execute_VariableExpression tests printing variables
@@ -324,7 +412,7 @@ func TestPrintStatement_Execute_VariableExpression(t *testing.T) {
 	
 	err := stmt.Execute(env)
 	assert.NoError(t, err)
-	assert.Equal(t, "Alice 25", output.GetLastOutput())
+	assert.Equal(t, "Alice          25", output.GetLastOutput())
 }
 
 // TestPrintStatement_Execute_ComplexExpression tests printing complex expressions
@@ -354,7 +442,7 @@ func TestPrintStatement_Execute_ComplexExpression(t *testing.T) {
 	
 	err := stmt.Execute(env)
 	assert.NoError(t, err)
-	assert.Equal(t, "20", output.GetLastOutput()) // 10 + (5 * 2) = 20
+	assert.Equal(t, " 20", output.GetLastOutput()) // 10 + (5 * 2) = 20
 }
 
 // TestPrintStatement_Execute_EmptyPrint tests printing with no expressions
@@ -383,11 +471,11 @@ func TestPrintStatement_Execute_NumericFormatting(t *testing.T) {
 		value    float64
 		expected string
 	}{
-		{"Integer", 42.0, "42"},
-		{"Decimal", 3.14, "3.14"},
-		{"Zero", 0.0, "0"},
+		{"Integer", 42.0, " 42"},
+		{"Decimal", 3.14, " 3.14"},
+		{"Zero", 0.0, " 0"},
 		{"Negative", -5.5, "-5.5"},
-		{"Large number", 1000000.0, "1e+06"},
+		{"Large number", 1000000.0, " 1e+06"},
 	}
 	
 	for _, tc := range testCases {
@@ -477,7 +565,7 @@ func TestPrintStatement_Execute_MixedTypes(t *testing.T) {
 	
 	err := stmt.Execute(env)
 	assert.NoError(t, err)
-	assert.Equal(t, "Count: 42 items", output.GetLastOutput())
+	assert.Equal(t, "Count:         42           items", output.GetLastOutput())
 }
 
 // TestPrintStatement_Execute_SeparatorHandling tests different separator behaviors
@@ -497,7 +585,85 @@ func TestPrintStatement_Execute_SeparatorHandling(t *testing.T) {
 	
 	err := stmt.Execute(env)
 	assert.NoError(t, err)
-	assert.Equal(t, "A B C", output.GetLastOutput())
+	assert.Equal(t, "A             B             C", output.GetLastOutput())
+}
+
+// TestPrintStatement_Execute_CommaZones tests that commas start each item at the next zone
+func TestPrintStatement_Execute_CommaZones(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []Expression
+		separators []rune
+		expected   string
+	}{
+		{
+			name:     "short fields start on zone boundaries",
+			items:    []Expression{str("A"), num(12), str("xyz")},
+			expected: "A" + strings.Repeat(" ", 13) + " 12" + strings.Repeat(" ", 11) + "xyz",
+		},
+		{
+			name:     "a field filling its zone moves on to the following one",
+			items:    []Expression{str("ABCDEFGHIJKLMN"), num(1)},
+			expected: "ABCDEFGHIJKLMN" + strings.Repeat(" ", 14) + " 1",
+		},
+		{
+			name:     "long fields skip the zones they cover",
+			items:    []Expression{str("A long label spanning"), num(1)},
+			expected: "A long label spanning" + strings.Repeat(" ", 7) + " 1",
+		},
+		{
+			name:       "zones count columns from the start of the line",
+			items:      []Expression{str("AB"), str("CD"), str("E")},
+			separators: []rune{';', ','},
+			expected:   "ABCD" + strings.Repeat(" ", 10) + "E",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &MockOutputWriter{}
+			stmt := NewPrintStatementWithSeparators(tt.items, tt.separators, false, output)
+
+			assert.NoError(t, stmt.Execute(runtime.NewEnvironment()))
+			assert.Equal(t, tt.expected, output.GetLastOutput())
+		})
+	}
+}
+
+// TestPrintStatement_Execute_SemicolonSeparator tests that semicolons join items without a space
+func TestPrintStatement_Execute_SemicolonSeparator(t *testing.T) {
+	env := runtime.NewEnvironment()
+	output := &MockOutputWriter{}
+	
+	stmt := NewPrintStatementWithSeparators([]Expression{str("A"), str("B"), num(1), num(2)}, []rune{';', ',', ';'}, false, output)
+	
+	assert.NoError(t, stmt.Execute(env))
+	assert.Equal(t, "AB             1 2", output.GetLastOutput())
+}
+
+// textOutputWriter is an output writer that can also write without ending the line
+type textOutputWriter struct {
+	strings.Builder
+}
+
+func (w *textOutputWriter) WriteLine(line string) error {
+	w.WriteString(line + "\n")
+	return nil
+}
+
+// TestPrintStatement_Execute_TrailingSemicolon tests that a trailing semicolon leaves the line open
+func TestPrintStatement_Execute_TrailingSemicolon(t *testing.T) {
+	env := runtime.NewEnvironment()
+	
+	output := &textOutputWriter{}
+	assert.NoError(t, NewPrintStatementWithSeparators([]Expression{str("A")}, nil, true, output).Execute(env))
+	assert.NoError(t, NewPrintStatementWithSeparators([]Expression{str("B")}, nil, false, output).Execute(env))
+	assert.Equal(t, "AB\n", output.String())
+	
+	// Writers that only write whole lines end the line anyway
+	lines := &MockOutputWriter{}
+	assert.NoError(t, NewPrintStatementWithSeparators([]Expression{str("A")}, nil, true, lines).Execute(env))
+	assert.Equal(t, []string{"A"}, lines.GetOutput())
 }
 
 // MockInputReader is a test double for providing input during tests
@@ -542,6 +708,34 @@ func TestInputStatement_Execute_NumericInput(t *testing.T) {
 	assert.Equal(t, 42.0, value.NumValue)
 }
 
+// TestInputStatement_Execute_Range tests reprompting until the value is within RANGE
+func TestInputStatement_Execute_Range(t *testing.T) {
+	env := runtime.NewEnvironment()
+	output := &MockOutputWriter{}
+	input := &MockInputReader{}
+	input.SetInputs([]string{"0", "11", "10"})
+	
+	// Test INPUT X RANGE 1 TO 10
+	stmt := &InputStatement{
+		Variable: "X",
+		Input:    input,
+		Output:   output,
+		Range: &InputRange{
+			Low:  NewLiteralExpression(runtime.NewNumericValue(1)),
+			High: NewLiteralExpression(runtime.NewNumericValue(10)),
+		},
+	}
+	
+	err := stmt.Execute(env)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, env.GetVariable("X").NumValue, "bounds are inclusive")
+	assert.Equal(t, []string{
+		"? ", "Please enter a number from 1 to 10",
+		"? ", "Please enter a number from 1 to 10",
+		"? ",
+	}, output.GetOutput())
+}
+
 // TestInputStatement_Execute_StringInput tests reading string input
 func TestInputStatement_Execute_StringInput(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -564,6 +758,35 @@ func TestInputStatement_Execute_StringInput(t *testing.T) {
 	assert.Equal(t, "Hello World", value.StrValue)
 }
 
+// TestInputStatement_Execute_LineInput tests that LINE INPUT keeps the whole line verbatim
+func TestInputStatement_Execute_LineInput(t *testing.T) {
+	env := runtime.NewEnvironment()
+	output := &MockOutputWriter{}
+	input := &MockInputReader{}
+	input.SetInputs([]string{"  Smith, John  "})
+	
+	// Test LINE INPUT NAME$
+	stmt := &InputStatement{
+		Variable: "NAME$",
+		Input:    input,
+		Output:   output,
+		Line:     true,
+	}
+	
+	err := stmt.Execute(env)
+	assert.NoError(t, err)
+	assert.Empty(t, output.GetOutput(), "LINE INPUT has no default prompt")
+	
+	value := env.GetVariable("NAME$")
+	assert.Equal(t, runtime.StringValue, value.Type)
+	assert.Equal(t, "  Smith, John  ", value.StrValue, "commas and surrounding spaces are kept")
+	
+	// A numeric variable is refused before any input is read
+	stmt = &InputStatement{Variable: "N", Input: input, Output: output, Line: true}
+	err = stmt.Execute(env)
+	assert.EqualError(t, err, "LINE INPUT requires a string variable, found N")
+}
+
 // TestInputStatement_Execute_WithPrompt tests INPUT with a prompt message
 func TestInputStatement_Execute_WithPrompt(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -628,6 +851,24 @@ func TestInputStatement_Execute_NumericConversion(t *testing.T) {
 	}
 }
 
+// TestInputStatement_Execute_DecimalComma tests numeric input under a comma-decimal number format
+func TestInputStatement_Execute_DecimalComma(t *testing.T) {
+	env := runtime.NewEnvironment()
+	env.NumberFormat.DecimalComma = true
+	input := &MockInputReader{}
+	input.SetInputs([]string{"3,14"})
+	
+	stmt := &InputStatement{
+		Variable: "NUM",
+		Input:    input,
+		Output:   &MockOutputWriter{},
+	}
+	
+	err := stmt.Execute(env)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.14, env.GetVariable("NUM").NumValue)
+}
+
 // TestInputStatement_Execute_StringVariableHandling tests string variable handling
 func TestInputStatement_Execute_StringVariableHandling(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -965,8 +1206,8 @@ func TestGotoStatement_Execute_ProgramCounterPreservation(t *testing.T) {
 	
 	// Set up some environment state
 	env.SetVariable("TEST", runtime.NewNumericValue(42))
-	env.CallStack = []int{5, 15}
-	originalCallStack := make([]int, len(env.CallStack))
+	env.CallStack = []runtime.ResumePoint{{Line: 5}, {Line: 15}}
+	originalCallStack := make([]runtime.ResumePoint, len(env.CallStack))
 	copy(originalCallStack, env.CallStack)
 	
 	program := &Program{
@@ -1074,6 +1315,32 @@ func TestIfStatement_Execute_FalseCondition(t *testing.T) {
 	assert.Equal(t, 0, len(output.GetOutput()))
 }
 
+// TestIfStatement_Execute_ElseBranch tests that ELSE runs only when the condition is false
+func TestIfStatement_Execute_ElseBranch(t *testing.T) {
+	printText := func(output *MockOutputWriter, text string) Statement {
+		return NewPrintStatement([]Expression{NewLiteralExpression(runtime.NewStringValue(text))}, output)
+	}
+
+	for _, tc := range []struct {
+		condition float64
+		expected  string
+	}{
+		{condition: 1, expected: "then"},
+		{condition: 0, expected: "else"},
+	} {
+		output := &MockOutputWriter{}
+		stmt := NewIfElseStatement(
+			NewLiteralExpression(runtime.NewNumericValue(tc.condition)),
+			printText(output, "then"),
+			printText(output, "else"),
+		)
+
+		err := stmt.Execute(runtime.NewEnvironment())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{tc.expected}, output.GetOutput())
+	}
+}
+
 // TestIfStatement_Execute_EqualityOperator tests IF-THEN with equality comparison
 func TestIfStatement_Execute_EqualityOperator(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -1236,6 +1503,99 @@ func TestIfStatement_Execute_StringComparison(t *testing.T) {
 	}
 }
 
+// TestComparisonExpression_MixedTypes tests number-string comparisons in strict and coercing modes
+// Coercion converts a string holding a number; any other string is still a type mismatch
+func TestComparisonExpression_MixedTypes(t *testing.T) {
+	compare := func(left runtime.Value, operator string, right runtime.Value) *ComparisonExpression {
+		return NewComparisonExpression(NewLiteralExpression(left), operator, NewLiteralExpression(right))
+	}
+	five := runtime.NewNumericValue(5)
+	
+	t.Run("strict by default", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		
+		_, err := compare(five, "=", runtime.NewStringValue("5")).Evaluate(env)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "type mismatch in comparison")
+		
+		_, err = compare(five, "=", runtime.NewStringValue("abc")).Evaluate(env)
+		assert.Error(t, err)
+	})
+	
+	t.Run("coercing mode compares numeric strings as numbers", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.CoerceComparisons = true
+		
+		testCases := []struct {
+			name     string
+			expr     *ComparisonExpression
+			expected float64
+		}{
+			{"5 = \"5\"", compare(five, "=", runtime.NewStringValue("5")), -1},
+			{"\"5\" = 5", compare(runtime.NewStringValue("5"), "=", five), -1},
+			{"5 = \" 5.0 \"", compare(five, "=", runtime.NewStringValue(" 5.0 ")), -1},
+			{"5 <> \"6\"", compare(five, "<>", runtime.NewStringValue("6")), -1},
+			{"5 < \"10\" is numeric, not string, order", compare(five, "<", runtime.NewStringValue("10")), -1},
+			{"strings are still compared as strings", compare(runtime.NewStringValue("10"), "<", runtime.NewStringValue("5")), -1},
+		}
+		
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				result, err := tc.expr.Evaluate(env)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result.NumValue)
+			})
+		}
+	})
+	
+	t.Run("coercing mode still rejects strings that are not numbers", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.CoerceComparisons = true
+		
+		_, err := compare(five, "=", runtime.NewStringValue("abc")).Evaluate(env)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "type mismatch in comparison")
+	})
+}
+
+// TestLogicalExpression_Evaluate tests AND, OR and NOT on BASIC truth values
+// Any non-zero number is true, and the result is -1 or 0 like a comparison
+func TestLogicalExpression_Evaluate(t *testing.T) {
+	literal := func(n float64) Expression {
+		return NewLiteralExpression(runtime.NewNumericValue(n))
+	}
+	
+	testCases := []struct {
+		name     string
+		expr     Expression
+		expected float64
+	}{
+		{"-1 AND -1", NewLogicalExpression(literal(-1), OpAnd, literal(-1)), -1},
+		{"5 AND 3", NewLogicalExpression(literal(5), OpAnd, literal(3)), -1},
+		{"5 AND 0", NewLogicalExpression(literal(5), OpAnd, literal(0)), 0},
+		{"0 OR 7", NewLogicalExpression(literal(0), OpOr, literal(7)), -1},
+		{"0 OR 0", NewLogicalExpression(literal(0), OpOr, literal(0)), 0},
+		{"NOT 0", NewNotExpression(literal(0)), -1},
+		{"NOT 2", NewNotExpression(literal(2)), 0},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.expr.Evaluate(runtime.NewEnvironment())
+			assert.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.Equal(t, tc.expected, result.NumValue)
+		})
+	}
+	
+	t.Run("both operands are always evaluated", func(t *testing.T) {
+		failing := NewBinaryExpression(literal(1), OpDivide, literal(0))
+		_, err := NewLogicalExpression(literal(0), OpAnd, failing).Evaluate(runtime.NewEnvironment())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "division by zero")
+	})
+}
+
 // TestIfStatement_Execute_VariableComparison tests comparison with variables
 func TestIfStatement_Execute_VariableComparison(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -2107,4 +2467,296 @@ func TestForNextIntegration_SimpleLoop(t *testing.T) {
 	assert.Equal(t, 4.0, env.GetVariable("I").NumValue) // Final value after increment
 	assert.Equal(t, 0, len(env.ForLoops)) // Loop completed and removed
 	assert.Equal(t, 10, env.ProgramCounter) // Program counter from last iteration
-}
\ No newline at end of file
+}
+
+// MockKeyReader is a test double that supplies single keypresses
+type MockKeyReader struct {
+	MockInputReader
+	keys []rune
+}
+
+func (m *MockKeyReader) ReadKey() (rune, error) {
+	if len(m.keys) == 0 {
+		return 0, fmt.Errorf("no more keys available")
+	}
+	key := m.keys[0]
+	m.keys = m.keys[1:]
+	return key, nil
+}
+
+// TestPauseStatement_Execute_DefaultPrompt tests that PAUSE waits for one key
+func TestPauseStatement_Execute_DefaultPrompt(t *testing.T) {
+	env := runtime.NewEnvironment()
+	input := &MockKeyReader{keys: []rune{'x', 'y'}}
+	output := &MockOutputWriter{}
+
+	stmt := NewPauseStatement("", input, output)
+	err := stmt.Execute(env)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultPausePrompt}, output.GetOutput())
+	assert.Equal(t, []rune{'y'}, input.keys, "PAUSE should consume exactly one key")
+}
+
+// TestPauseStatement_Execute_CustomPrompt tests PAUSE with a custom message
+func TestPauseStatement_Execute_CustomPrompt(t *testing.T) {
+	env := runtime.NewEnvironment()
+	input := &MockKeyReader{keys: []rune{' '}}
+	output := &MockOutputWriter{}
+
+	stmt := NewPauseStatement("Hit a key for the next page", input, output)
+	err := stmt.Execute(env)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hit a key for the next page"}, output.GetOutput())
+}
+
+// TestPauseStatement_Execute_LineReaderFallback tests PAUSE with a reader that cannot read keys
+func TestPauseStatement_Execute_LineReaderFallback(t *testing.T) {
+	env := runtime.NewEnvironment()
+	input := &MockInputReader{inputs: []string{"", "next"}}
+	output := &MockOutputWriter{}
+
+	stmt := NewPauseStatement("", input, output)
+	err := stmt.Execute(env)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, input.index, "PAUSE should consume one line")
+}
+
+// TestPauseStatement_Execute_ReadError tests that input errors are reported
+func TestPauseStatement_Execute_ReadError(t *testing.T) {
+	env := runtime.NewEnvironment()
+	output := &MockOutputWriter{}
+
+	stmt := NewPauseStatement("", &MockKeyReader{}, output)
+	err := stmt.Execute(env)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error reading key")
+}
+
+// TestAssertStatement_Execute tests ASSERT with passing and failing conditions
+func TestAssertStatement_Execute(t *testing.T) {
+	env := runtime.NewEnvironment()
+	env.SetVariable("A", runtime.NewNumericValue(5))
+
+	t.Run("true condition does nothing", func(t *testing.T) {
+		condition := NewComparisonExpression(NewVariableExpression("A"), "=", NewLiteralExpression(runtime.NewNumericValue(5)))
+		err := NewAssertStatement(condition, 10).Execute(env)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("false condition fails with the line number", func(t *testing.T) {
+		condition := NewComparisonExpression(NewVariableExpression("A"), ">", NewLiteralExpression(runtime.NewNumericValue(10)))
+		err := NewAssertStatement(condition, 40).Execute(env)
+
+		assert.Error(t, err)
+		assert.Equal(t, "assertion failed at line 40", err.Error())
+	})
+
+	t.Run("uses IF truthiness rules", func(t *testing.T) {
+		assert.NoError(t, NewAssertStatement(NewLiteralExpression(runtime.NewNumericValue(-1)), 10).Execute(env))
+		assert.NoError(t, NewAssertStatement(NewLiteralExpression(runtime.NewStringValue("yes")), 10).Execute(env))
+		assert.Error(t, NewAssertStatement(NewLiteralExpression(runtime.NewNumericValue(0)), 10).Execute(env))
+		assert.Error(t, NewAssertStatement(NewLiteralExpression(runtime.NewStringValue("")), 10).Execute(env))
+	})
+}
+
+// TestCompoundStatement_Execute tests statements separated by colons
+func TestCompoundStatement_Execute(t *testing.T) {
+	t.Run("runs all statements in order", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		output := &MockOutputWriter{}
+		stmt := NewCompoundStatement([]Statement{
+			NewAssignmentStatement("A", NewLiteralExpression(runtime.NewNumericValue(1))),
+			NewAssignmentStatement("B", NewLiteralExpression(runtime.NewNumericValue(2))),
+			NewPrintStatement([]Expression{NewVariableExpression("A"), NewVariableExpression("B")}, output),
+		})
+
+		err := stmt.Execute(env)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{" 1             2"}, output.GetOutput())
+	})
+
+	t.Run("stops after a jump", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.ProgramCounter = 10
+		program := &Program{Lines: map[int]Statement{10: NewEndStatement(), 50: NewEndStatement()}, Order: []int{10, 50}}
+		output := &MockOutputWriter{}
+		stmt := NewCompoundStatement([]Statement{
+			NewGotoStatement(50, program),
+			NewPrintStatement([]Expression{NewLiteralExpression(runtime.NewStringValue("skipped"))}, output),
+		})
+
+		err := stmt.Execute(env)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 50, env.ProgramCounter)
+		assert.Empty(t, output.GetOutput())
+	})
+}
+
+// MockCommandRunner records SHELL commands and returns canned output
+type MockCommandRunner struct {
+	Commands []string
+	Output   string
+	Err      error
+}
+
+func (m *MockCommandRunner) RunCommand(command string) (string, error) {
+	m.Commands = append(m.Commands, command)
+	return m.Output, m.Err
+}
+
+// TestShellStatement_Execute tests running commands through a mock runner
+func TestShellStatement_Execute(t *testing.T) {
+	command := NewLiteralExpression(runtime.NewStringValue("echo hi"))
+
+	t.Run("invokes the command and prints its output", func(t *testing.T) {
+		runner := &MockCommandRunner{Output: "one\ntwo\n"}
+		output := &MockOutputWriter{}
+		err := NewShellStatement(command, "", runner, output).Execute(runtime.NewEnvironment())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"echo hi"}, runner.Commands)
+		assert.Equal(t, []string{"one", "two"}, output.GetOutput())
+	})
+
+	t.Run("captures output into a string variable", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		runner := &MockCommandRunner{Output: "hi\n"}
+		output := &MockOutputWriter{}
+		err := NewShellStatement(command, "R$", runner, output).Execute(env)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", env.GetVariable("R$").StrValue)
+		assert.Empty(t, output.GetOutput())
+	})
+
+	t.Run("is disabled without a runner", func(t *testing.T) {
+		err := NewShellStatement(command, "", nil, &MockOutputWriter{}).Execute(runtime.NewEnvironment())
+
+		assert.ErrorIs(t, err, runtime.ErrFeatureDisabled)
+	})
+
+	t.Run("is disabled by the environment even with a runner", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.DisableFeature("SHELL")
+		runner := &MockCommandRunner{}
+		err := NewShellStatement(command, "", runner, &MockOutputWriter{}).Execute(env)
+
+		assert.ErrorIs(t, err, runtime.ErrFeatureDisabled)
+		assert.Empty(t, runner.Commands, "the command must not run")
+	})
+
+	t.Run("command failure is an error", func(t *testing.T) {
+		runner := &MockCommandRunner{Err: fmt.Errorf("exit status 1")}
+		err := NewShellStatement(command, "", runner, &MockOutputWriter{}).Execute(runtime.NewEnvironment())
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error running command")
+	})
+}
+
+// TestGosubStatement_Execute tests that GOSUB records where RETURN resumes
+func TestGosubStatement_Execute(t *testing.T) {
+	program := &Program{
+		Lines: map[int]Statement{
+			10:  NewGosubStatement(100, nil),
+			20:  NewEndStatement(),
+			100: NewReturnStatement(),
+		},
+		Order: []int{10, 20, 100},
+	}
+
+	t.Run("pushes the next statement and jumps", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.ProgramCounter = 10
+
+		err := NewGosubStatement(100, program).Execute(env)
+		assert.NoError(t, err)
+		assert.Equal(t, 100, env.ProgramCounter)
+		assert.Equal(t, []runtime.ResumePoint{{Line: 10, Statement: 1}}, env.CallStack)
+	})
+
+	t.Run("GOSUB in the middle of a line returns to the rest of it", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.ProgramCounter = 10
+		env.Statement = 2
+
+		err := NewGosubStatement(100, program).Execute(env)
+		assert.NoError(t, err)
+		assert.Equal(t, []runtime.ResumePoint{{Line: 10, Statement: 3}}, env.CallStack)
+	})
+
+	t.Run("missing target line", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+
+		err := NewGosubStatement(50, program).Execute(env)
+		assert.EqualError(t, err, "line number 50 does not exist")
+		assert.Empty(t, env.CallStack)
+	})
+}
+
+// TestReturnStatement_Execute tests that RETURN resumes at the innermost GOSUB
+func TestReturnStatement_Execute(t *testing.T) {
+	env := runtime.NewEnvironment()
+	env.CallStack = []runtime.ResumePoint{{Line: 20}, {Line: 110, Statement: 2}}
+
+	assert.NoError(t, NewReturnStatement().Execute(env))
+	assert.Equal(t, runtime.ResumePoint{Line: 110, Statement: 2}, env.JumpTarget)
+	assert.Equal(t, 110, env.ProgramCounter)
+
+	assert.NoError(t, NewReturnStatement().Execute(env))
+	assert.Equal(t, runtime.ResumePoint{Line: 20}, env.JumpTarget)
+
+	err := NewReturnStatement().Execute(env)
+	assert.EqualError(t, err, "RETURN without GOSUB")
+}
+
+// TestRandomizeStatement_Execute tests reseeding the random number generator
+func TestRandomizeStatement_Execute(t *testing.T) {
+	draw := func(env *runtime.Environment) []float64 {
+		return []float64{env.Random(), env.Random(), env.Random()}
+	}
+
+	t.Run("RANDOMIZE n gives a repeatable sequence", func(t *testing.T) {
+		seed := NewLiteralExpression(runtime.NewNumericValue(42))
+
+		first := runtime.NewEnvironment()
+		assert.NoError(t, NewRandomizeStatement(seed).Execute(first))
+		second := runtime.NewEnvironment()
+		assert.NoError(t, NewRandomizeStatement(seed).Execute(second))
+
+		assert.Equal(t, int64(42), first.GetRandomSeed())
+		assert.Equal(t, draw(first), draw(second))
+	})
+
+	t.Run("RANDOMIZE TIMER seeds from the clock", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.Clock = func() time.Time {
+			return time.Date(2024, 3, 15, 0, 1, 40, 0, time.Local)
+		}
+
+		err := NewRandomizeStatement(NewFunctionCallExpression("TIMER", nil)).Execute(env)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), env.GetRandomSeed())
+	})
+
+	t.Run("plain RANDOMIZE uses the current time", func(t *testing.T) {
+		now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+		env := runtime.NewEnvironment()
+		env.Clock = func() time.Time { return now }
+
+		assert.NoError(t, NewRandomizeStatement(nil).Execute(env))
+		assert.Equal(t, now.UnixNano(), env.GetRandomSeed())
+	})
+
+	t.Run("string seed is an error", func(t *testing.T) {
+		seed := NewLiteralExpression(runtime.NewStringValue("x"))
+		assert.Error(t, NewRandomizeStatement(seed).Execute(runtime.NewEnvironment()))
+	})
+}
@@ -2,6 +2,7 @@ package ast
 
 import (
 	"basic-interpreter/internal/runtime"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -330,6 +331,462 @@ func TestMidFunction_ErrorCases(t *testing.T) {
 	})
 }
 
+// Test LEFT$ function implementation
+func TestLeftFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("LEFT$")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		str      string
+		length   float64
+		expected string
+	}{
+		{name: "leading characters", str: "HELLO", length: 3, expected: "HEL"},
+		{name: "whole string", str: "HELLO", length: 5, expected: "HELLO"},
+		{name: "length past the end", str: "HELLO", length: 10, expected: "HELLO"},
+		{name: "huge length", str: "abc", length: 1e20, expected: "abc"},
+		{name: "huge negative length", str: "abc", length: -1e20, expected: ""},
+		{name: "fractional length is truncated", str: "HELLO", length: 2.9, expected: "HE"},
+		{name: "zero length", str: "HELLO", length: 0, expected: ""},
+		{name: "negative length", str: "HELLO", length: -1, expected: ""},
+		{name: "empty string", str: "", length: 3, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{runtime.NewStringValue(tc.str), runtime.NewNumericValue(tc.length)}
+			result, err := fn.Call(args, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, runtime.StringValue, result.Type)
+			assert.Equal(t, tc.expected, result.StrValue)
+		})
+	}
+}
+
+func TestLeftFunction_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("LEFT$")
+	require.NotNil(t, fn)
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("HELLO")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 2 arguments")
+	})
+
+	t.Run("first argument not string", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(123), runtime.NewNumericValue(1)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "first argument must be string")
+	})
+
+	t.Run("second argument not numeric", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("HELLO"), runtime.NewStringValue("1")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "second argument must be numeric")
+	})
+}
+
+// Test UCASE$ and LCASE$ function implementations
+func TestCaseFunctions_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	ucase := GetBuiltinFunction("UCASE$")
+	require.NotNil(t, ucase)
+	lcase := GetBuiltinFunction("LCASE$")
+	require.NotNil(t, lcase)
+
+	testCases := []struct {
+		name  string
+		str   string
+		upper string
+		lower string
+	}{
+		{name: "mixed case", str: "Hello World", upper: "HELLO WORLD", lower: "hello world"},
+		{name: "already upper case", str: "YES", upper: "YES", lower: "yes"},
+		{name: "already lower case", str: "no", upper: "NO", lower: "no"},
+		{name: "non-alphabetic characters are kept", str: "a1-b2 #3!", upper: "A1-B2 #3!", lower: "a1-b2 #3!"},
+		{name: "empty string", str: "", upper: "", lower: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{runtime.NewStringValue(tc.str)}
+
+			result, err := ucase.Call(args, env)
+			require.NoError(t, err)
+			assert.Equal(t, runtime.StringValue, result.Type)
+			assert.Equal(t, tc.upper, result.StrValue)
+
+			result, err = lcase.Call(args, env)
+			require.NoError(t, err)
+			assert.Equal(t, runtime.StringValue, result.Type)
+			assert.Equal(t, tc.lower, result.StrValue)
+		})
+	}
+}
+
+func TestCaseFunctions_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+
+	for _, name := range []string{"UCASE$", "LCASE$"} {
+		fn := GetBuiltinFunction(name)
+		require.NotNil(t, fn)
+
+		t.Run(name+" wrong argument count", func(t *testing.T) {
+			_, err := fn.Call([]runtime.Value{}, env)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected 1 argument")
+		})
+
+		t.Run(name+" argument not string", func(t *testing.T) {
+			_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1)}, env)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "must be string")
+		})
+	}
+}
+
+// Test TRIM$, LTRIM$ and RTRIM$ function implementations
+func TestTrimFunctions_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+
+	testCases := []struct {
+		name  string
+		str   string
+		trim  string
+		ltrim string
+		rtrim string
+	}{
+		{name: "leading spaces", str: "  abc", trim: "abc", ltrim: "abc", rtrim: "  abc"},
+		{name: "trailing spaces", str: "abc  ", trim: "abc", ltrim: "abc  ", rtrim: "abc"},
+		{name: "both ends", str: "  a b  ", trim: "a b", ltrim: "a b  ", rtrim: "  a b"},
+		{name: "no surrounding spaces", str: "a b", trim: "a b", ltrim: "a b", rtrim: "a b"},
+		{name: "only spaces", str: "   ", trim: "", ltrim: "", rtrim: ""},
+		{name: "empty string", str: "", trim: "", ltrim: "", rtrim: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{runtime.NewStringValue(tc.str)}
+			for name, expected := range map[string]string{"TRIM$": tc.trim, "LTRIM$": tc.ltrim, "RTRIM$": tc.rtrim} {
+				fn := GetBuiltinFunction(name)
+				require.NotNil(t, fn)
+
+				result, err := fn.Call(args, env)
+				require.NoError(t, err)
+				assert.Equal(t, runtime.StringValue, result.Type)
+				assert.Equal(t, expected, result.StrValue, name)
+			}
+		})
+	}
+}
+
+func TestTrimFunctions_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+
+	for _, name := range []string{"TRIM$", "LTRIM$", "RTRIM$"} {
+		fn := GetBuiltinFunction(name)
+		require.NotNil(t, fn)
+
+		t.Run(name+" wrong argument count", func(t *testing.T) {
+			_, err := fn.Call([]runtime.Value{runtime.NewStringValue(" a "), runtime.NewStringValue(" b ")}, env)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected 1 argument")
+		})
+
+		t.Run(name+" argument not string", func(t *testing.T) {
+			_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1)}, env)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "must be string")
+		})
+	}
+}
+
+// Test RIGHT$ function implementation
+func TestRightFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("RIGHT$")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		str      string
+		length   float64
+		expected string
+	}{
+		{
+			name:     "trailing characters",
+			str:      "HELLO",
+			length:   3.0,
+			expected: "LLO",
+		},
+		{
+			name:     "single character",
+			str:      "HELLO",
+			length:   1.0,
+			expected: "O",
+		},
+		{
+			name:     "whole string",
+			str:      "HELLO",
+			length:   5.0,
+			expected: "HELLO",
+		},
+		{
+			name:     "length longer than string",
+			str:      "HELLO",
+			length:   10.0,
+			expected: "HELLO",
+		},
+		{
+			name:     "huge length",
+			str:      "abc",
+			length:   1e20,
+			expected: "abc",
+		},
+		{
+			name:     "huge negative length",
+			str:      "abc",
+			length:   -1e20,
+			expected: "",
+		},
+		{
+			name:     "fractional length is truncated",
+			str:      "HELLO",
+			length:   2.7,
+			expected: "LO",
+		},
+		{
+			name:     "zero length",
+			str:      "HELLO",
+			length:   0.0,
+			expected: "",
+		},
+		{
+			name:     "negative length",
+			str:      "HELLO",
+			length:   -2.0,
+			expected: "",
+		},
+		{
+			name:     "empty string input",
+			str:      "",
+			length:   3.0,
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{
+				runtime.NewStringValue(tc.str),
+				runtime.NewNumericValue(tc.length),
+			}
+			result, err := fn.Call(args, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, runtime.StringValue, result.Type)
+			assert.Equal(t, tc.expected, result.StrValue)
+		})
+	}
+}
+
+func TestRightFunction_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("RIGHT$")
+	require.NotNil(t, fn)
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("HELLO")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 2 arguments")
+	})
+
+	t.Run("first argument not string", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(123), runtime.NewNumericValue(1)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "first argument must be string")
+	})
+
+	t.Run("second argument not numeric", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("HELLO"), runtime.NewStringValue("1")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "second argument must be numeric")
+	})
+}
+
+func TestInstrFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("INSTR")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		haystack string
+		needle   string
+		expected float64
+	}{
+		{
+			name:     "found at start",
+			haystack: "HELLO",
+			needle:   "HE",
+			expected: 1,
+		},
+		{
+			name:     "found in the middle",
+			haystack: "HELLO",
+			needle:   "LL",
+			expected: 3,
+		},
+		{
+			name:     "first of several matches",
+			haystack: "BANANA",
+			needle:   "AN",
+			expected: 2,
+		},
+		{
+			name:     "not found",
+			haystack: "HELLO",
+			needle:   "XYZ",
+			expected: 0,
+		},
+		{
+			name:     "search is case sensitive",
+			haystack: "HELLO",
+			needle:   "hello",
+			expected: 0,
+		},
+		{
+			name:     "needle longer than string",
+			haystack: "HI",
+			needle:   "HIGH",
+			expected: 0,
+		},
+		{
+			name:     "empty needle",
+			haystack: "HELLO",
+			needle:   "",
+			expected: 1,
+		},
+		{
+			name:     "empty string",
+			haystack: "",
+			needle:   "",
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{
+				runtime.NewStringValue(tc.haystack),
+				runtime.NewStringValue(tc.needle),
+			}
+			result, err := fn.Call(args, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.Equal(t, tc.expected, result.NumValue)
+		})
+	}
+}
+
+func TestInstrFunction_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("INSTR")
+	require.NotNil(t, fn)
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("HELLO")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 2 to 3 arguments")
+	})
+
+	t.Run("first argument not string", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(123), runtime.NewStringValue("1")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "first argument must be string")
+	})
+
+	t.Run("second argument not string", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("HELLO"), runtime.NewNumericValue(1)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "second argument must be string")
+	})
+
+	t.Run("start position not numeric", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("1"), runtime.NewStringValue("HELLO"), runtime.NewStringValue("L")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "first argument must be numeric")
+	})
+
+	t.Run("needle not string with a start position", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1), runtime.NewStringValue("HELLO"), runtime.NewNumericValue(1)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "third argument must be string")
+	})
+
+	t.Run("start position below one", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(0), runtime.NewStringValue("HELLO"), runtime.NewStringValue("L")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "start position must be at least 1")
+	})
+}
+
+func TestInstrFunction_CallWithStart(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("INSTR")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		start    float64
+		haystack string
+		needle   string
+		expected float64
+	}{
+		{"start at one is the two-argument form", 1, "BANANA", "AN", 2},
+		{"finds the next match after start", 3, "BANANA", "AN", 4},
+		{"match at the start position", 4, "BANANA", "AN", 4},
+		{"no match after start", 5, "BANANA", "AN", 0},
+		{"start past the end", 7, "BANANA", "A", 0},
+		{"empty needle is found at start", 3, "BANANA", "", 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{
+				runtime.NewNumericValue(tc.start),
+				runtime.NewStringValue(tc.haystack),
+				runtime.NewStringValue(tc.needle),
+			}
+			result, err := fn.Call(args, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result.NumValue)
+		})
+	}
+}
+
 // Test STR$ function implementation
 func TestStrFunction_Call(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -428,6 +885,89 @@ func TestStrFunction_ErrorCases(t *testing.T) {
 	})
 }
 
+func TestDigitsFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("DIGITS")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		input    float64
+		expected float64
+	}{
+		{
+			name:     "multi-digit integer",
+			input:    12345,
+			expected: 5,
+		},
+		{
+			name:     "negative number excludes the sign",
+			input:    -120,
+			expected: 3,
+		},
+		{
+			name:     "decimal counts the point",
+			input:    3.25,
+			expected: 4,
+		},
+		{
+			name:     "fraction below one counts the leading zero",
+			input:    -0.5,
+			expected: 3,
+		},
+		{
+			name:     "zero",
+			input:    0,
+			expected: 1,
+		},
+		{
+			name:     "exponential notation counts the whole form",
+			input:    1000000,
+			expected: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := fn.Call([]runtime.Value{runtime.NewNumericValue(tc.input)}, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.Equal(t, tc.expected, result.NumValue)
+		})
+	}
+
+	t.Run("consistent with STR$ under a custom number format", func(t *testing.T) {
+		custom := runtime.NewEnvironment()
+		custom.NumberFormat = runtime.NumberFormat{ExponentAbove: 1e9, ExponentBelow: 1e-9}
+
+		result, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1000000)}, custom)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7.0, result.NumValue)
+	})
+}
+
+func TestDigitsFunction_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("DIGITS")
+	require.NotNil(t, fn)
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 1 argument")
+	})
+
+	t.Run("string argument", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("42")}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "argument must be numeric")
+	})
+}
+
 // Test VAL function implementation
 func TestValFunction_Call(t *testing.T) {
 	env := runtime.NewEnvironment()
@@ -571,6 +1111,27 @@ func TestValFunction_InvalidConversions(t *testing.T) {
 	}
 }
 
+func TestValFunction_DecimalComma(t *testing.T) {
+	env := runtime.NewEnvironment()
+	env.NumberFormat.DecimalComma = true
+
+	result, err := GetBuiltinFunction("VAL").Call([]runtime.Value{runtime.NewStringValue("3,14")}, env)
+	require.NoError(t, err)
+	assert.Equal(t, 3.14, result.NumValue)
+
+	result, err = GetBuiltinFunction("VAL").Call([]runtime.Value{runtime.NewStringValue("1.234,5")}, env)
+	require.NoError(t, err)
+	assert.Equal(t, 1234.5, result.NumValue)
+
+	result, err = GetBuiltinFunction("STR$").Call([]runtime.Value{runtime.NewNumericValue(3.14)}, env)
+	require.NoError(t, err)
+	assert.Equal(t, "3,14", result.StrValue)
+
+	result, err = GetBuiltinFunction("ISNUMERIC").Call([]runtime.Value{runtime.NewStringValue("3,14")}, env)
+	require.NoError(t, err)
+	assert.Equal(t, -1.0, result.NumValue)
+}
+
 func TestValFunction_ErrorCases(t *testing.T) {
 	env := runtime.NewEnvironment()
 	fn := GetBuiltinFunction("VAL")
@@ -747,4 +1308,126 @@ func TestStringFunctionEdgeCases(t *testing.T) {
 			})
 		}
 	})
-}
\ No newline at end of file
+}
+
+// Test FORMATNUM$ function implementation
+func TestFormatNumFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("FORMATNUM$")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		value    float64
+		decimals float64
+		expected string
+	}{
+		{name: "thousands grouping", value: 1234567.5, decimals: 2, expected: "1,234,567.50"},
+		{name: "no grouping below a thousand", value: 999, decimals: 2, expected: "999.00"},
+		{name: "exactly a thousand", value: 1000, decimals: 0, expected: "1,000"},
+		{name: "rounds to nearest", value: 2.26, decimals: 1, expected: "2.3"},
+		{name: "exact ties round to even", value: 2.25, decimals: 1, expected: "2.2"},
+		{name: "rounds up", value: 9999.996, decimals: 2, expected: "10,000.00"},
+		{name: "zero decimals rounds to integer", value: 1234.6, decimals: 0, expected: "1,235"},
+		{name: "negative value", value: -1234567.891, decimals: 2, expected: "-1,234,567.89"},
+		{name: "negative zero decimals", value: -1234.6, decimals: 0, expected: "-1,235"},
+		{name: "small negative rounds to zero", value: -0.001, decimals: 2, expected: "0.00"},
+		{name: "zero", value: 0, decimals: 3, expected: "0.000"},
+		{name: "huge value", value: 1e21, decimals: 1, expected: "1,000,000,000,000,000,000,000.0"},
+		{name: "most decimals", value: 0.5, decimals: 20, expected: "0.50000000000000000000"},
+		{name: "fractional decimals are truncated", value: 12.3456, decimals: 2.9, expected: "12.35"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{runtime.NewNumericValue(tc.value), runtime.NewNumericValue(tc.decimals)}
+			result, err := fn.Call(args, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, runtime.StringValue, result.Type)
+			assert.Equal(t, tc.expected, result.StrValue)
+		})
+	}
+}
+
+func TestFormatNumFunction_ErrorCases(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("FORMATNUM$")
+	require.NotNil(t, fn)
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 2 arguments")
+	})
+
+	t.Run("wrong argument type - string value", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewStringValue("1"), runtime.NewNumericValue(2)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "argument must be numeric")
+	})
+
+	t.Run("negative decimals", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1), runtime.NewNumericValue(-1)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be negative")
+	})
+
+	t.Run("too many decimals", func(t *testing.T) {
+		for _, decimals := range []float64{21, 400, 1e20} {
+			_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1), runtime.NewNumericValue(decimals)}, env)
+
+			assert.ErrorContains(t, err, "decimals cannot be more than 20")
+		}
+	})
+
+	t.Run("infinite or not a number", func(t *testing.T) {
+		for _, value := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+			_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(value), runtime.NewNumericValue(2)}, env)
+
+			assert.ErrorContains(t, err, "FORMATNUM$ function cannot format")
+		}
+	})
+}
+
+// Test ISNUMERIC function implementation
+func TestIsNumericFunction_Call(t *testing.T) {
+	env := runtime.NewEnvironment()
+	fn := GetBuiltinFunction("ISNUMERIC")
+	require.NotNil(t, fn)
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected float64
+	}{
+		{name: "integer", input: "42", expected: -1},
+		{name: "decimal", input: "3.14", expected: -1},
+		{name: "negative exponent form", input: "-1e3", expected: -1},
+		{name: "surrounding spaces are trimmed", input: "  7 ", expected: -1},
+		{name: "empty string", input: "", expected: 0},
+		{name: "letters", input: "abc", expected: 0},
+		{name: "trailing junk", input: "12abc", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []runtime.Value{runtime.NewStringValue(tc.input)}
+			result, err := fn.Call(args, env)
+
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, result.Type)
+			assert.Equal(t, tc.expected, result.NumValue)
+		})
+	}
+
+	t.Run("numeric argument is an error", func(t *testing.T) {
+		_, err := fn.Call([]runtime.Value{runtime.NewNumericValue(1)}, env)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "argument must be string")
+	})
+}
@@ -0,0 +1,84 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// TAB and SPC
+// These position the print head rather than produce a value, so they are items of a
+// PRINT list instead of ordinary functions: PRINT TAB(10); "X" puts X in column 10 and
+// PRINT SPC(5); "X" writes five spaces before it. Columns are numbered from 1.
+
+// MaxPrintPosition is the largest SPC count and TAB column, as in classic BASIC; larger
+// ones are clamped to it, so a huge count cannot exhaust memory
+const MaxPrintPosition = 255
+
+// TabExpression represents TAB(n) in a PRINT list, moving the print head to column n
+type TabExpression struct {
+	Column Expression
+}
+
+// NewTabExpression creates a new TAB item for the given column
+func NewTabExpression(column Expression) *TabExpression {
+	return &TabExpression{Column: column}
+}
+
+// Evaluate fails, since TAB only has a meaning while PRINT is placing text
+func (t *TabExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	return runtime.Value{}, fmt.Errorf("TAB can only be used in PRINT")
+}
+
+// SpcExpression represents SPC(n) in a PRINT list, writing n spaces
+type SpcExpression struct {
+	Count Expression
+}
+
+// NewSpcExpression creates a new SPC item for the given number of spaces
+func NewSpcExpression(count Expression) *SpcExpression {
+	return &SpcExpression{Count: count}
+}
+
+// Evaluate fails, since SPC only has a meaning while PRINT is placing text
+func (s *SpcExpression) Evaluate(env *runtime.Environment) (runtime.Value, error) {
+	return runtime.Value{}, fmt.Errorf("SPC can only be used in PRINT")
+}
+
+// printLine builds the text of a PRINT line, keeping track of the print head's column
+type printLine struct {
+	text   strings.Builder
+	column int // Characters written since the last line break, so 0 is the first column
+}
+
+// write appends text, moving the print head past it
+func (l *printLine) write(text string) {
+	l.text.WriteString(text)
+	if index := strings.LastIndex(text, "\n"); index >= 0 {
+		l.column = utf8.RuneCountInString(text[index+1:])
+	} else {
+		l.column += utf8.RuneCountInString(text)
+	}
+}
+
+// nextZone pads the line to the start of the next print zone
+func (l *printLine) nextZone() {
+	zone := (l.column/PrintZoneWidth + 1) * PrintZoneWidth
+	l.write(strings.Repeat(" ", zone-l.column))
+}
+
+// tab pads the line up to the given 1-based column, starting a new line when the
+// print head is already past it
+func (l *printLine) tab(column int) {
+	target := max(column-1, 0)
+	if target < l.column {
+		l.write("\n")
+	}
+	l.write(strings.Repeat(" ", target-l.column))
+}
+
+// String returns the text built so far
+func (l *printLine) String() string {
+	return l.text.String()
+}
@@ -0,0 +1,95 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPrintLine_TabAndSpc(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []Expression
+		separators []rune
+		expected   string
+	}{
+		{
+			name:       "TAB moves to a column counted from 1",
+			items:      []Expression{NewTabExpression(num(10)), str("X")},
+			separators: []rune{';'},
+			expected:   "         X",
+		},
+		{
+			name:       "TAB after text pads up to the column",
+			items:      []Expression{str("AB"), NewTabExpression(num(6)), str("C")},
+			separators: []rune{';', ';'},
+			expected:   "AB   C",
+		},
+		{
+			name:       "TAB behind the print head starts a new line",
+			items:      []Expression{str("ABCDEF"), NewTabExpression(num(3)), str("Y")},
+			separators: []rune{';', ';'},
+			expected:   "ABCDEF\n  Y",
+		},
+		{
+			name:       "TAB to the current column does nothing",
+			items:      []Expression{str("ABC"), NewTabExpression(num(4)), str("D")},
+			separators: []rune{';', ';'},
+			expected:   "ABCD",
+		},
+		{
+			name:       "SPC writes spaces",
+			items:      []Expression{str("A"), NewSpcExpression(num(3)), str("B")},
+			separators: []rune{';', ';'},
+			expected:   "A   B",
+		},
+		{
+			name:       "SPC of a negative count writes nothing",
+			items:      []Expression{str("A"), NewSpcExpression(num(-2)), str("B")},
+			separators: []rune{';', ';'},
+			expected:   "AB",
+		},
+		{
+			name:       "SPC writes at most 255 spaces",
+			items:      []Expression{NewSpcExpression(num(3e9)), str("X")},
+			separators: []rune{';'},
+			expected:   strings.Repeat(" ", 255) + "X",
+		},
+		{
+			name:       "TAB goes at most to column 255",
+			items:      []Expression{NewTabExpression(num(1e300)), str("X")},
+			separators: []rune{';'},
+			expected:   strings.Repeat(" ", 254) + "X",
+		},
+		{
+			name:       "zones after TAB count from the new position",
+			items:      []Expression{NewTabExpression(num(20)), str("A"), str("B")},
+			separators: []rune{';', ','},
+			expected:   "                   A        B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, err := FormatPrintLine(tt.items, tt.separators, runtime.NewEnvironment())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, line)
+		})
+	}
+
+	t.Run("non-numeric arguments", func(t *testing.T) {
+		_, err := FormatPrintLine([]Expression{NewTabExpression(str("x"))}, nil, runtime.NewEnvironment())
+		assert.EqualError(t, err, "TAB column value must be numeric")
+
+		_, err = FormatPrintLine([]Expression{NewSpcExpression(str("x"))}, nil, runtime.NewEnvironment())
+		assert.EqualError(t, err, "SPC count value must be numeric")
+	})
+
+	t.Run("outside PRINT", func(t *testing.T) {
+		_, err := NewTabExpression(num(3)).Evaluate(runtime.NewEnvironment())
+		assert.EqualError(t, err, "TAB can only be used in PRINT")
+	})
+}
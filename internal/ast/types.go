@@ -0,0 +1,249 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+	"strings"
+)
+
+// Static type checking
+// CheckTypes looks for type errors that are certain from the program text alone, using
+// literal types and variable name suffixes (NAME$ holds a string, any other name a number).
+// Anything that depends on values only known at run time, such as adding a string to a
+// number or subtracting from a string variable that may hold "3", is left to the interpreter.
+
+// StaticType is the type of an expression as far as it is known without running the program
+type StaticType int
+
+const (
+	UnknownType StaticType = iota // Depends on values only known at run time
+	NumericType
+	StringType
+)
+
+// String returns the name of the type, as used in type mismatch messages
+func (t StaticType) String() string {
+	switch t {
+	case NumericType:
+		return "numeric"
+	case StringType:
+		return "string"
+	default:
+		return "unknown"
+	}
+}
+
+// TypeMismatch is a type error found by CheckTypes
+type TypeMismatch struct {
+	Line    int
+	Message string
+}
+
+// Error returns the mismatch with the line it was found on
+func (m TypeMismatch) Error() string {
+	return fmt.Sprintf("type mismatch at line %d: %s", m.Line, m.Message)
+}
+
+// InferType returns the type an expression is certain to have, or UnknownType
+// Functions are only known to return strings when their name ends in $
+func InferType(expr Expression) StaticType {
+	switch e := expr.(type) {
+	case *LiteralExpression:
+		if e.Value.Type == runtime.StringValue {
+			return StringType
+		}
+		return NumericType
+	case *VariableExpression:
+		return variableType(e.Name)
+	case *ArrayElementExpression:
+		return variableType(e.Name)
+	case *ParenthesesExpression:
+		return InferType(e.Expression)
+	case *BinaryExpression:
+		if e.Operator != OpAdd {
+			return NumericType
+		}
+		// + adds numbers and joins strings; a mix depends on whether the string holds a number
+		left, right := InferType(e.Left), InferType(e.Right)
+		if left == right {
+			return left
+		}
+		return UnknownType
+	case *ComparisonExpression, *LogicalExpression, *NotExpression:
+		return NumericType
+	case *SprintExpression:
+		return StringType
+	case *FunctionCallExpression:
+		if strings.HasSuffix(e.Name, "$") {
+			return StringType
+		}
+		return UnknownType
+	default:
+		return UnknownType
+	}
+}
+
+// variableType returns the type a variable holds according to its name
+func variableType(name string) StaticType {
+	if strings.HasSuffix(name, "$") {
+		return StringType
+	}
+	return NumericType
+}
+
+// CheckTypes returns the type mismatches in a program, in line order
+func CheckTypes(program *Program) []TypeMismatch {
+	if program == nil {
+		return nil
+	}
+
+	checker := &typeChecker{}
+	for _, lineNumber := range program.Order {
+		checker.line = lineNumber
+		checker.checkStatement(program.Lines[lineNumber])
+	}
+	return checker.mismatches
+}
+
+// typeChecker collects mismatches while walking the statements of one line at a time
+type typeChecker struct {
+	line       int
+	mismatches []TypeMismatch
+}
+
+// report records a mismatch on the current line
+func (c *typeChecker) report(format string, args ...interface{}) {
+	c.mismatches = append(c.mismatches, TypeMismatch{Line: c.line, Message: fmt.Sprintf(format, args...)})
+}
+
+// checkStatement checks the expressions of a statement, looking inside IF branches and compounds
+func (c *typeChecker) checkStatement(stmt Statement) {
+	switch s := stmt.(type) {
+	case *CompoundStatement:
+		for _, inner := range s.Statements {
+			c.checkStatement(inner)
+		}
+	case *IfStatement:
+		c.checkExpression(s.Condition)
+		c.checkStatement(s.ThenStatement)
+		if s.ElseStatement != nil {
+			c.checkStatement(s.ElseStatement)
+		}
+	case *AssignmentStatement:
+		c.checkExpression(s.Expression)
+		c.checkAssignment(s.Variable, s.Expression)
+	case *ArrayAssignmentStatement:
+		for _, index := range s.Indexes {
+			c.checkExpression(index)
+		}
+		c.checkExpression(s.Expression)
+		c.checkAssignment(s.Name, s.Expression)
+	case *DimStatement:
+		for _, array := range s.Arrays {
+			for _, size := range array.Sizes {
+				c.checkExpression(size)
+			}
+		}
+	case *ForStatement:
+		c.checkExpression(s.StartExpr)
+		c.checkExpression(s.EndExpr)
+		c.checkAssignment(s.Variable, s.StartExpr)
+		if s.StepExpr != nil {
+			c.checkExpression(s.StepExpr)
+		}
+	case *WhileStatement:
+		c.checkExpression(s.Condition)
+	case *DoStatement:
+		if s.Condition != nil {
+			c.checkExpression(s.Condition)
+		}
+	case *LoopStatement:
+		if s.Condition != nil {
+			c.checkExpression(s.Condition)
+		}
+	case *PrintStatement:
+		for _, expr := range s.Expressions {
+			c.checkExpression(expr)
+		}
+	case *AssertStatement:
+		c.checkExpression(s.Condition)
+	case *DefFnStatement:
+		c.checkExpression(s.Body)
+	case *EndStatement:
+		if s.ExitCode != nil {
+			c.checkExpression(s.ExitCode)
+		}
+	case *RandomizeStatement:
+		if s.Seed != nil {
+			c.checkExpression(s.Seed)
+		}
+	case *ShellStatement:
+		c.checkExpression(s.Command)
+	}
+}
+
+// checkAssignment reports a value whose type can never match the variable it is stored in
+func (c *typeChecker) checkAssignment(variable string, expr Expression) {
+	want, got := variableType(variable), InferType(expr)
+	if got != UnknownType && got != want {
+		c.report("%s value assigned to %s variable %s", got, want, variable)
+	}
+}
+
+// checkExpression reports arithmetic on string literals that can never be read as numbers
+func (c *typeChecker) checkExpression(expr Expression) {
+	switch e := expr.(type) {
+	case *ParenthesesExpression:
+		c.checkExpression(e.Expression)
+	case *BinaryExpression:
+		c.checkExpression(e.Left)
+		c.checkExpression(e.Right)
+		if e.Operator == OpAdd {
+			return
+		}
+		for _, operand := range []Expression{e.Left, e.Right} {
+			if isNonNumericString(operand) {
+				c.report("%s is not a number in %s", FormatExpression(operand), FormatExpression(e))
+				return
+			}
+		}
+	case *ComparisonExpression:
+		c.checkExpression(e.Left)
+		c.checkExpression(e.Right)
+	case *LogicalExpression:
+		c.checkExpression(e.Left)
+		c.checkExpression(e.Right)
+	case *NotExpression:
+		c.checkExpression(e.Operand)
+	case *ArrayElementExpression:
+		for _, index := range e.Indexes {
+			c.checkExpression(index)
+		}
+	case *FunctionCallExpression:
+		for _, arg := range e.Args {
+			c.checkExpression(arg)
+		}
+	case *SprintExpression:
+		for _, inner := range e.Expressions {
+			c.checkExpression(inner)
+		}
+	case *TabExpression:
+		c.checkExpression(e.Column)
+	case *SpcExpression:
+		c.checkExpression(e.Count)
+	}
+}
+
+// isNonNumericString reports whether an expression is a string literal that does not hold a number
+// Strings such as "3" are converted by arithmetic at run time, so they are not mismatches
+func isNonNumericString(expr Expression) bool {
+	if parens, ok := expr.(*ParenthesesExpression); ok {
+		return isNonNumericString(parens.Expression)
+	}
+	literal, ok := expr.(*LiteralExpression)
+	if !ok || literal.Value.Type != runtime.StringValue {
+		return false
+	}
+	_, err := literal.Value.ToNumber()
+	return err != nil
+}
@@ -0,0 +1,93 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func str(value string) Expression {
+	return NewLiteralExpression(runtime.NewStringValue(value))
+}
+
+func num(value float64) Expression {
+	return NewLiteralExpression(runtime.NewNumericValue(value))
+}
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     Expression
+		expected StaticType
+	}{
+		{"numeric literal", num(1), NumericType},
+		{"string literal", str("x"), StringType},
+		{"string variable", NewVariableExpression("A$"), StringType},
+		{"numeric variable", NewVariableExpression("A"), NumericType},
+		{"integer variable", NewVariableExpression("I%"), NumericType},
+		{"arithmetic", NewBinaryExpression(num(5), OpAdd, num(3)), NumericType},
+		{"concatenation", NewBinaryExpression(str("a"), OpAdd, NewVariableExpression("B$")), StringType},
+		{"mixed add depends on the string", NewBinaryExpression(str("3"), OpAdd, num(1)), UnknownType},
+		{"subtraction is numeric", NewBinaryExpression(str("x"), OpSubtract, str("y")), NumericType},
+		{"comparison", NewComparisonExpression(str("a"), "=", str("b")), NumericType},
+		{"parentheses", NewParenthesesExpression(str("x")), StringType},
+		{"string function", NewFunctionCallExpression("LEFT$", []Expression{str("abc"), num(1)}), StringType},
+		{"other function", NewFunctionCallExpression("FORMATNUM", []Expression{num(1)}), UnknownType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InferType(tt.expr))
+		})
+	}
+}
+
+func TestCheckTypes(t *testing.T) {
+	t.Run("string subtraction", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: NewAssignmentStatement("B", NewBinaryExpression(str("x"), OpSubtract, str("y"))),
+		}, []int{10})
+
+		mismatches := CheckTypes(program)
+
+		assert.Equal(t, []TypeMismatch{{Line: 10, Message: `"x" is not a number in ("x" - "y")`}}, mismatches)
+		assert.EqualError(t, mismatches[0], `type mismatch at line 10: "x" is not a number in ("x" - "y")`)
+	})
+
+	t.Run("numeric value assigned to string variable", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: NewAssignmentStatement("A$", NewBinaryExpression(num(5), OpAdd, num(3))),
+			20: NewAssignmentStatement("B", str("x")),
+		}, []int{10, 20})
+
+		assert.Equal(t, []TypeMismatch{
+			{Line: 10, Message: "numeric value assigned to string variable A$"},
+			{Line: 20, Message: "string value assigned to numeric variable B"},
+		}, CheckTypes(program))
+	})
+
+	t.Run("mismatches inside IF branches and compounds", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: NewIfElseStatement(
+				NewComparisonExpression(NewVariableExpression("A"), "=", num(1)),
+				NewAssignmentStatement("A$", num(1)),
+				NewCompoundStatement([]Statement{printLiteral("ok"), NewPrintStatement([]Expression{NewBinaryExpression(str("x"), OpMultiply, num(2))}, nil)}),
+			),
+		}, []int{10})
+
+		assert.Len(t, CheckTypes(program), 2)
+	})
+
+	t.Run("ambiguous cases are left to run time", func(t *testing.T) {
+		program := newTestProgram(map[int]Statement{
+			10: NewAssignmentStatement("A", NewBinaryExpression(str("3"), OpAdd, num(1))),
+			20: NewAssignmentStatement("B", NewBinaryExpression(NewVariableExpression("A$"), OpSubtract, num(1))),
+			30: NewAssignmentStatement("C", NewBinaryExpression(str("3"), OpMultiply, num(2))),
+			40: NewAssignmentStatement("D", NewFunctionCallExpression("VAL", []Expression{str("3")})),
+			50: NewAssignmentStatement("N$", NewBinaryExpression(str("a"), OpAdd, NewVariableExpression("M$"))),
+		}, []int{10, 20, 30, 40, 50})
+
+		assert.Empty(t, CheckTypes(program))
+	})
+}
@@ -0,0 +1,124 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"fmt"
+	"sort"
+)
+
+// WHILE and WEND
+// WHILE pushes the loop and its condition onto the environment when the condition holds,
+// and otherwise skips past the matching WEND. WEND evaluates the condition again and
+// either jumps back to run the body once more or ends the loop.
+
+// WhileStatement represents a WHILE statement opening a loop that runs while its condition holds
+type WhileStatement struct {
+	Condition Expression
+	LineNum   int
+	Program   *Program // Searched for the matching WEND when the condition is false
+}
+
+// NewWhileStatement creates a new WHILE statement on the given line
+func NewWhileStatement(condition Expression, lineNum int) *WhileStatement {
+	return &WhileStatement{
+		Condition: condition,
+		LineNum:   lineNum,
+	}
+}
+
+// Execute enters the loop when the condition holds, or jumps past the matching WEND
+func (w *WhileStatement) Execute(env *runtime.Environment) error {
+	value, err := w.Condition.Evaluate(env)
+	if err != nil {
+		return fmt.Errorf("error evaluating WHILE condition: %w", err)
+	}
+
+	if IsTrue(value) {
+		env.WhileLoops = append(env.WhileLoops, runtime.WhileLoopState{
+			LineNum:   w.LineNum,
+			Body:      env.Statement + 1,
+			Condition: w.Condition,
+		})
+		return nil
+	}
+
+	exit, found := loopExit(w.Program, w.LineNum, w, isWhile, isWend)
+	if !found {
+		return fmt.Errorf("WHILE without WEND")
+	}
+	env.ResumeAt(exit)
+	return nil
+}
+
+// WendStatement represents a WEND statement closing the innermost WHILE loop
+type WendStatement struct{}
+
+// NewWendStatement creates a new WEND statement
+func NewWendStatement() *WendStatement {
+	return &WendStatement{}
+}
+
+// Execute evaluates the innermost loop's condition again, jumping back to the start of
+// its body while it holds and removing the loop once it no longer does
+func (w *WendStatement) Execute(env *runtime.Environment) error {
+	if len(env.WhileLoops) == 0 {
+		return fmt.Errorf("WEND without WHILE")
+	}
+
+	loop := env.WhileLoops[len(env.WhileLoops)-1]
+	value, err := loop.Condition.Evaluate(env)
+	if err != nil {
+		return fmt.Errorf("error evaluating WHILE condition: %w", err)
+	}
+
+	if IsTrue(value) {
+		env.ResumeAt(runtime.ResumePoint{Line: loop.LineNum, Statement: loop.Body})
+	} else {
+		env.WhileLoops = env.WhileLoops[:len(env.WhileLoops)-1]
+	}
+	return nil
+}
+
+// MatchingWend returns the line of the WEND that closes the WHILE on the given line
+// WHILE loops nested inside are skipped along with their own WEND
+func MatchingWend(program *Program, whileLine int) (int, bool) {
+	exit, found := loopExit(program, whileLine, nil, isWhile, isWend)
+	return exit.Line, found
+}
+
+func isWhile(stmt Statement) bool { _, ok := stmt.(*WhileStatement); return ok }
+func isWend(stmt Statement) bool  { _, ok := stmt.(*WendStatement); return ok }
+
+// loopExit returns where execution goes on after the statement closing the loop that the
+// start statement opens on the given line; a nil start means the first loop on the line
+// Loops of the same kind nested inside are skipped along with their own closing statement
+func loopExit(program *Program, startLine int, start Statement, opens, closes func(Statement) bool) (runtime.ResumePoint, bool) {
+	if program == nil {
+		return runtime.ResumePoint{}, false
+	}
+
+	depth := 0
+	skipping := start != nil
+	if skipping {
+		depth = 1
+	}
+	for _, lineNumber := range program.Order[sort.SearchInts(program.Order, startLine):] {
+		for _, stmt := range lineStatements(program.Lines[lineNumber]) {
+			if skipping {
+				skipping = lineNumber == startLine && stmt != start
+				if lineNumber == startLine {
+					continue
+				}
+			}
+			if opens(stmt) {
+				depth++
+			} else if closes(stmt) {
+				depth--
+				if depth == 0 {
+					return statementAfter(program, lineNumber, stmt), true
+				}
+			}
+		}
+	}
+	return runtime.ResumePoint{}, false
+}
@@ -0,0 +1,69 @@
+package ast
+
+import (
+	"basic-interpreter/internal/runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhileStatement_Execute(t *testing.T) {
+	condition := NewComparisonExpression(NewVariableExpression("I"), "<", num(3))
+
+	t.Run("true condition enters the loop", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.ProgramCounter = 20
+
+		require.NoError(t, NewWhileStatement(condition, 20).Execute(env))
+		require.Len(t, env.WhileLoops, 1)
+		assert.Equal(t, 20, env.WhileLoops[0].LineNum)
+		assert.Equal(t, 20, env.ProgramCounter)
+	})
+
+	t.Run("false condition jumps past the matching WEND", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		env.SetVariable("I", runtime.NewNumericValue(3))
+		while := NewWhileStatement(condition, 20)
+		newTestProgram(map[int]Statement{
+			20: while,
+			30: NewWhileStatement(num(1), 30),
+			40: NewWendStatement(),
+			50: NewWendStatement(),
+			60: printLiteral("after"),
+		}, []int{20, 30, 40, 50, 60})
+		env.ProgramCounter = 20
+
+		require.NoError(t, while.Execute(env))
+		assert.Empty(t, env.WhileLoops)
+		assert.True(t, env.Jumped)
+		assert.Equal(t, runtime.ResumePoint{Line: 50, Statement: 1}, env.JumpTarget, "goes on after the outer WEND")
+	})
+
+	t.Run("false condition without WEND", func(t *testing.T) {
+		env := runtime.NewEnvironment()
+		while := NewWhileStatement(num(0), 20)
+		newTestProgram(map[int]Statement{20: while}, []int{20})
+
+		assert.EqualError(t, while.Execute(env), "WHILE without WEND")
+	})
+}
+
+func TestWendStatement_Execute(t *testing.T) {
+	env := runtime.NewEnvironment()
+	condition := NewComparisonExpression(NewVariableExpression("I"), "<", num(3))
+	require.NoError(t, NewWhileStatement(condition, 20).Execute(env))
+
+	env.ProgramCounter = 50
+	require.NoError(t, NewWendStatement().Execute(env))
+	assert.Equal(t, 20, env.ProgramCounter, "jumps back while the condition holds")
+	assert.Len(t, env.WhileLoops, 1)
+
+	env.SetVariable("I", runtime.NewNumericValue(3))
+	env.ProgramCounter = 50
+	require.NoError(t, NewWendStatement().Execute(env))
+	assert.Equal(t, 50, env.ProgramCounter)
+	assert.Empty(t, env.WhileLoops, "the loop ends once the condition fails")
+
+	assert.EqualError(t, NewWendStatement().Execute(env), "WEND without WHILE")
+}
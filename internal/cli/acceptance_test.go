@@ -32,7 +32,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 			name: "print multiple values",
 			program: `10 PRINT "Value:", 42
 20 END`,
-			expected: []string{"Value: 42"},
+			expected: []string{"Value:         42"},
 		},
 		{
 			name: "print string variable",
@@ -57,7 +57,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 30 C = A + B
 40 PRINT "Sum:", C
 50 END`,
-			expected: []string{"Sum: 15"},
+			expected: []string{"Sum:           15"},
 		},
 		{
 			name: "complex arithmetic",
@@ -85,7 +85,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 30 NEXT I
 40 PRINT "Done!"
 50 END`,
-			expected: []string{"Count: 1", "Count: 2", "Count: 3", "Done!"},
+			expected: []string{"Count:         1", "Count:         2", "Count:         3", "Done!"},
 		},
 		{
 			name: "for loop with step",
@@ -93,7 +93,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 20 PRINT "Even:", I
 30 NEXT I
 40 END`,
-			expected: []string{"Even: 2", "Even: 4", "Even: 6", "Even: 8", "Even: 10"},
+			expected: []string{"Even:          2", "Even:          4", "Even:          6", "Even:          8", "Even:          10"},
 		},
 		{
 			name: "countdown loop",
@@ -102,7 +102,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 30 NEXT I
 40 PRINT "Blast off!"
 50 END`,
-			expected: []string{"Countdown: 5", "Countdown: 4", "Countdown: 3", "Countdown: 2", "Countdown: 1", "Blast off!"},
+			expected: []string{"Countdown:     5", "Countdown:     4", "Countdown:     3", "Countdown:     2", "Countdown:     1", "Blast off!"},
 		},
 		{
 			name: "nested loops",
@@ -112,7 +112,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 40 NEXT J
 50 NEXT I
 60 END`,
-			expected: []string{"I= 1 J= 1", "I= 1 J= 2", "I= 2 J= 1", "I= 2 J= 2"},
+			expected: []string{"I=             1            J=             1", "I=             1            J=             2", "I=             2            J=             1", "I=             2            J=             2"},
 		},
 		
 		// IF statements
@@ -147,7 +147,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 20 PRINT "You entered:", X
 30 END`,
 			inputs:   []string{"42"},
-			expected: []string{"You entered: 42"},
+			expected: []string{"You entered:   42"},
 		},
 		{
 			name: "string input",
@@ -155,7 +155,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 20 PRINT "Hello", NAME$
 30 END`,
 			inputs:   []string{"World"},
-			expected: []string{"Hello World"},
+			expected: []string{"Hello         World"},
 		},
 		{
 			name: "input with prompt",
@@ -163,7 +163,15 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 20 PRINT "You are", AGE, "years old"
 30 END`,
 			inputs:   []string{"25"},
-			expected: []string{"Enter your age: ", "You are 25 years old"},
+			expected: []string{"Enter your age: ", "You are        25           years old"},
+		},
+		{
+			name: "input with range reprompts",
+			program: `10 INPUT "Pick 1-10: "; N RANGE 1 TO 10
+20 PRINT "Picked", N
+30 END`,
+			inputs:   []string{"11", "7"},
+			expected: []string{"Pick 1-10: ", "Please enter a number from 1 to 10", "Pick 1-10: ", "Picked         7"},
 		},
 		
 		// Built-in functions
@@ -227,12 +235,12 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 100 END`,
 			expected: []string{
 				"BASIC Calculator",
-				"A = 10",
-				"B = 5",
-				"A + B = 15",
-				"A - B = 5",
-				"A * B = 50",
-				"A / B = 2",
+				"A =            10",
+				"B =            5",
+				"A + B =        15",
+				"A - B =        5",
+				"A * B =        50",
+				"A / B =        2",
 			},
 		},
 		{
@@ -258,7 +266,7 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 40 PRINT "Product:", A * B
 50 END`,
 			inputs:   []string{"6", "7"},
-			expected: []string{"Enter first number: ", "Enter second number: ", "Sum: 13", "Product: 42"},
+			expected: []string{"Enter first number: ", "Enter second number: ", "Sum:           13", "Product:       42"},
 		},
 		
 		// Comments and empty lines
@@ -280,6 +288,110 @@ func TestBasicInterpreter_AcceptanceTests(t *testing.T) {
 			wantErr:     true,
 			errContains: "syntax error",
 		},
+		{
+			name: "integer variable truncates division at assignment",
+			program: `10 I% = 7 / 2
+20 J% = -7 / 2
+30 PRINT I%
+40 PRINT J%
+50 PRINT 7 / 2
+60 END`,
+			expected: []string{"3", "-3", "3.5"},
+		},
+		{
+			name: "pause waits for a key and continues",
+			program: `10 PRINT "Before"
+20 PAUSE
+30 PRINT "After"
+40 END`,
+			inputs:   []string{""},
+			expected: []string{"Before", "Press any key to continue", "After"},
+		},
+		{
+			name: "pause with custom prompt",
+			program: `10 PAUSE "Hit a key"
+20 PRINT "Done"
+30 END`,
+			inputs:   []string{"x"},
+			expected: []string{"Hit a key", "Done"},
+		},
+		{
+			name: "MOD follows the sign of the dividend",
+			program: `10 PRINT 7 MOD 3
+20 PRINT -7 MOD 3
+30 PRINT 7 MOD -3
+40 PRINT 2 + 10 MOD 4 * 2
+50 END`,
+			expected: []string{"1", "-1", "1", "6"},
+		},
+		{
+			name: "runtime error - MOD by zero",
+			program: `10 PRINT 5 MOD 0
+20 END`,
+			wantErr:     true,
+			errContains: "division by zero",
+		},
+		{
+			name: "FORMATNUM$ groups thousands",
+			program: `10 A = 1234567.5
+20 PRINT "Total:", FORMATNUM$(A, 2)
+30 END`,
+			expected: []string{"Total:        1,234,567.50"},
+		},
+		{
+			name: "UCASE$ compares answers regardless of case",
+			program: `10 A$ = "Yes"
+20 IF UCASE$(A$) = "YES" THEN PRINT "agreed"
+30 PRINT LCASE$("Mixed Case 42")
+40 END`,
+			expected: []string{"agreed", "mixed case 42"},
+		},
+		{
+			name: "passing assertion produces no output",
+			program: `10 A = 2 + 2
+20 ASSERT A = 4
+30 PRINT "OK"
+40 END`,
+			expected: []string{"OK"},
+		},
+		{
+			name: "failing assertion stops with its line number",
+			program: `10 A = 2 + 2
+20 ASSERT A = 5
+30 PRINT "Not reached"
+40 END`,
+			wantErr:     true,
+			errContains: "assertion failed at line 20",
+		},
+		{
+			name: "colon-separated THEN statements all run when true",
+			program: `10 X = 1
+20 IF X = 1 THEN A = 1 : B = 2 : PRINT "Then", A, B
+30 PRINT "After"
+40 END`,
+			expected: []string{"Then           1             2", "After"},
+		},
+		{
+			name: "colon-separated THEN statements are all skipped when false",
+			program: `10 X = 0
+20 IF X = 1 THEN A = 1 : B = 2 : PRINT "Then"
+30 PRINT "A =", A, "B =", B
+40 END`,
+			expected: []string{"A =            0            B =            0"},
+		},
+		{
+			name: "negative base to integer power",
+			program: `10 PRINT (-2) ^ 3
+20 END`,
+			expected: []string{"-8"},
+		},
+		{
+			name: "runtime error - negative base to fractional power",
+			program: `10 PRINT (-4) ^ 0.5
+20 END`,
+			wantErr:     true,
+			errContains: "negative base to fractional power",
+		},
 		{
 			name: "runtime error - division by zero",
 			program: `10 A = 1 / 0
@@ -472,7 +584,7 @@ func TestBasicInterpreter_VariableScoping(t *testing.T) {
 	require.NoError(t, err)
 	
 	// Check that variable A is modified correctly through the loop
-	expectedValues := []string{"A = 6", "A = 8", "A = 11", "Final A = 11"}
+	expectedValues := []string{"A =            6", "A =            8", "A =            11", "Final A =      11"}
 	
 	for _, expected := range expectedValues {
 		found := false
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"basic-interpreter/internal/ast"
+	"basic-interpreter/internal/lexer"
+	"basic-interpreter/internal/parser"
+	"fmt"
+)
+
+// CheckFile loads a BASIC program and reports its syntax errors and jumps to missing lines without executing it
+// Each problem is written to the output; an error is returned when there is any, so the check can fail a build
+func (fe *FileExecutor) CheckFile(filename string) error {
+	content, err := fe.readFile(filename)
+	if err != nil {
+		return fe.wrapFileError("failed to read file", filename, err)
+	}
+
+	content, err = fe.expandIncludes(content, filename)
+	if err != nil {
+		return fe.wrapFileError("failed to expand includes in", filename, err)
+	}
+
+	// Load the program as a run does, so that for example a repeated line number replaces
+	// the earlier line instead of being reported
+	var problems []string
+	program, err := fe.parseProgram(content)
+	if err != nil {
+		problems = []string{"Error: " + err.Error()}
+	} else {
+		problems = CheckSource(fe.programToSourceCode(program))
+	}
+	for _, problem := range problems {
+		fe.output.WriteLine(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in %s", len(problems), filename)
+	}
+	return nil
+}
+
+// CheckSource parses a program and returns a message for every line that cannot be parsed
+// and every GOTO or GOSUB to a line that is not in the source at all
+func CheckSource(source string) []string {
+	program, syntaxErrors := parser.NewParser(lexer.NewLexer(source)).ParseProgramCollectingErrors()
+
+	var problems []string
+	unparsed := make(map[int]bool)
+	for _, syntaxError := range syntaxErrors {
+		problems = append(problems, "Error: "+syntaxError.Error())
+		unparsed[syntaxError.Line] = true
+	}
+	for _, jump := range ast.MissingJumpTargets(program) {
+		if unparsed[jump.Target] {
+			continue // The line is there, it just could not be parsed
+		}
+		problems = append(problems, "Error: "+jump.Error())
+	}
+	return problems
+}
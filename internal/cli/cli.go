@@ -1,16 +1,32 @@
 package cli
 
 import (
+	"basic-interpreter/internal/runtime"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
 // Config holds the parsed command line configuration
 type Config struct {
-	DebugMode   bool
-	Interactive bool
-	InputFile   string
+	DebugMode      bool
+	TraceMode      bool
+	LintMode       bool
+	CheckOnly      bool // Parse and check the program without running it
+	Deterministic  bool
+	AllowShell     bool
+	CleanNumbers   bool
+	StrictNext     bool
+	CoerceCompare  bool
+	DecimalComma   bool
+	Interactive    bool
+	InputFile      string
+	TranscriptFile string
+	RecordFile     string // Interactive session input is saved here
+	ReplayFile     string // Interactive session input is read from here
+	Variables      map[string]runtime.Value // Set with -D NAME=value before the program runs
+	MaxSteps       int                      // Set with --max-steps; 0 keeps the default, -1 means no limit
 }
 
 // CLI handles command line argument parsing
@@ -46,8 +62,66 @@ func (c *CLI) ParseArgs(args []string) (*Config, error) {
 			return nil, errors.New("help requested")
 		case "-d", "--debug":
 			config.DebugMode = true
+		case "--trace":
+			config.TraceMode = true
+		case "--lint":
+			config.LintMode = true
+		case "--check":
+			config.CheckOnly = true
+		case "--deterministic":
+			config.Deterministic = true
+		case "--allow-shell":
+			config.AllowShell = true
+		case "--clean-numbers":
+			config.CleanNumbers = true
+		case "--strict-next":
+			config.StrictNext = true
+		case "--coerce-compare":
+			config.CoerceCompare = true
+		case "--decimal-comma":
+			config.DecimalComma = true
+		case "--transcript":
+			if i+1 >= len(args) {
+				return nil, errors.New("--transcript requires a file name")
+			}
+			i++
+			config.TranscriptFile = args[i]
+		case "--record":
+			if i+1 >= len(args) {
+				return nil, errors.New("--record requires a file name")
+			}
+			i++
+			config.RecordFile = args[i]
+		case "--replay":
+			if i+1 >= len(args) {
+				return nil, errors.New("--replay requires a file name")
+			}
+			i++
+			config.ReplayFile = args[i]
+		case "--max-steps":
+			if i+1 >= len(args) {
+				return nil, errors.New("--max-steps requires a number of steps")
+			}
+			i++
+			if err := config.setMaxSteps(args[i]); err != nil {
+				return nil, err
+			}
+		case "-D":
+			if i+1 >= len(args) {
+				return nil, errors.New("-D requires NAME=value")
+			}
+			i++
+			if err := config.defineVariable(args[i]); err != nil {
+				return nil, err
+			}
 		default:
-			if strings.HasPrefix(arg, "-") {
+			if strings.HasPrefix(arg, "-D") {
+				if err := config.defineVariable(arg[len("-D"):]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if strings.HasPrefix(arg, "-") && arg != StdinFileName {
 				return nil, fmt.Errorf("unknown flag: %s", arg)
 			}
 			fileArgs = append(fileArgs, arg)
@@ -69,9 +143,116 @@ func (c *CLI) ParseArgs(args []string) (*Config, error) {
 		return nil, errors.New("debug mode requires a file")
 	}
 	
+	if config.TraceMode && config.Interactive {
+		return nil, errors.New("trace mode requires a file")
+	}
+	
+	if config.LintMode && config.Interactive {
+		return nil, errors.New("lint mode requires a file")
+	}
+	
+	if config.CheckOnly && config.Interactive {
+		return nil, errors.New("--check requires a file")
+	}
+	
+	if config.Deterministic && config.Interactive {
+		return nil, errors.New("deterministic mode requires a file")
+	}
+	
+	if config.AllowShell && config.Interactive {
+		return nil, errors.New("--allow-shell requires a file")
+	}
+	
+	if config.CleanNumbers && config.Interactive {
+		return nil, errors.New("--clean-numbers requires a file")
+	}
+	
+	if config.StrictNext && config.Interactive {
+		return nil, errors.New("--strict-next requires a file")
+	}
+	
+	if config.CoerceCompare && config.Interactive {
+		return nil, errors.New("--coerce-compare requires a file")
+	}
+	
+	if config.DecimalComma && config.Interactive {
+		return nil, errors.New("--decimal-comma requires a file")
+	}
+	
+	if len(config.Variables) > 0 && config.Interactive {
+		return nil, errors.New("-D requires a file")
+	}
+	
+	if config.MaxSteps != 0 && config.Interactive {
+		return nil, errors.New("--max-steps requires a file")
+	}
+	
+	if config.RecordFile != "" && !config.Interactive {
+		return nil, errors.New("--record is only available in interactive mode")
+	}
+	
+	if config.ReplayFile != "" && !config.Interactive {
+		return nil, errors.New("--replay is only available in interactive mode")
+	}
+	
 	return config, nil
 }
 
+// defineVariable records a -D NAME=value definition
+// Names ending in $ get the value as a string; other names must be given a number
+func (config *Config) defineVariable(definition string) error {
+	name, text, found := strings.Cut(definition, "=")
+	name = strings.TrimSpace(name)
+	if !found || !isVariableName(name) {
+		return fmt.Errorf("invalid -D definition %q: expected NAME=value", definition)
+	}
+	
+	value := runtime.NewStringValue(text)
+	if !strings.HasSuffix(name, "$") {
+		number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return fmt.Errorf("invalid -D definition %q: %s needs a numeric value", definition, name)
+		}
+		value = runtime.NewNumericValue(number)
+	}
+	
+	if config.Variables == nil {
+		config.Variables = make(map[string]runtime.Value)
+	}
+	config.Variables[name] = value
+	return nil
+}
+
+// setMaxSteps records the --max-steps limit, where 0 lifts the limit altogether
+func (config *Config) setMaxSteps(text string) error {
+	steps, err := strconv.Atoi(text)
+	if err != nil || steps < 0 {
+		return fmt.Errorf("invalid --max-steps %q: expected a number of steps, or 0 for no limit", text)
+	}
+	
+	config.MaxSteps = steps
+	if steps == 0 {
+		config.MaxSteps = -1
+	}
+	return nil
+}
+
+// isVariableName checks that name is a letter followed by letters, digits or
+// underscores, with an optional $ or % type suffix
+func isVariableName(name string) bool {
+	base := strings.TrimRight(name, "$%")
+	if base == "" || len(name)-len(base) > 1 {
+		return false
+	}
+	for i, ch := range base {
+		isLetter := ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch == '_'
+		if !isLetter && (i == 0 || ch < '0' || ch > '9') {
+			return false
+		}
+	}
+	return true
+}
+
 // GetHelpMessage returns the help message
 func (c *CLI) GetHelpMessage() string {
 	return `BASIC Interpreter
@@ -81,15 +262,56 @@ Usage:
 
 Options:
   -d, --debug    Enable debug mode (shows each line before execution)
+      --trace    Show each line number after it runs, with the variables it changed
+      --lint     Report unreachable lines, fall-through into subroutines and certain
+                 type mismatches as warnings without running the program
+      --check    Report every syntax error and jump to a missing line without
+                 running the program; exits with status 1 if there are any
+      --deterministic
+                 Use a fixed random seed so RND produces the same sequence every run
+      --transcript file
+                 Record output and consumed input, interleaved, to file
+      --allow-shell
+                 Let SHELL run external commands with your privileges;
+                 only use with programs you trust
+      --clean-numbers
+                 Print numbers without the space classic BASIC puts before positive ones,
+                 for clean output such as data export
+      --strict-next
+                 Reject NEXT without a variable instead of closing the innermost loop
+      --coerce-compare
+                 Let comparisons mix numbers and numeric strings, so 5 = "5" is true
+      --decimal-comma
+                 Use a comma as decimal separator in PRINT, STR$, VAL and numeric INPUT,
+                 with a period between thousands: 3,14 and 1.234,5
+  -D NAME=value  Set a variable before the program runs (repeatable);
+                 NAME$ takes a string, other names a number
+      --max-steps N
+                 Stop the program as a likely infinite loop after N statements
+                 (default 10000000; 0 for no limit)
+      --record file
+                 Interactive mode: save every line typed in the session to file
+      --replay file
+                 Interactive mode: read the session's input from a recorded file
   -h, --help     Show this help message
 
 Arguments:
-  file           BASIC program file to execute
+  file           BASIC program file to execute, or - to read it from standard input;
+                 a program read that way cannot also read INPUT from the keyboard
 
 Examples:
   basic-interpreter                    # Interactive mode
   basic-interpreter program.bas       # Execute file
   basic-interpreter -d program.bas    # Execute file with debug output
+  cat program.bas | basic-interpreter - # Execute a program piped to standard input
+  basic-interpreter --trace program.bas # Follow how each line changes the variables
+  basic-interpreter --lint program.bas # Check file for unreachable lines and type mismatches
+  basic-interpreter --check program.bas # Check file for syntax errors, as in CI
+  basic-interpreter --transcript run.txt program.bas # Record a replayable transcript
+  basic-interpreter -D N=10 -D NAME$=Ada program.bas # Run with preset variables
+  basic-interpreter --max-steps 0 program.bas # Let a long simulation run to the end
+  basic-interpreter --record session.txt # Record an interactive session
+  basic-interpreter --replay session.txt # Replay it to reproduce a problem
 `
 }
 
@@ -108,5 +330,45 @@ func (config *Config) Validate() error {
 		return errors.New("debug mode is only available for file execution")
 	}
 	
+	if config.TraceMode && config.Interactive {
+		return errors.New("trace mode is only available for file execution")
+	}
+	
+	if config.LintMode && config.Interactive {
+		return errors.New("lint mode is only available for file execution")
+	}
+	
+	if config.CheckOnly && config.Interactive {
+		return errors.New("--check is only available for file execution")
+	}
+	
+	if config.Deterministic && config.Interactive {
+		return errors.New("deterministic mode is only available for file execution")
+	}
+	
+	if config.AllowShell && config.Interactive {
+		return errors.New("--allow-shell is only available for file execution")
+	}
+	
+	if config.CleanNumbers && config.Interactive {
+		return errors.New("--clean-numbers is only available for file execution")
+	}
+	
+	if config.StrictNext && config.Interactive {
+		return errors.New("--strict-next is only available for file execution")
+	}
+	
+	if config.CoerceCompare && config.Interactive {
+		return errors.New("--coerce-compare is only available for file execution")
+	}
+	
+	if config.DecimalComma && config.Interactive {
+		return errors.New("--decimal-comma is only available for file execution")
+	}
+	
+	if config.MaxSteps != 0 && config.Interactive {
+		return errors.New("--max-steps is only available for file execution")
+	}
+	
 	return nil
 }
\ No newline at end of file
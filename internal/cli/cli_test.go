@@ -1,8 +1,13 @@
 package cli
 
 import (
+	"basic-interpreter/internal/interpreter"
+	"basic-interpreter/internal/runtime"
+	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -35,6 +40,15 @@ func TestCLI_ParseArgs_DebugFlag(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "trace flag",
+			args: []string{"program", "--trace", "test.bas"},
+			expected: &Config{
+				TraceMode: true,
+				InputFile: "test.bas",
+			},
+			wantErr: false,
+		},
 		{
 			name: "no debug flag",
 			args: []string{"program", "test.bas"},
@@ -162,6 +176,12 @@ func TestCLI_ParseArgs_ErrorCases(t *testing.T) {
 			wantErr: true,
 			errMsg:  "debug mode requires a file",
 		},
+		{
+			name:    "trace flag without file",
+			args:    []string{"program", "--trace"},
+			wantErr: true,
+			errMsg:  "trace mode requires a file",
+		},
 		{
 			name:    "conflicting flags",
 			args:    []string{"program", "-h", "-d", "test.bas"},
@@ -188,6 +208,8 @@ func TestCLI_GetHelpMessage(t *testing.T) {
 	assert.Contains(t, help, "BASIC Interpreter")
 	assert.Contains(t, help, "Usage:")
 	assert.Contains(t, help, "-d, --debug")
+	assert.Contains(t, help, "--trace")
+	assert.Contains(t, help, "--check")
 	assert.Contains(t, help, "-h, --help")
 	assert.Contains(t, help, "Examples:")
 	assert.Contains(t, help, "Interactive mode")
@@ -242,6 +264,24 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "debug mode is only available for file execution",
 		},
+		{
+			name: "invalid - trace without file",
+			config: &Config{
+				TraceMode:   true,
+				Interactive: true,
+			},
+			wantErr: true,
+			errMsg:  "trace mode is only available for file execution",
+		},
+		{
+			name: "invalid - check without file",
+			config: &Config{
+				CheckOnly:   true,
+				Interactive: true,
+			},
+			wantErr: true,
+			errMsg:  "--check is only available for file execution",
+		},
 	}
 
 	for _, tt := range tests {
@@ -683,10 +723,10 @@ func TestCLI_FileExecution_Integration(t *testing.T) {
 	
 	// Check for expected outputs
 	expectedOutputs := []string{
-		"Sum: 30",
-		"Loop iteration: 1",
-		"Loop iteration: 2", 
-		"Loop iteration: 3",
+		"Sum:           30",
+		"Loop iteration:              1",
+		"Loop iteration:              2", 
+		"Loop iteration:              3",
 		"C is greater than 25",
 	}
 	
@@ -747,4 +787,994 @@ func removeTempFile(t *testing.T, filename string) {
 	if err != nil {
 		t.Logf("Warning: could not remove temp file %s: %v", filename, err)
 	}
-}
\ No newline at end of file
+}
+
+func TestCLI_ParseArgs_LintFlag(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "--lint", "test.bas"})
+	require.NoError(t, err)
+	assert.True(t, config.LintMode)
+	assert.Equal(t, "test.bas", config.InputFile)
+
+	_, err = cli.ParseArgs([]string{"program", "--lint"})
+	assert.Error(t, err, "lint mode should require a file")
+}
+
+func TestCLI_LintFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  string
+		expected []string
+	}{
+		{
+			name: "line after GOTO without inbound reference is flagged",
+			program: `10 PRINT "Start"
+20 GOTO 40
+30 PRINT "Never"
+40 END`,
+			expected: []string{"Warning: line 30 is unreachable"},
+		},
+		{
+			name: "GOTO target is not flagged",
+			program: `10 GOTO 30
+20 END
+30 PRINT "Target"
+40 GOTO 20`,
+			expected: nil,
+		},
+		{
+			name: "fall-through into a subroutine is flagged",
+			program: `10 GOSUB 100
+20 PRINT "Done"
+90 REM -- Greet
+100 PRINT "Hello"
+110 RETURN`,
+			expected: []string{"Warning: line 20 falls through into subroutine at line 100"},
+		},
+		{
+			name: "certain type mismatches are flagged",
+			program: `10 A$ = 5 + 3
+20 B = "x" - "y"
+30 C = "3" + 1`,
+			expected: []string{
+				"Warning: type mismatch at line 10: numeric value assigned to string variable A$",
+				`Warning: type mismatch at line 20: "x" is not a number in ("x" - "y")`,
+			},
+		},
+		{
+			name: "jump to a missing line is reported as --check reports it",
+			program: `10 PRINT "Start"
+20 GOTO 99`,
+			expected: []string{"Error: line 20 jumps to line 99, which does not exist"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := createTempFile(t, tt.program)
+			defer removeTempFile(t, tmpFile)
+
+			mockOutput := &MockOutputWriter{}
+			fileExecutor := NewFileExecutor(&MockInputReader{}, mockOutput)
+
+			err := fileExecutor.LintFile(tmpFile)
+
+			require.NoError(t, err, "warnings should not fail the lint run")
+			assert.Equal(t, tt.expected, mockOutput.outputs)
+		})
+	}
+}
+
+func TestCLI_ParseArgs_CheckFlag(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "--check", "test.bas"})
+	require.NoError(t, err)
+	assert.True(t, config.CheckOnly)
+	assert.Equal(t, "test.bas", config.InputFile)
+
+	_, err = cli.ParseArgs([]string{"program", "--check"})
+	assert.EqualError(t, err, "--check requires a file")
+}
+
+func TestCLI_CheckFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  string
+		expected []string
+	}{
+		{
+			name: "valid program has no problems",
+			program: `10 GOSUB 100
+20 IF X > 1 THEN GOTO 10
+30 END
+100 PRINT "Hello"
+110 RETURN`,
+			expected: nil,
+		},
+		{
+			name: "jump to a missing line is reported",
+			program: `10 PRINT "Start"
+20 GOTO 99
+30 IF X THEN GOTO 40 ELSE GOSUB 200
+40 END`,
+			expected: []string{
+				"Error: line 20 jumps to line 99, which does not exist",
+				"Error: line 30 jumps to line 200, which does not exist",
+			},
+		},
+		{
+			name: "every syntax error is reported",
+			program: `10 FOR I = 1 10
+20 PRINT I
+30 X = * 2
+40 GOTO 10`,
+			expected: []string{
+				"Error: error parsing statement at BASIC line 10: expected TO in FOR statement at line 1, column 14",
+				"Error: error parsing statement at BASIC line 30: error parsing assignment expression: unexpected token in expression: * at line 3, column 8",
+			},
+		},
+		{
+			name: "a repeated line number replaces the earlier line, as when running",
+			program: `10 PRINT "A"
+10 PRINT "B"
+20 END`,
+			expected: nil,
+		},
+		{
+			name: "a line without a number is reported as when running",
+			program: `10 PRINT "A"
+PRINT "B"`,
+			expected: []string{"Error: invalid line number at line 2: PRINT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := createTempFile(t, tt.program)
+			defer removeTempFile(t, tmpFile)
+
+			mockOutput := &MockOutputWriter{}
+			fileExecutor := NewFileExecutor(&MockInputReader{}, mockOutput)
+
+			err := fileExecutor.CheckFile(tmpFile)
+
+			assert.Equal(t, tt.expected, mockOutput.outputs)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, fmt.Sprintf("found %d problem(s) in %s", len(tt.expected), tmpFile))
+			}
+		})
+	}
+}
+
+func TestCLI_ParseArgs_DeterministicFlag(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "--deterministic", "test.bas"})
+	require.NoError(t, err)
+	assert.True(t, config.Deterministic)
+	assert.Equal(t, "test.bas", config.InputFile)
+
+	_, err = cli.ParseArgs([]string{"program", "--deterministic"})
+	assert.Error(t, err, "deterministic mode without a file should be rejected")
+}
+
+func TestCLI_FileExecution_Deterministic(t *testing.T) {
+	program := `10 PRINT RND
+20 PRINT RND
+30 PRINT RND`
+
+	tmpFile := createTempFile(t, program)
+	defer removeTempFile(t, tmpFile)
+
+	run := func() []string {
+		mockOutput := &MockOutputWriter{}
+		fileExecutor := NewFileExecutorWithConfig(&MockInputReader{}, mockOutput, ExecutorConfig{Deterministic: true})
+		require.NoError(t, fileExecutor.ExecuteFile(tmpFile, false))
+		return mockOutput.outputs
+	}
+
+	first := run()
+	second := run()
+
+	require.Len(t, first, 3)
+	assert.Equal(t, first, second, "deterministic runs should produce the same RND sequence")
+	assert.NotEqual(t, first[0], first[1], "RND should still advance within a run")
+}
+
+// The sample games write RND(1), as classic BASIC programs do
+func TestCLI_FileExecution_GuessNumberDrawsANumber(t *testing.T) {
+	// Press enter, miss all seven guesses, then decline another game
+	inputs := []string{"", "-1", "-1", "-1", "-1", "-1", "-1", "-1", "N"}
+	mockOutput := &MockOutputWriter{}
+	fileExecutor := NewFileExecutorWithConfig(&MockInputReader{inputs: inputs}, mockOutput, ExecutorConfig{Deterministic: true})
+
+	require.NoError(t, fileExecutor.ExecuteFile("../../testdata/guess_number.bas", false))
+
+	output := strings.Join(mockOutput.outputs, "")
+	assert.Contains(t, output, "I HAVE CHOSEN A NUMBER")
+	assert.Regexp(t, `THE NUMBER WAS ?\d+`, output)
+}
+
+func TestCLI_InteractiveMode_Calc(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   []string
+		expected string
+	}{
+		{
+			name:     "arithmetic expression",
+			inputs:   []string{"CALC 2+3*4", "EXIT"},
+			expected: " 14",
+		},
+		{
+			name:     "variable set by the last RUN",
+			inputs:   []string{"10 A = 5", "RUN", "calc A * 2", "EXIT"},
+			expected: " 10",
+		},
+		{
+			name:     "syntax error is reported",
+			inputs:   []string{"CALC 2 +", "EXIT"},
+			expected: "Error: syntax error",
+		},
+		{
+			name:     "missing expression is reported",
+			inputs:   []string{"CALC", "EXIT"},
+			expected: "Error: CALC requires an expression",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInput := &MockInputReader{inputs: tt.inputs}
+			mockOutput := &MockOutputWriter{}
+
+			interactive := NewInteractiveMode(mockInput, mockOutput)
+			err := interactive.Run()
+			assert.NoError(t, err)
+
+			// The CALC output is the last line before the final prompt and goodbye message
+			outputs := mockOutput.outputs
+			require.GreaterOrEqual(t, len(outputs), 3)
+			assert.True(t, strings.HasPrefix(outputs[len(outputs)-3], tt.expected),
+				"expected '%s' in %v", tt.expected, outputs)
+		})
+	}
+}
+
+func TestCLI_ParseArgs_TranscriptFlag(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "--transcript", "run.txt", "test.bas"})
+	require.NoError(t, err)
+	assert.Equal(t, "run.txt", config.TranscriptFile)
+	assert.Equal(t, "test.bas", config.InputFile)
+
+	_, err = cli.ParseArgs([]string{"program", "--transcript"})
+	assert.Error(t, err, "transcript flag should require a file name")
+}
+
+func TestCLI_FileExecution_Transcript(t *testing.T) {
+	program := `10 INPUT "Name"; N$
+20 PRINT "Hello", N$
+30 INPUT A
+40 PRINT A * 2`
+
+	tmpFile := createTempFile(t, program)
+	defer removeTempFile(t, tmpFile)
+
+	transcriptFile := filepath.Join(t.TempDir(), "transcript.txt")
+	file, err := os.Create(transcriptFile)
+	require.NoError(t, err)
+
+	transcript := NewTranscript(file)
+	mockOutput := &MockOutputWriter{}
+	input := NewRecordingInputReader(&MockInputReader{inputs: []string{"Ada", "21"}}, transcript)
+	output := NewRecordingOutputWriter(mockOutput, transcript)
+
+	err = NewFileExecutor(input, output).ExecuteFile(tmpFile, false)
+	require.NoError(t, err)
+	require.NoError(t, transcript.Err())
+	require.NoError(t, file.Close())
+
+	content, err := os.ReadFile(transcriptFile)
+	require.NoError(t, err)
+
+	expected := "Name\n" +
+		"> Ada\n" +
+		"Hello         Ada\n" +
+		"? \n" +
+		"> 21\n" +
+		" 42\n"
+	assert.Equal(t, expected, string(content), "transcript should interleave prompts, inputs and outputs")
+	assert.Equal(t, []string{"Name", "Hello         Ada", "? ", " 42"}, mockOutput.outputs,
+		"recording should not change what the program writes")
+}
+
+func TestCLI_InteractiveMode_Show(t *testing.T) {
+	mockInput := &MockInputReader{inputs: []string{"SHOW 2+3*4", "show 2^3^2", "SHOW 2 +", "EXIT"}}
+	mockOutput := &MockOutputWriter{}
+
+	err := NewInteractiveMode(mockInput, mockOutput).Run()
+	assert.NoError(t, err)
+
+	assert.Contains(t, mockOutput.outputs, "(2 + (3 * 4))")
+	assert.Contains(t, mockOutput.outputs, "(2 ^ (3 ^ 2))")
+
+	errorFound := false
+	for _, output := range mockOutput.outputs {
+		if strings.HasPrefix(output, "Error: syntax error") {
+			errorFound = true
+		}
+	}
+	assert.True(t, errorFound, "syntax error should be reported in %v", mockOutput.outputs)
+}
+
+func TestCLI_FileExecution_Include(t *testing.T) {
+	writeFile := func(t *testing.T, dir, name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("library lines are inlined", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "lib.bas", `900 PRINT "From library"
+910 END`)
+		writeFile(t, dir, "util.bas", `' $INCLUDE: 'lib.bas'
+800 PRINT "From util"`)
+		main := writeFile(t, dir, "main.bas", `10 PRINT "Main"
+20 GOTO 800
+$INCLUDE "util.bas"`)
+
+		mockOutput := &MockOutputWriter{}
+		err := NewFileExecutor(&MockInputReader{}, mockOutput).ExecuteFile(main, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Main", "From util", "From library"}, mockOutput.outputs)
+	})
+
+	t.Run("directive inside REM", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "lib.bas", `900 PRINT "From library"`)
+		main := writeFile(t, dir, "main.bas", `10 REM $INCLUDE "lib.bas"`)
+
+		mockOutput := &MockOutputWriter{}
+		err := NewFileExecutor(&MockInputReader{}, mockOutput).ExecuteFile(main, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"From library"}, mockOutput.outputs)
+	})
+
+	t.Run("cyclic include is an error", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "a.bas", `10 PRINT "A"
+$INCLUDE "b.bas"`)
+		writeFile(t, dir, "b.bas", `20 PRINT "B"
+$INCLUDE "a.bas"`)
+
+		err := NewFileExecutor(&MockInputReader{}, &MockOutputWriter{}).ExecuteFile(filepath.Join(dir, "a.bas"), false)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "include cycle: a.bas -> b.bas -> a.bas")
+	})
+
+	t.Run("missing include file is an error", func(t *testing.T) {
+		dir := t.TempDir()
+		main := writeFile(t, dir, "main.bas", `$INCLUDE "missing.bas"`)
+
+		err := NewFileExecutor(&MockInputReader{}, &MockOutputWriter{}).ExecuteFile(main, false)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot include missing.bas")
+	})
+}
+
+// mockCommandRunner returns canned output for SHELL commands
+type mockCommandRunner struct {
+	commands []string
+	output   string
+}
+
+func (m *mockCommandRunner) RunCommand(command string) (string, error) {
+	m.commands = append(m.commands, command)
+	return m.output, nil
+}
+
+func TestCLI_ParseArgs_AllowShellFlag(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "--allow-shell", "test.bas"})
+	require.NoError(t, err)
+	assert.True(t, config.AllowShell)
+
+	config, err = cli.ParseArgs([]string{"program", "test.bas"})
+	require.NoError(t, err)
+	assert.False(t, config.AllowShell, "SHELL is off by default")
+}
+
+func TestCLI_FileExecution_Shell(t *testing.T) {
+	program := `10 SHELL "date", D$
+20 PRINT "Today is", D$`
+
+	tmpFile := createTempFile(t, program)
+	defer removeTempFile(t, tmpFile)
+
+	t.Run("runs through the configured runner", func(t *testing.T) {
+		runner := &mockCommandRunner{output: "Monday\n"}
+		mockOutput := &MockOutputWriter{}
+		err := NewFileExecutorWithConfig(&MockInputReader{}, mockOutput, ExecutorConfig{CommandRunner: runner}).ExecuteFile(tmpFile, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"date"}, runner.commands)
+		assert.Equal(t, []string{"Today is      Monday"}, mockOutput.outputs)
+	})
+
+	t.Run("is disabled by default", func(t *testing.T) {
+		err := NewFileExecutor(&MockInputReader{}, &MockOutputWriter{}).ExecuteFile(tmpFile, false)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "feature disabled: SHELL")
+	})
+}
+
+func TestCLI_InteractiveMode_Subs(t *testing.T) {
+	mockInput := &MockInputReader{inputs: []string{
+		"10 GOSUB 500",
+		"20 GOSUB 600",
+		"30 IF A = 0 THEN GOSUB 500",
+		"40 GOSUB 700",
+		"50 END",
+		"500 REM -- PrintReport",
+		"510 RETURN",
+		"590 ' SaveTotals",
+		"600 RETURN",
+		"700 RETURN",
+		"SUBS",
+		"EXIT",
+	}}
+	mockOutput := &MockOutputWriter{}
+
+	err := NewInteractiveMode(mockInput, mockOutput).Run()
+	assert.NoError(t, err)
+
+	subs := indexOf(mockOutput.outputs, "500 PrintReport (called from 10, 30)")
+	require.GreaterOrEqual(t, subs, 0, "SUBS output: %v", mockOutput.outputs)
+	assert.Equal(t, []string{
+		"500 PrintReport (called from 10, 30)",
+		"600 SaveTotals (called from 20)",
+		"700 (called from 40)",
+	}, mockOutput.outputs[subs:subs+3], "a REM on the line before the subroutine names it too")
+}
+
+func TestCLI_InteractiveMode_Loops(t *testing.T) {
+	mockInput := &MockInputReader{inputs: []string{
+		"10 FOR I = 1 TO 3",
+		"20 FOR J = 1 TO 2",
+		"30 PRINT I * J",
+		"40 NEXT J",
+		"50 NEXT I",
+		"LOOPS",
+		"EXIT",
+	}}
+	mockOutput := &MockOutputWriter{}
+
+	err := NewInteractiveMode(mockInput, mockOutput).Run()
+	assert.NoError(t, err)
+
+	assert.Contains(t, mockOutput.outputs, "FOR 10 -> NEXT 50")
+	assert.Contains(t, mockOutput.outputs, "FOR 20 -> NEXT 40")
+}
+
+func TestCLI_FileExecution_CleanNumbers(t *testing.T) {
+	tmpFile := createTempFile(t, `10 PRINT 5`)
+	defer removeTempFile(t, tmpFile)
+
+	config, err := NewCLI().ParseArgs([]string{"program", "--clean-numbers", tmpFile})
+	require.NoError(t, err)
+	assert.True(t, config.CleanNumbers)
+
+	authentic := &MockOutputWriter{}
+	require.NoError(t, NewFileExecutor(&MockInputReader{}, authentic).ExecuteFile(tmpFile, false))
+	assert.Equal(t, []string{" 5"}, authentic.outputs)
+
+	clean := &MockOutputWriter{}
+	require.NoError(t, NewFileExecutorWithConfig(&MockInputReader{}, clean, ExecutorConfig{CleanNumbers: true}).ExecuteFile(tmpFile, false))
+	assert.Equal(t, []string{"5"}, clean.outputs)
+}
+
+func TestCLI_InteractiveMode_RunRange(t *testing.T) {
+	program := []string{
+		`10 PRINT "ten"`,
+		`30 PRINT "thirty"`,
+		`60 PRINT "sixty"`,
+		`70 PRINT "seventy"`,
+	}
+
+	run := func(commands ...string) []string {
+		mockInput := &MockInputReader{inputs: append(append([]string{}, program...), append(commands, "EXIT")...)}
+		mockOutput := &MockOutputWriter{}
+		require.NoError(t, NewInteractiveMode(mockInput, mockOutput).Run())
+		return mockOutput.outputs
+	}
+
+	outputs := run("RUN 30-60")
+	assert.Contains(t, outputs, "thirty")
+	assert.Contains(t, outputs, "sixty")
+	assert.NotContains(t, outputs, "ten")
+	assert.NotContains(t, outputs, "seventy")
+
+	outputs = run("RUN 60")
+	assert.Contains(t, outputs, "seventy")
+	assert.NotContains(t, outputs, "thirty")
+
+	outputs = run("RUN 60-30")
+	assert.Contains(t, outputs, "Error: invalid line range: 60-30")
+}
+
+func TestCLI_InteractiveMode_ListRange(t *testing.T) {
+	program := []string{
+		`30 PRINT "thirty"`,
+		`10 PRINT "ten"`,
+		`60 PRINT "sixty"`,
+		`70 PRINT "seventy"`,
+	}
+
+	list := func(command string) []string {
+		mockInput := &MockInputReader{inputs: append(append([]string{}, program...), command, "EXIT")}
+		mockOutput := &MockOutputWriter{}
+		require.NoError(t, NewInteractiveMode(mockInput, mockOutput).Run())
+		return mockOutput.outputs
+	}
+	listed := func(outputs []string) []string {
+		var lines []string
+		for _, line := range outputs {
+			if strings.Contains(line, "PRINT") {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	all := []string{`10 PRINT "ten"`, `30 PRINT "thirty"`, `60 PRINT "sixty"`, `70 PRINT "seventy"`}
+	assert.Equal(t, all, listed(list("LIST")))
+	assert.Equal(t, []string{`30 PRINT "thirty"`}, listed(list("LIST 30")))
+	assert.Equal(t, []string{`30 PRINT "thirty"`, `60 PRINT "sixty"`}, listed(list("LIST 20-60")))
+	assert.Empty(t, listed(list("LIST 40")), "a line that does not exist lists nothing")
+
+	assert.Contains(t, list("LIST 60-30"), "Error: invalid line range: 60-30")
+	outputs := list("LIST ten")
+	assert.Empty(t, listed(outputs))
+	assert.Contains(t, strings.Join(outputs, "\n"), "Error: ")
+}
+
+func TestCLI_InteractiveMode_ListCanonical(t *testing.T) {
+	inputs := []string{
+		`10 print   "hi";a`,
+		`20 let x=1+2*3`,
+		`30 if a>1 then print "big" : rem done`,
+		`40 x=1 ' keep this note`,
+		`50 PRINT (`,
+		`60 goto   10`,
+		`70 if x then gosub 10`,
+		"LIST",
+		"EXIT",
+	}
+	output := &MockOutputWriter{}
+	require.NoError(t, NewInteractiveMode(&MockInputReader{inputs: inputs}, output).Run())
+
+	listed := indexOf(output.outputs, `10 PRINT "hi"; A`)
+	require.GreaterOrEqual(t, listed, 0, "lines are listed in canonical form")
+	assert.Equal(t, []string{
+		`10 PRINT "hi"; A`,
+		`20 X = 1 + 2 * 3`,
+		`30 IF A > 1 THEN PRINT "big": REM done`,
+		`40 x=1 ' keep this note`,
+		`50 PRINT (`,
+		`60 GOTO 10`,
+		`70 IF X THEN GOSUB 10`,
+	}, output.outputs[listed:listed+7], "lines with a trailing comment or a syntax error are kept as typed")
+}
+
+func TestCLI_ParseArgs_DefineFlag(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "-D", "N=10", "-DNAME$=Ada Lovelace", "test.bas"})
+	require.NoError(t, err)
+	assert.Equal(t, "test.bas", config.InputFile)
+	assert.Equal(t, runtime.NewNumericValue(10), config.Variables["N"])
+	assert.Equal(t, runtime.NewStringValue("Ada Lovelace"), config.Variables["NAME$"])
+
+	config, err = cli.ParseArgs([]string{"program", "test.bas"})
+	require.NoError(t, err)
+	assert.Empty(t, config.Variables)
+
+	errorCases := []struct {
+		name string
+		args []string
+	}{
+		{"missing definition", []string{"program", "test.bas", "-D"}},
+		{"missing equals sign", []string{"program", "-D", "N", "test.bas"}},
+		{"invalid name", []string{"program", "-D", "1N=5", "test.bas"}},
+		{"non-numeric value", []string{"program", "-D", "N=ten", "test.bas"}},
+		{"no file", []string{"program", "-D", "N=10"}},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := cli.ParseArgs(tc.args)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCLI_FileExecution_DefinedVariables(t *testing.T) {
+	tmpFile := createTempFile(t, "10 PRINT \"Hello,\", NAME$\n20 PRINT N * 2")
+	defer removeTempFile(t, tmpFile)
+
+	config, err := NewCLI().ParseArgs([]string{"program", "-D", "NAME$=Ada", "-D", "N=21", tmpFile})
+	require.NoError(t, err)
+
+	mockOutput := &MockOutputWriter{}
+	executor := NewFileExecutorWithConfig(&MockInputReader{}, mockOutput, ExecutorConfig{Variables: config.Variables})
+	require.NoError(t, executor.ExecuteFile(tmpFile, false))
+	assert.Equal(t, []string{"Hello,        Ada", " 42"}, mockOutput.outputs)
+}
+
+func TestCLI_FileExecution_ApostropheComments(t *testing.T) {
+	tmpFile := createTempFile(t, "10 ' greet the user, \"politely\n20 X = 5 ' five\n30 PRINT \"it's\", X ' show value")
+	defer removeTempFile(t, tmpFile)
+
+	mockOutput := &MockOutputWriter{}
+	require.NoError(t, NewFileExecutor(&MockInputReader{}, mockOutput).ExecuteFile(tmpFile, false))
+	assert.Equal(t, []string{"it's           5"}, mockOutput.outputs)
+}
+
+func TestCLI_FileExecution_PrintSemicolons(t *testing.T) {
+	tmpFile := createTempFile(t, "10 FOR I = 1 TO 3\n20 PRINT I;\n30 NEXT I\n40 PRINT\n50 PRINT \"A\"; \"B\", \"C\"")
+	defer removeTempFile(t, tmpFile)
+
+	output := NewMemoryOutputWriter()
+	require.NoError(t, NewFileExecutor(&MockInputReader{}, output).ExecuteFile(tmpFile, false))
+	// Items printed with a trailing semicolon are written without a line break
+	assert.Equal(t, []string{" 1", " 2", " 3", "", "AB            C"}, output.Lines())
+}
+
+func TestCLI_FileExecution_StrictNext(t *testing.T) {
+	tmpFile := createTempFile(t, "10 FOR I = 1 TO 2\n20 FOR J = 1 TO 2\n30 PRINT I, J\n40 NEXT\n50 NEXT")
+	defer removeTempFile(t, tmpFile)
+
+	config, err := NewCLI().ParseArgs([]string{"program", "--strict-next", tmpFile})
+	require.NoError(t, err)
+	assert.True(t, config.StrictNext)
+
+	_, err = NewCLI().ParseArgs([]string{"program", "--strict-next"})
+	assert.Error(t, err, "--strict-next requires a file")
+
+	lenient := &MockOutputWriter{}
+	require.NoError(t, NewFileExecutor(&MockInputReader{}, lenient).ExecuteFile(tmpFile, false))
+	assert.Equal(t, []string{" 1             1", " 1             2", " 2             1", " 2             2"}, lenient.outputs)
+
+	strict := &MockOutputWriter{}
+	err = NewFileExecutorWithConfig(&MockInputReader{}, strict, ExecutorConfig{StrictNext: true}).ExecuteFile(tmpFile, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NEXT without a variable at line 40 closes FOR J at line 20")
+	assert.Empty(t, strict.outputs, "the program does not start")
+}
+
+func TestCLI_ParseArgs_RecordReplayFlags(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "--record", "out.txt", "--replay", "in.txt"})
+	require.NoError(t, err)
+	assert.True(t, config.Interactive)
+	assert.Equal(t, "out.txt", config.RecordFile)
+	assert.Equal(t, "in.txt", config.ReplayFile)
+
+	_, err = cli.ParseArgs([]string{"program", "--record"})
+	assert.Error(t, err, "record flag should require a file name")
+
+	_, err = cli.ParseArgs([]string{"program", "--replay", "in.txt", "test.bas"})
+	assert.Error(t, err, "replay is only for interactive mode")
+}
+
+func TestCLI_InteractiveMode_RecordAndReplay(t *testing.T) {
+	session := []string{`10 INPUT "Name"; N$`, `20 PRINT "Hello", N$`, "RUN", "Ada", "LIST", "EXIT"}
+
+	var recording bytes.Buffer
+	recorder := NewSessionRecorder(&MockInputReader{inputs: session}, &recording)
+	recorded := &MockOutputWriter{}
+	require.NoError(t, NewInteractiveMode(recorder, recorded).Run())
+	require.NoError(t, recorder.Err())
+
+	assert.Equal(t, strings.Join(session, "\n")+"\n", recording.String(),
+		"commands and program input are recorded one per line")
+
+	replay, err := LoadSession(&recording)
+	require.NoError(t, err)
+	replayed := &MockOutputWriter{}
+	require.NoError(t, NewInteractiveMode(replay, replayed).Run())
+
+	assert.Contains(t, recorded.outputs, "Hello         Ada")
+	assert.Equal(t, recorded.outputs, replayed.outputs)
+}
+
+func TestCLI_SaveAndLoadVariables(t *testing.T) {
+	env := runtime.NewEnvironment()
+	env.SetVariable("A", runtime.NewNumericValue(0.1))
+	env.SetVariable("N$", runtime.NewStringValue(`say "hi"`))
+	env.SetVariable("I%", runtime.NewNumericValue(3))
+	env.SetVariable("D$", runtime.NewStringValue("42"))
+
+	var dump bytes.Buffer
+	require.NoError(t, SaveVariables(env, &dump))
+	assert.Equal(t, "A=0.1\nD$=\"42\"\nI%=3\nN$=\"say \\\"hi\\\"\"\n", dump.String())
+
+	restored := runtime.NewEnvironment()
+	require.NoError(t, LoadVariables(restored, &dump))
+	assert.Equal(t, env.Variables, restored.Variables, "names, types and values are restored")
+
+	t.Run("invalid dump sets nothing", func(t *testing.T) {
+		target := runtime.NewEnvironment()
+		err := LoadVariables(target, strings.NewReader("A=1\nB=oops\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+		assert.Empty(t, target.Variables)
+	})
+}
+
+func TestCLI_InteractiveMode_SaveAndLoadVariables(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.txt")
+	inputs := []string{
+		`10 A = 2.5`,
+		`20 N$ = "Ada"`,
+		"RUN",
+		fmt.Sprintf(`SAVEVARS "%s"`, stateFile),
+		"CLEAR",
+		"CALC A",
+		fmt.Sprintf(`LOADVARS "%s"`, stateFile),
+		"CALC A * 2",
+		`CALC N$ + "!"`,
+		"EXIT",
+	}
+	output := &MockOutputWriter{}
+	require.NoError(t, NewInteractiveMode(&MockInputReader{inputs: inputs}, output).Run())
+
+	assert.Contains(t, output.outputs, "2 variables saved")
+	assert.Contains(t, output.outputs, "Variables loaded")
+	cleared := indexOf(output.outputs, ProgramClearedMessage)
+	require.GreaterOrEqual(t, cleared, 0)
+	after := output.outputs[cleared:]
+	assert.Equal(t, " 0", after[2], "CLEAR forgets the variables")
+	assert.Contains(t, after, " 5", "numbers are restored as numbers")
+	assert.Contains(t, after, "Ada!", "strings are restored as strings")
+
+	t.Run("the next RUN starts with the loaded variables", func(t *testing.T) {
+		inputs := []string{
+			`10 A = 2 : B$ = "x" : C = 3`,
+			"RUN",
+			fmt.Sprintf(`SAVEVARS "%s"`, stateFile),
+			"CLEAR",
+			`10 PRINT A; B$; C`,
+			fmt.Sprintf(`LOADVARS "%s"`, stateFile),
+			"RUN",
+			"EXIT",
+		}
+		output := &MockOutputWriter{}
+		require.NoError(t, NewInteractiveMode(&MockInputReader{inputs: inputs}, output).Run())
+
+		loaded := indexOf(output.outputs, VariablesLoadedMessage)
+		require.GreaterOrEqual(t, loaded, 0)
+		assert.Contains(t, output.outputs[loaded:], " 2x 3")
+	})
+
+	t.Run("missing file name", func(t *testing.T) {
+		output := &MockOutputWriter{}
+		require.NoError(t, NewInteractiveMode(&MockInputReader{inputs: []string{"LOADVARS", "EXIT"}}, output).Run())
+		assert.Contains(t, output.outputs, "Error: LOADVARS requires a file name")
+	})
+}
+
+func TestCLI_InteractiveMode_SaveAndLoadProgram(t *testing.T) {
+	programFile := filepath.Join(t.TempDir(), "hello.bas")
+	inputs := []string{
+		`20 PRINT "World"`,
+		`10 PRINT "Hello"`,
+		fmt.Sprintf(`SAVE "%s"`, programFile),
+		"CLEAR",
+		`30 PRINT "Replaced"`,
+		fmt.Sprintf(`LOAD "%s"`, programFile),
+		"LIST",
+		"RUN",
+		"EXIT",
+	}
+	output := &MockOutputWriter{}
+	require.NoError(t, NewInteractiveMode(&MockInputReader{inputs: inputs}, output).Run())
+
+	saved, err := os.ReadFile(programFile)
+	require.NoError(t, err)
+	assert.Equal(t, "10 PRINT \"Hello\"\n20 PRINT \"World\"\n", string(saved), "lines are saved in order")
+
+	assert.Contains(t, output.outputs, "2 lines saved")
+	loaded := indexOf(output.outputs, "2 lines loaded")
+	require.GreaterOrEqual(t, loaded, 0)
+	after := output.outputs[loaded:]
+	assert.Equal(t, []string{`10 PRINT "Hello"`, `20 PRINT "World"`}, after[2:4], "LOAD replaces the program")
+	assert.Contains(t, after, "Hello")
+	assert.NotContains(t, after, "Replaced")
+
+	t.Run("file errors are reported and keep the program", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "missing.bas")
+		inputs := []string{
+			`10 PRINT "Kept"`,
+			fmt.Sprintf(`LOAD "%s"`, missing),
+			fmt.Sprintf(`SAVE "%s"`, filepath.Join(missing, "nested.bas")),
+			"LIST",
+			"EXIT",
+		}
+		output := &MockOutputWriter{}
+		require.NoError(t, NewInteractiveMode(&MockInputReader{inputs: inputs}, output).Run())
+
+		errorCount := 0
+		for _, line := range output.outputs {
+			if strings.HasPrefix(line, "Error: cannot load program:") || strings.HasPrefix(line, "Error: cannot save program:") {
+				errorCount++
+			}
+		}
+		assert.Equal(t, 2, errorCount)
+		assert.Contains(t, output.outputs, `10 PRINT "Kept"`)
+	})
+
+	t.Run("missing file name", func(t *testing.T) {
+		output := &MockOutputWriter{}
+		require.NoError(t, NewInteractiveMode(&MockInputReader{inputs: []string{"SAVE", "EXIT"}}, output).Run())
+		assert.Contains(t, output.outputs, "Error: SAVE requires a file name")
+	})
+}
+
+// indexOf returns the position of the first line equal to target, or -1
+func indexOf(lines []string, target string) int {
+	for i, line := range lines {
+		if line == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// interruptingOutputWriter cancels the run after the given number of lines, like Ctrl-C mid-run
+type interruptingOutputWriter struct {
+	MockOutputWriter
+	cancel context.CancelFunc
+	after  int
+}
+
+func (w *interruptingOutputWriter) WriteLine(line string) error {
+	if len(w.outputs)+1 >= w.after {
+		w.cancel()
+	}
+	return w.MockOutputWriter.WriteLine(line)
+}
+
+func TestCLI_FileExecution_Interrupted(t *testing.T) {
+	program := `10 PRINT "tick"
+20 GOTO 10`
+	tmpFile := createTempFile(t, program)
+	defer removeTempFile(t, tmpFile)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	output := &interruptingOutputWriter{cancel: cancel, after: 3}
+
+	err := NewFileExecutorWithConfig(&MockInputReader{}, output, ExecutorConfig{Context: ctx}).ExecuteFile(tmpFile, false)
+
+	var interrupted *interpreter.InterruptedError
+	require.ErrorAs(t, err, &interrupted, "the interruption is returned unwrapped so the caller can exit cleanly")
+	assert.Equal(t, 20, interrupted.Line)
+	assert.Equal(t, []string{"tick", "tick", "tick", "Interrupted at line 20"}, output.outputs)
+}
+
+func TestCLI_FileExecution_CoerceCompare(t *testing.T) {
+	tmpFile := createTempFile(t, `10 IF 5 = "5" THEN PRINT "equal"`)
+	defer removeTempFile(t, tmpFile)
+
+	config, err := NewCLI().ParseArgs([]string{"program", "--coerce-compare", tmpFile})
+	require.NoError(t, err)
+	assert.True(t, config.CoerceCompare)
+
+	strict := &MockOutputWriter{}
+	err = NewFileExecutor(&MockInputReader{}, strict).ExecuteFile(tmpFile, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "type mismatch in comparison")
+
+	coercing := &MockOutputWriter{}
+	require.NoError(t, NewFileExecutorWithConfig(&MockInputReader{}, coercing, ExecutorConfig{CoerceCompare: true}).ExecuteFile(tmpFile, false))
+	assert.Equal(t, []string{"equal"}, coercing.outputs)
+
+	_, err = NewCLI().ParseArgs([]string{"program", "--coerce-compare"})
+	assert.EqualError(t, err, "--coerce-compare requires a file")
+}
+
+func TestCLI_FileExecution_DecimalComma(t *testing.T) {
+	tmpFile := createTempFile(t, "10 INPUT X\n20 PRINT X * 2\n30 PRINT VAL(\"1.000,5\")")
+	defer removeTempFile(t, tmpFile)
+
+	config, err := NewCLI().ParseArgs([]string{"program", "--decimal-comma", tmpFile})
+	require.NoError(t, err)
+	assert.True(t, config.DecimalComma)
+
+	output := &MockOutputWriter{}
+	input := &MockInputReader{inputs: []string{"1,57"}}
+	require.NoError(t, NewFileExecutorWithConfig(input, output, ExecutorConfig{DecimalComma: true}).ExecuteFile(tmpFile, false))
+	assert.Equal(t, []string{"? ", " 3,14", " 1000,5"}, output.outputs)
+
+	_, err = NewCLI().ParseArgs([]string{"program", "--decimal-comma"})
+	assert.EqualError(t, err, "--decimal-comma requires a file")
+}
+
+func TestCLI_ParseArgs_MaxStepsFlag(t *testing.T) {
+	cli := NewCLI()
+
+	config, err := cli.ParseArgs([]string{"program", "--max-steps", "500", "test.bas"})
+	require.NoError(t, err)
+	assert.Equal(t, 500, config.MaxSteps)
+
+	config, err = cli.ParseArgs([]string{"program", "--max-steps", "0", "test.bas"})
+	require.NoError(t, err)
+	assert.Equal(t, -1, config.MaxSteps, "0 lifts the limit")
+
+	config, err = cli.ParseArgs([]string{"program", "test.bas"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, config.MaxSteps, "the interpreter's default applies")
+
+	errorCases := []struct {
+		name string
+		args []string
+	}{
+		{"missing count", []string{"program", "test.bas", "--max-steps"}},
+		{"not a number", []string{"program", "--max-steps", "many", "test.bas"}},
+		{"negative", []string{"program", "--max-steps", "-5", "test.bas"}},
+		{"no file", []string{"program", "--max-steps", "500"}},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := cli.ParseArgs(tc.args)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCLI_FileExecution_MaxSteps(t *testing.T) {
+	tmpFile := createTempFile(t, "10 PRINT \"start\"\n20 GOTO 20")
+	defer removeTempFile(t, tmpFile)
+
+	config, err := NewCLI().ParseArgs([]string{"program", "--max-steps", "100", tmpFile})
+	require.NoError(t, err)
+
+	mockOutput := &MockOutputWriter{}
+	err = NewFileExecutorWithConfig(&MockInputReader{}, mockOutput, ExecutorConfig{MaxSteps: config.MaxSteps}).ExecuteFile(tmpFile, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "execution exceeded 100 steps (possible infinite loop)")
+	assert.Equal(t, []string{"start"}, mockOutput.outputs)
+}
+
+func TestCLI_FileExecution_Stdin(t *testing.T) {
+	config, err := NewCLI().ParseArgs([]string{"program", "-"})
+	require.NoError(t, err)
+	assert.Equal(t, "-", config.InputFile)
+	assert.False(t, config.Interactive, "- names a file to execute")
+
+	stdin := strings.NewReader("20 PRINT \"from stdin\"\n10 PRINT \"first\"\n")
+	mockOutput := &MockOutputWriter{}
+	err = NewFileExecutorWithConfig(&MockInputReader{}, mockOutput, ExecutorConfig{Stdin: stdin}).ExecuteFile(config.InputFile, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "from stdin"}, mockOutput.outputs)
+
+	stdin = strings.NewReader("10 GOTO 99\n")
+	mockOutput = &MockOutputWriter{}
+	err = NewFileExecutorWithConfig(&MockInputReader{}, mockOutput, ExecutorConfig{Stdin: stdin}).CheckFile("-")
+	assert.EqualError(t, err, "found 1 problem(s) in -")
+	assert.Equal(t, []string{"Error: line 10 jumps to line 99, which does not exist"}, mockOutput.outputs)
+}
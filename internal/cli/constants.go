@@ -4,13 +4,16 @@ const (
 	// Version information
 	InterpreterVersion = "1.0.0"
 	
+	// File name that stands for standard input, as in cat program.bas | basic-interpreter -
+	StdinFileName = "-"
+	
 	// Line number constraints
 	MinLineNumber = 1
 	MaxLineNumber = 99999
 	
 	// Interactive mode messages
 	InteractiveModeHeader = "BASIC Interpreter - Interactive Mode"
-	InteractiveModeInstructions = "Type EXIT to quit, LIST to show program, RUN to execute, CLEAR to clear program"
+	InteractiveModeInstructions = "Type EXIT to quit, LIST [start-end] to show program, RUN [start-end] to execute, CLEAR to clear program, CALC expr to evaluate, SHOW expr to see how it parses, LOOPS to pair FOR with NEXT, SUBS to list subroutines, SAVE/LOAD file to keep the program, SAVEVARS/LOADVARS file to keep variables between sessions"
 	ReadyPrompt = "READY"
 	GoodbyeMessage = "Goodbye!"
 	
@@ -20,4 +23,9 @@ const (
 	NoProgramMessage = "No program to run"
 	NoProgramLoadedMessage = "No program loaded"
 	ProgramClearedMessage = "Program cleared"
+	NoSubroutinesMessage = "No subroutines"
+	ProgramSavedMessage = "%d lines saved"
+	ProgramLoadedMessage = "%d lines loaded"
+	VariablesSavedMessage = "%d variables saved"
+	VariablesLoadedMessage = "Variables loaded"
 )
\ No newline at end of file
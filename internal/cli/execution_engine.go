@@ -7,6 +7,7 @@ import (
 	"basic-interpreter/internal/parser"
 	"basic-interpreter/internal/runtime"
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -21,6 +22,17 @@ type ForLoopInfo struct {
 
 // ExecuteProgram executes a parsed BASIC program using the real interpreter
 func (fe *FileExecutor) ExecuteProgram(program map[int]string, debugMode bool) error {
+	return fe.executeProgramInEnvironment(program, fe.newEnvironment(), debugMode)
+}
+
+// executeProgramInEnvironment executes a program using the given runtime environment
+// Callers that need the variables after the run (such as interactive mode) keep the environment
+func (fe *FileExecutor) executeProgramInEnvironment(program map[int]string, env *runtime.Environment, debugMode bool) error {
+	return fe.executeProgramRangeInEnvironment(program, env, debugMode, math.MinInt, math.MaxInt)
+}
+
+// executeProgramRangeInEnvironment executes only the program lines from start to end, inclusive
+func (fe *FileExecutor) executeProgramRangeInEnvironment(program map[int]string, env *runtime.Environment, debugMode bool, start, end int) error {
 	if len(program) == 0 {
 		return nil // Empty program is valid
 	}
@@ -37,15 +49,56 @@ func (fe *FileExecutor) ExecuteProgram(program map[int]string, debugMode bool) e
 		fe.output.WriteLine("")
 	}
 	
-	// Create lexer and parser
-	lex := lexer.NewLexer(sourceCode)
-	p := parser.NewParser(lex)
+	astProgram, err := fe.buildProgram(sourceCode)
+	if err != nil {
+		return err
+	}
 	
-	// Parse the program
-	astProgram, err := p.ParseProgram()
+	// Create interpreter with debug output if needed, stopping when the run is cancelled
+	config := interpreter.InterpreterConfig{
+		MaxSteps: fe.config.MaxSteps,
+		Context:  fe.config.Context,
+	}
+	if debugMode {
+		config.DebugMode = true
+		config.DebugOutput = fe.output
+	}
+	if fe.config.TraceMode {
+		config.TraceMode = true
+		config.TraceOutput = fe.output
+	}
+	interpreterInstance := interpreter.NewInterpreter(config)
+	
+	// Execute the program
+	return interpreterInstance.ExecuteRange(astProgram, env, start, end)
+}
+
+// newEnvironment creates the runtime environment for a run according to the executor config
+func (fe *FileExecutor) newEnvironment() *runtime.Environment {
+	env := runtime.NewEnvironment()
+	if fe.config.Deterministic {
+		env.SetRandomSeed(runtime.DeterministicSeed)
+	}
+	env.CleanNumbers = fe.config.CleanNumbers
+	env.CoerceComparisons = fe.config.CoerceCompare
+	env.NumberFormat.DecimalComma = fe.config.DecimalComma
+	for name, value := range fe.config.Variables {
+		env.SetVariable(name, value)
+	}
+	return env
+}
+
+// buildProgram parses source code into an AST program wired to the executor's I/O
+func (fe *FileExecutor) buildProgram(sourceCode string) (*ast.Program, error) {
+	astProgram, err := fe.parseSource(sourceCode, true)
 	if err != nil {
-		return fmt.Errorf("parse error in generated source code:\n%s\nError: %w", 
-			fe.addLineNumbers(sourceCode), err)
+		return nil, err
+	}
+	
+	if fe.config.StrictNext {
+		if err := ast.CheckExplicitNext(astProgram); err != nil {
+			return nil, err
+		}
 	}
 	
 	// Set output writer for all PRINT statements
@@ -54,26 +107,27 @@ func (fe *FileExecutor) ExecuteProgram(program map[int]string, debugMode bool) e
 	// Set input/output writers for all INPUT statements
 	fe.setInputOutputWriters(astProgram)
 	
-	// Set program references for all GOTO statements
-	fe.setProgramReferences(astProgram)
-	
-	// Create runtime environment
-	env := runtime.NewEnvironment()
+	return astProgram, nil
+}
+
+// parseSource parses source code into an AST program, rejecting jumps to missing lines
+// only when checkJumps is set
+func (fe *FileExecutor) parseSource(sourceCode string, checkJumps bool) (*ast.Program, error) {
+	// Create lexer and parser
+	lex := lexer.NewLexer(sourceCode)
+	p := parser.NewParser(lex)
 	
-	// Create interpreter with debug output if needed
-	var interpreterInstance *interpreter.Interpreter
-	if debugMode {
-		interpreterInstance = interpreter.NewInterpreter(interpreter.InterpreterConfig{
-			DebugMode:   true,
-			DebugOutput: fe.output,
-			MaxSteps:    -1,
-		})
-	} else {
-		interpreterInstance = interpreter.NewBasicInterpreter(false)
+	// Parse the program
+	parse := p.ParseProgramLines
+	if checkJumps {
+		parse = p.ParseProgram
 	}
-	
-	// Execute the program
-	return interpreterInstance.Execute(astProgram, env)
+	astProgram, err := parse()
+	if err != nil {
+		return nil, fmt.Errorf("parse error in generated source code:\n%s\nError: %w", 
+			fe.addLineNumbers(sourceCode), err)
+	}
+	return astProgram, nil
 }
 
 // programToSourceCode converts a map[int]string program to source code
@@ -263,15 +317,22 @@ func (fe *FileExecutor) setPrintOutputWriterForStatement(statement ast.Statement
 	case *ast.PrintStatement:
 		stmt.Output = fe.output
 	case *ast.IfStatement:
-		// Handle PRINT statements in IF-THEN clauses
+		// Handle PRINT statements in IF-THEN-ELSE clauses
 		if stmt.ThenStatement != nil {
 			fe.setPrintOutputWriterForStatement(stmt.ThenStatement)
 		}
+		if stmt.ElseStatement != nil {
+			fe.setPrintOutputWriterForStatement(stmt.ElseStatement)
+		}
+	case *ast.CompoundStatement:
+		for _, inner := range stmt.Statements {
+			fe.setPrintOutputWriterForStatement(inner)
+		}
 	// Add other statement types that might contain PRINT statements as needed
 	}
 }
 
-// setInputOutputWriters sets the input/output writers for all INPUT statements in the program
+// setInputOutputWriters sets the input/output writers for all INPUT, PAUSE and SHELL statements in the program
 func (fe *FileExecutor) setInputOutputWriters(program *ast.Program) {
 	for _, statement := range program.Lines {
 		fe.setInputOutputWriterForStatement(statement)
@@ -284,11 +345,24 @@ func (fe *FileExecutor) setInputOutputWriterForStatement(statement ast.Statement
 	case *ast.InputStatement:
 		stmt.Input = fe.input
 		stmt.Output = fe.output
+	case *ast.PauseStatement:
+		stmt.Input = fe.input
+		stmt.Output = fe.output
+	case *ast.ShellStatement:
+		stmt.Runner = fe.config.CommandRunner
+		stmt.Output = fe.output
 	case *ast.IfStatement:
-		// Handle INPUT statements in IF-THEN clauses
+		// Handle INPUT statements in IF-THEN-ELSE clauses
 		if stmt.ThenStatement != nil {
 			fe.setInputOutputWriterForStatement(stmt.ThenStatement)
 		}
+		if stmt.ElseStatement != nil {
+			fe.setInputOutputWriterForStatement(stmt.ElseStatement)
+		}
+	case *ast.CompoundStatement:
+		for _, inner := range stmt.Statements {
+			fe.setInputOutputWriterForStatement(inner)
+		}
 	// Add other statement types that might contain INPUT statements as needed
 	}
 }
@@ -303,23 +377,3 @@ func (fe *FileExecutor) addLineNumbers(sourceCode string) string {
 	return strings.Join(numbered, "\n")
 }
 
-// setProgramReferences sets the program reference for all GOTO statements
-func (fe *FileExecutor) setProgramReferences(program *ast.Program) {
-	for _, statement := range program.Lines {
-		fe.setProgramReferenceForStatement(statement, program)
-	}
-}
-
-// setProgramReferenceForStatement recursively sets program references for GOTO statements
-func (fe *FileExecutor) setProgramReferenceForStatement(statement ast.Statement, program *ast.Program) {
-	switch stmt := statement.(type) {
-	case *ast.GotoStatement:
-		stmt.Program = program
-	case *ast.IfStatement:
-		// Handle GOTO statements in IF-THEN clauses
-		if stmt.ThenStatement != nil {
-			fe.setProgramReferenceForStatement(stmt.ThenStatement, program)
-		}
-	// Add other statement types that might contain GOTO statements as needed
-	}
-}
\ No newline at end of file
@@ -1,22 +1,50 @@
 package cli
 
 import (
+	"basic-interpreter/internal/ast"
+	"basic-interpreter/internal/interpreter"
+	"basic-interpreter/internal/runtime"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
+// ExecutorConfig holds options that change how programs are executed
+type ExecutorConfig struct {
+	Deterministic bool                     // Seed the random generator with a fixed seed instead of the clock
+	TraceMode     bool                     // Show each line after it runs, with the variables it changed
+	CommandRunner ast.CommandRunner        // Runs SHELL commands; nil leaves SHELL disabled
+	CleanNumbers  bool                     // Print numbers without the classic leading space
+	StrictNext    bool                     // Reject NEXT without a variable before running
+	CoerceCompare bool                     // Compare numbers with numeric strings as numbers
+	DecimalComma  bool                     // Read and write numbers with a decimal comma
+	Variables     map[string]runtime.Value // Variables set before the program runs
+	MaxSteps      int                      // Statements a run may execute; 0 for interpreter.DefaultMaxSteps, -1 for no limit
+	Context       context.Context          // Cancelling it interrupts the run, e.g. on Ctrl-C; nil never does
+	Stdin         io.Reader                // Read for the program when the file name is "-"; nil means os.Stdin
+}
+
 // FileExecutor handles file-based program execution
 type FileExecutor struct {
 	input  InputReader
 	output OutputWriter
+	config ExecutorConfig
 }
 
 // NewFileExecutor creates a new file executor instance
 func NewFileExecutor(input InputReader, output OutputWriter) *FileExecutor {
+	return NewFileExecutorWithConfig(input, output, ExecutorConfig{})
+}
+
+// NewFileExecutorWithConfig creates a new file executor instance with the given options
+func NewFileExecutorWithConfig(input InputReader, output OutputWriter, config ExecutorConfig) *FileExecutor {
 	return &FileExecutor{
 		input:  input,
 		output: output,
+		config: config,
 	}
 }
 
@@ -28,15 +56,37 @@ func (fe *FileExecutor) ExecuteFile(filename string, debugMode bool) error {
 		return fe.wrapFileError("failed to read file", filename, err)
 	}
 	
+	content, err = fe.expandIncludes(content, filename)
+	if err != nil {
+		return fe.wrapFileError("failed to expand includes in", filename, err)
+	}
+	
+	return fe.executeContent(content, filename, debugMode)
+}
+
+// ExecuteSource executes a BASIC program held in a string, without touching the file system
+// Errors are reported like file errors, with "source" in place of the file name
+func (fe *FileExecutor) ExecuteSource(source string, debugMode bool) error {
+	return fe.executeContent(source, "source", debugMode)
+}
+
+// executeContent parses and executes program text, naming its origin in errors
+func (fe *FileExecutor) executeContent(content, name string, debugMode bool) error {
 	// Parse program
 	program, err := fe.parseProgram(content)
 	if err != nil {
-		return fe.wrapFileError("syntax error in", filename, err)
+		return fe.wrapFileError("syntax error in", name, err)
 	}
 	
 	// Execute program
 	if err := fe.ExecuteProgram(program, debugMode); err != nil {
-		return fe.wrapFileError("runtime error in", filename, err)
+		// An interruption is not a fault in the program: report where it stopped, unwrapped
+		var interrupted *interpreter.InterruptedError
+		if errors.As(err, &interrupted) {
+			fe.output.WriteLine(interrupted.Error())
+			return interrupted
+		}
+		return fe.wrapFileError("runtime error in", name, err)
 	}
 	
 	return nil
@@ -47,8 +97,17 @@ func (fe *FileExecutor) wrapFileError(prefix, filename string, err error) error
 	return fmt.Errorf("%s %s: %w", prefix, filename, err)
 }
 
-// readFile reads the content of a file
+// readFile reads the content of a file, or all of standard input for the file name "-"
 func (fe *FileExecutor) readFile(filename string) (string, error) {
+	if filename == StdinFileName {
+		stdin := fe.config.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		data, err := io.ReadAll(stdin)
+		return string(data), err
+	}
+	
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return "", err
@@ -97,8 +156,13 @@ func (fe *FileExecutor) parseProgram(content string) (map[int]string, error) {
 
 // validateStatement performs basic syntax validation
 func (fe *FileExecutor) validateStatement(statement string) error {
+	// Comment text may contain anything
+	if isRemStatement(statement) {
+		return nil
+	}
+	
 	// Check for unterminated strings
-	quoteCount := strings.Count(statement, "\"")
+	quoteCount := strings.Count(stripComment(statement), "\"")
 	if quoteCount%2 != 0 {
 		return fmt.Errorf("unterminated string")
 	}
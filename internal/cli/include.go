@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// includePattern matches a $INCLUDE metacommand line
+// The directive may follow a line number and may sit inside a REM or apostrophe comment:
+//   $INCLUDE "lib.bas"
+//   10 REM $INCLUDE "lib.bas"
+//   ' $INCLUDE: 'lib.bas'
+var includePattern = regexp.MustCompile(`(?i)^(?:\d+\s+)?(?:REM\s*|')?\s*\$INCLUDE\s*:?\s*["']([^"']+)["']\s*$`)
+
+// expandIncludes replaces $INCLUDE lines in file content with the lines of the included files
+// Included paths are resolved relative to the including file, and included files may
+// include others; a file that includes itself, directly or indirectly, is an error
+func (fe *FileExecutor) expandIncludes(content, filename string) (string, error) {
+	return fe.expandIncludesFrom(content, filename, nil)
+}
+
+// expandIncludesFrom expands includes in content, given the chain of files being expanded
+func (fe *FileExecutor) expandIncludesFrom(content, filename string, chain []string) (string, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+	for _, visited := range chain {
+		if visited == path {
+			return "", fmt.Errorf("include cycle: %s", formatIncludeChain(append(chain, path)))
+		}
+	}
+	chain = append(chain, path)
+	
+	lines := strings.Split(content, "\n")
+	expanded := make([]string, 0, len(lines))
+	for lineIdx, line := range lines {
+		match := includePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			expanded = append(expanded, line)
+			continue
+		}
+		
+		includeName := match[1]
+		if !filepath.IsAbs(includeName) {
+			includeName = filepath.Join(filepath.Dir(path), includeName)
+		}
+		
+		included, err := fe.readFile(includeName)
+		if err != nil {
+			return "", fmt.Errorf("cannot include %s at line %d of %s: %w", match[1], lineIdx+1, filename, err)
+		}
+		
+		included, err = fe.expandIncludesFrom(included, includeName, chain)
+		if err != nil {
+			return "", err
+		}
+		expanded = append(expanded, included)
+	}
+	
+	return strings.Join(expanded, "\n"), nil
+}
+
+// formatIncludeChain renders a chain of included files by base name, e.g. "a.bas -> b.bas -> a.bas"
+func formatIncludeChain(chain []string) string {
+	names := make([]string, len(chain))
+	for i, path := range chain {
+		names[i] = filepath.Base(path)
+	}
+	return strings.Join(names, " -> ")
+}
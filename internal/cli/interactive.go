@@ -1,27 +1,34 @@
 package cli
 
 import (
+	"basic-interpreter/internal/ast"
+	"basic-interpreter/internal/lexer"
+	"basic-interpreter/internal/parser"
+	"basic-interpreter/internal/runtime"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 )
 
 // InteractiveMode handles the REPL functionality
 type InteractiveMode struct {
-	input     InputReader
-	output    OutputWriter
-	program   map[int]string // Store program lines as strings
-	order     []int          // Track line order
-	variables map[string]interface{} // Store variables
+	input   InputReader
+	output  OutputWriter
+	program map[int]string           // Store program lines as strings
+	order   []int                    // Track line order
+	env     *runtime.Environment     // Variables left by the last RUN, used by CALC
+	loaded  map[string]runtime.Value // Variables set by LOADVARS, which each RUN starts with until CLEAR
 }
 
 // NewInteractiveMode creates a new interactive mode instance
 func NewInteractiveMode(input InputReader, output OutputWriter) *InteractiveMode {
 	return &InteractiveMode{
-		input:     input,
-		output:    output,
-		program:   make(map[int]string),
-		order:     []int{},
-		variables: make(map[string]interface{}),
+		input:   input,
+		output:  output,
+		program: make(map[int]string),
+		order:   []int{},
+		env:     runtime.NewEnvironment(),
 	}
 }
 
@@ -75,19 +82,79 @@ func (im *InteractiveMode) displayError(err error) {
 // handleCommand handles special interactive commands
 // Returns true if the command was handled (including exit)
 func (im *InteractiveMode) handleCommand(line string) (bool, bool) {
-	switch strings.ToUpper(line) {
+	upper := strings.ToUpper(line)
+	if argument, ok := commandArgument(line, "CALC"); ok {
+		if err := im.calculate(argument); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
+	}
+	if argument, ok := commandArgument(line, "RUN"); ok {
+		start, end, err := parseLineRange(argument)
+		if err != nil {
+			im.displayError(err)
+		} else {
+			im.runProgram(start, end)
+		}
+		return true, false // Command handled, continue running
+	}
+	if argument, ok := commandArgument(line, "LIST"); ok {
+		start, end, err := parseListRange(argument)
+		if err != nil {
+			im.displayError(err)
+		} else {
+			im.listProgram(start, end)
+		}
+		return true, false // Command handled, continue running
+	}
+	if argument, ok := commandArgument(line, "SHOW"); ok {
+		if err := im.showExpression(argument); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
+	}
+	if argument, ok := commandArgument(line, "SAVE"); ok {
+		if err := im.saveProgram(argument); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
+	}
+	if argument, ok := commandArgument(line, "LOAD"); ok {
+		if err := im.loadProgram(argument); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
+	}
+	if argument, ok := commandArgument(line, "SAVEVARS"); ok {
+		if err := im.saveVariables(argument); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
+	}
+	if argument, ok := commandArgument(line, "LOADVARS"); ok {
+		if err := im.loadVariables(argument); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
+	}
+	
+	switch upper {
 	case "EXIT", "QUIT":
 		im.output.WriteLine(GoodbyeMessage)
 		return true, true // Command handled, should exit
-	case "LIST":
-		im.listProgram()
-		return true, false // Command handled, continue running
-	case "RUN":
-		im.runProgram()
-		return true, false // Command handled, continue running
 	case "CLEAR":
 		im.clearProgram()
 		return true, false // Command handled, continue running
+	case "LOOPS":
+		if err := im.showLoops(); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
+	case "SUBS":
+		if err := im.showSubroutines(); err != nil {
+			im.displayError(err)
+		}
+		return true, false // Command handled, continue running
 	default:
 		return false, false // Not a command
 	}
@@ -127,8 +194,13 @@ func (im *InteractiveMode) processLine(line string) error {
 
 // validateStatement performs basic syntax validation
 func (im *InteractiveMode) validateStatement(statement string) error {
+	// Comment text may contain anything
+	if isRemStatement(statement) {
+		return nil
+	}
+	
 	// Check for unterminated strings
-	quoteCount := strings.Count(statement, "\"")
+	quoteCount := strings.Count(stripComment(statement), "\"")
 	if quoteCount%2 != 0 {
 		return fmt.Errorf("unterminated string")
 	}
@@ -136,15 +208,50 @@ func (im *InteractiveMode) validateStatement(statement string) error {
 	return nil
 }
 
-// addLine adds or replaces a program line
+// addLine adds or replaces a program line, stored in canonical form
 func (im *InteractiveMode) addLine(lineNum int, statement string) {
-	im.program[lineNum] = statement
+	im.program[lineNum] = canonicalStatement(lineNum, statement)
 	
 	if !im.lineExists(lineNum) {
 		im.insertLineInOrder(lineNum)
 	}
 }
 
+// canonicalStatement returns a statement as its parsed form renders it, so LIST shows
+// every line with uppercase keywords and single spaces whatever the spacing typed
+// A line that does not parse is kept as typed for RUN to report, and so is a line
+// ending in a comment, which the parsed form would lose
+func canonicalStatement(lineNum int, statement string) string {
+	if hasTrailingComment(statement) {
+		return statement
+	}
+	
+	// The line is parsed on its own, so its jumps cannot be checked against the program
+	program, err := parser.NewParser(lexer.NewLexer(fmt.Sprintf("%d %s", lineNum, statement))).ParseProgramLines()
+	if err != nil {
+		return statement
+	}
+	stmt, exists := program.Lines[lineNum]
+	if !exists || len(program.Lines) != 1 {
+		return statement
+	}
+	return stmt.String()
+}
+
+// hasTrailingComment reports whether a comment follows a statement, as in PRINT X ' note
+// A comment starting the statement, or following a colon, THEN or ELSE, is a REM statement of its own
+func hasTrailingComment(statement string) bool {
+	lex := lexer.NewLexer(statement)
+	previous := lexer.COLON
+	for token := lex.NextToken(); token.Type != lexer.EOF; token = lex.NextToken() {
+		if token.Type == lexer.REM && previous != lexer.COLON && previous != lexer.THEN && previous != lexer.ELSE {
+			return true
+		}
+		previous = token.Type
+	}
+	return false
+}
+
 // lineExists checks if a line number already exists in the program
 func (im *InteractiveMode) lineExists(lineNum int) bool {
 	for _, num := range im.order {
@@ -185,22 +292,25 @@ func (im *InteractiveMode) removeLineFromOrder(lineNum int) {
 	}
 }
 
-// listProgram displays the current program
-func (im *InteractiveMode) listProgram() {
+// listProgram displays the current program lines from start to end, inclusive
+func (im *InteractiveMode) listProgram(start, end int) {
 	if len(im.program) == 0 {
 		im.output.WriteLine(NoProgramLoadedMessage)
 		return
 	}
 	
 	for _, lineNum := range im.order {
+		if lineNum < start || lineNum > end {
+			continue
+		}
 		if statement, exists := im.program[lineNum]; exists {
 			im.output.WriteLine(fmt.Sprintf("%d %s", lineNum, statement))
 		}
 	}
 }
 
-// runProgram executes the current program
-func (im *InteractiveMode) runProgram() {
+// runProgram executes the current program lines from start to end, inclusive
+func (im *InteractiveMode) runProgram(start, end int) {
 	if len(im.program) == 0 {
 		im.output.WriteLine(NoProgramMessage)
 		return
@@ -208,11 +318,15 @@ func (im *InteractiveMode) runProgram() {
 	
 	im.output.WriteLine(RunningProgramMessage)
 	
-	// Create a file executor to handle the execution logic
-	fileExecutor := NewFileExecutor(im.input, im.output)
+	// Create a file executor to handle the execution logic, passing on the variables
+	// loaded by LOADVARS as -D passes its presets
+	fileExecutor := NewFileExecutorWithConfig(im.input, im.output, ExecutorConfig{Variables: im.loaded})
+	
+	// Each run starts from a fresh environment, which is kept for CALC afterwards
+	im.env = fileExecutor.newEnvironment()
 	
 	// Execute the program using the same logic as file execution
-	if err := fileExecutor.ExecuteProgram(im.program, false); err != nil {
+	if err := fileExecutor.executeProgramRangeInEnvironment(im.program, im.env, false, start, end); err != nil {
 		im.output.WriteLine(fmt.Sprintf("Runtime error: %s", err.Error()))
 		return
 	}
@@ -220,12 +334,236 @@ func (im *InteractiveMode) runProgram() {
 	im.output.WriteLine(ProgramCompletedMessage)
 }
 
+// commandArgument checks whether line is the given command and returns the text after it
+// The command name is matched case-insensitively and must be followed by a space or end of line
+func commandArgument(line, command string) (string, bool) {
+	upper := strings.ToUpper(line)
+	if upper != command && !strings.HasPrefix(upper, command+" ") {
+		return "", false
+	}
+	return strings.TrimSpace(line[len(command):]), true
+}
+
+// parseCommandExpression parses the expression argument of an interactive command
+func parseCommandExpression(command, source string) (ast.Expression, error) {
+	if source == "" {
+		return nil, fmt.Errorf("%s requires an expression", command)
+	}
+	
+	p := parser.NewParser(lexer.NewLexer(source))
+	expr, err := p.ParseStandaloneExpression()
+	if err != nil {
+		return nil, fmt.Errorf("syntax error: %w", err)
+	}
+	return expr, nil
+}
+
+// calculate evaluates an expression with the current variables and prints the result
+func (im *InteractiveMode) calculate(source string) error {
+	expr, err := parseCommandExpression("CALC", source)
+	if err != nil {
+		return err
+	}
+	
+	result, err := ast.FormatPrintLine([]ast.Expression{expr}, nil, im.env)
+	if err != nil {
+		return err
+	}
+	
+	im.output.WriteLine(result)
+	return nil
+}
+
+// showExpression prints an expression fully parenthesized, to show how it was parsed
+func (im *InteractiveMode) showExpression(source string) error {
+	expr, err := parseCommandExpression("SHOW", source)
+	if err != nil {
+		return err
+	}
+	
+	im.output.WriteLine(ast.FormatExpression(expr))
+	return nil
+}
+
+// fileNameArgument returns the file name given to an interactive command, with or without quotes
+func fileNameArgument(command, argument string) (string, error) {
+	name := strings.TrimSpace(strings.Trim(argument, "\""))
+	if name == "" {
+		return "", fmt.Errorf("%s requires a file name", command)
+	}
+	return name, nil
+}
 
+// saveProgram writes the program lines to a file in order, as LIST shows them
+func (im *InteractiveMode) saveProgram(argument string) error {
+	fileName, err := fileNameArgument("SAVE", argument)
+	if err != nil {
+		return err
+	}
+	if len(im.program) == 0 {
+		im.output.WriteLine(NoProgramLoadedMessage)
+		return nil
+	}
+	
+	var content strings.Builder
+	for _, lineNum := range im.order {
+		fmt.Fprintf(&content, "%d %s\n", lineNum, im.program[lineNum])
+	}
+	if err := os.WriteFile(fileName, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("cannot save program: %w", err)
+	}
+	
+	im.output.WriteLine(fmt.Sprintf(ProgramSavedMessage, len(im.order)))
+	return nil
+}
+
+// loadProgram replaces the program with the lines read from a file
+// The current program is kept when the file cannot be read or has invalid lines
+func (im *InteractiveMode) loadProgram(argument string) error {
+	fileName, err := fileNameArgument("LOAD", argument)
+	if err != nil {
+		return err
+	}
+	
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("cannot load program: %w", err)
+	}
+	program, err := NewFileExecutor(im.input, im.output).parseProgram(string(content))
+	if err != nil {
+		return fmt.Errorf("cannot load program from %s: %w", fileName, err)
+	}
+	
+	im.program = make(map[int]string)
+	im.order = []int{}
+	for lineNum, statement := range program {
+		im.addLine(lineNum, statement)
+	}
+	
+	im.output.WriteLine(fmt.Sprintf(ProgramLoadedMessage, len(im.order)))
+	return nil
+}
+
+// saveVariables writes the current variables to a file, so LOADVARS can restore them later
+func (im *InteractiveMode) saveVariables(argument string) error {
+	fileName, err := fileNameArgument("SAVEVARS", argument)
+	if err != nil {
+		return err
+	}
+	
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("cannot save variables: %w", err)
+	}
+	if err := SaveVariables(im.env, file); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot save variables: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("cannot save variables: %w", err)
+	}
+	
+	im.output.WriteLine(fmt.Sprintf(VariablesSavedMessage, len(im.env.Variables)))
+	return nil
+}
+
+// loadVariables sets the variables saved by SAVEVARS in the current environment,
+// and remembers them so that the program finds them when it is RUN
+func (im *InteractiveMode) loadVariables(argument string) error {
+	fileName, err := fileNameArgument("LOADVARS", argument)
+	if err != nil {
+		return err
+	}
+	
+	file, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("cannot load variables: %w", err)
+	}
+	defer file.Close()
+	
+	loaded := runtime.NewEnvironment()
+	if err := LoadVariables(loaded, file); err != nil {
+		return fmt.Errorf("cannot load variables from %s: %w", fileName, err)
+	}
+	
+	if im.loaded == nil {
+		im.loaded = make(map[string]runtime.Value)
+	}
+	for name, value := range loaded.Variables {
+		im.env.SetVariable(name, value)
+		im.loaded[name] = value
+	}
+	
+	im.output.WriteLine(VariablesLoadedMessage)
+	return nil
+}
+
+// showLoops prints which NEXT line closes each FOR line, to troubleshoot mispaired loops
+func (im *InteractiveMode) showLoops() error {
+	if len(im.program) == 0 {
+		im.output.WriteLine(NoProgramLoadedMessage)
+		return nil
+	}
+	
+	fileExecutor := NewFileExecutor(im.input, im.output)
+	program, err := fileExecutor.buildProgram(fileExecutor.programToSourceCode(im.program))
+	if err != nil {
+		return fmt.Errorf("syntax error: %w", err)
+	}
+	
+	links, err := ast.ForNextLinks(program)
+	if err != nil {
+		return err
+	}
+	
+	forLines := make([]int, 0, len(links))
+	for forLine := range links {
+		forLines = append(forLines, forLine)
+	}
+	sort.Ints(forLines)
+	for _, forLine := range forLines {
+		im.output.WriteLine(fmt.Sprintf("FOR %d -> NEXT %d", forLine, links[forLine]))
+	}
+	return nil
+}
+
+// showSubroutines lists each GOSUB target with its REM label and the lines that call it
+func (im *InteractiveMode) showSubroutines() error {
+	if len(im.program) == 0 {
+		im.output.WriteLine(NoProgramLoadedMessage)
+		return nil
+	}
+	
+	fileExecutor := NewFileExecutor(im.input, im.output)
+	program, err := fileExecutor.buildProgram(fileExecutor.programToSourceCode(im.program))
+	if err != nil {
+		return fmt.Errorf("syntax error: %w", err)
+	}
+	
+	subroutines := ast.Subroutines(program)
+	if len(subroutines) == 0 {
+		im.output.WriteLine(NoSubroutinesMessage)
+		return nil
+	}
+	for _, sub := range subroutines {
+		callers := make([]string, len(sub.Callers))
+		for i, caller := range sub.Callers {
+			callers[i] = fmt.Sprint(caller)
+		}
+		label := fmt.Sprint(sub.Line)
+		if sub.Name != "" {
+			label += " " + sub.Name
+		}
+		im.output.WriteLine(fmt.Sprintf("%s (called from %s)", label, strings.Join(callers, ", ")))
+	}
+	return nil
+}
 
 // clearProgram clears the current program
 func (im *InteractiveMode) clearProgram() {
 	im.program = make(map[int]string)
 	im.order = []int{}
-	im.variables = make(map[string]interface{})
+	im.env = runtime.NewEnvironment()
+	im.loaded = nil
 	im.output.WriteLine(ProgramClearedMessage)
 }
\ No newline at end of file
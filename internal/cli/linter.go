@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"basic-interpreter/internal/ast"
+	"fmt"
+)
+
+// LintFile loads a BASIC program and reports static warnings without executing it
+// Warnings are written to the output; only load and syntax problems are returned as errors
+func (fe *FileExecutor) LintFile(filename string) error {
+	content, err := fe.readFile(filename)
+	if err != nil {
+		return fe.wrapFileError("failed to read file", filename, err)
+	}
+
+	content, err = fe.expandIncludes(content, filename)
+	if err != nil {
+		return fe.wrapFileError("failed to expand includes in", filename, err)
+	}
+
+	program, err := fe.parseProgram(content)
+	if err != nil {
+		return fe.wrapFileError("syntax error in", filename, err)
+	}
+
+	warnings, err := fe.LintProgram(program)
+	if err != nil {
+		return fe.wrapFileError("syntax error in", filename, err)
+	}
+
+	for _, warning := range warnings {
+		fe.output.WriteLine(warning)
+	}
+	return nil
+}
+
+// LintProgram parses a program and returns the warnings found by static analysis,
+// after an error for each jump to a missing line, worded as CheckSource does
+func (fe *FileExecutor) LintProgram(program map[int]string) ([]string, error) {
+	if len(program) == 0 {
+		return nil, nil
+	}
+
+	astProgram, err := fe.parseSource(fe.programToSourceCode(program), false)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, jump := range ast.MissingJumpTargets(astProgram) {
+		warnings = append(warnings, "Error: "+jump.Error())
+	}
+	for _, lineNumber := range ast.UnreachableLines(astProgram) {
+		warnings = append(warnings, fmt.Sprintf("Warning: line %d is unreachable", lineNumber))
+	}
+	for _, fall := range ast.SubroutineFallThroughs(astProgram) {
+		warnings = append(warnings, fmt.Sprintf("Warning: line %d falls through into subroutine at line %d", fall.Line, fall.Subroutine))
+	}
+	for _, mismatch := range ast.CheckTypes(astProgram) {
+		warnings = append(warnings, "Warning: "+mismatch.Error())
+	}
+	return warnings, nil
+}
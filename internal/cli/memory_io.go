@@ -0,0 +1,53 @@
+package cli
+
+import "io"
+
+// MemoryInputReader implements InputReader over a fixed list of lines
+// It returns io.EOF once every line has been read
+type MemoryInputReader struct {
+	lines []string
+	index int
+}
+
+// NewMemoryInputReader creates an input reader that returns the given lines in order
+func NewMemoryInputReader(lines []string) *MemoryInputReader {
+	return &MemoryInputReader{lines: lines}
+}
+
+// ReadLine returns the next line, or io.EOF when there are none left
+func (r *MemoryInputReader) ReadLine() (string, error) {
+	if r.index >= len(r.lines) {
+		return "", io.EOF
+	}
+	line := r.lines[r.index]
+	r.index++
+	return line, nil
+}
+
+// MemoryOutputWriter implements OutputWriter by collecting output in memory
+// Each WriteLine and each Write call is stored as one entry
+type MemoryOutputWriter struct {
+	lines []string
+}
+
+// NewMemoryOutputWriter creates an empty in-memory output writer
+func NewMemoryOutputWriter() *MemoryOutputWriter {
+	return &MemoryOutputWriter{}
+}
+
+// WriteLine stores a line of output
+func (w *MemoryOutputWriter) WriteLine(line string) error {
+	w.lines = append(w.lines, line)
+	return nil
+}
+
+// Write stores raw output data as a single entry
+func (w *MemoryOutputWriter) Write(data []byte) (int, error) {
+	w.lines = append(w.lines, string(data))
+	return len(data), nil
+}
+
+// Lines returns the output collected so far
+func (w *MemoryOutputWriter) Lines() []string {
+	return w.lines
+}
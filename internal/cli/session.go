@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+)
+
+// SessionRecorder wraps an InputReader and saves every line it returns, commands and
+// program input alike, so the session can be replayed later with LoadSession
+// Unlike a Transcript, the recording holds input only, one line per line read
+type SessionRecorder struct {
+	input InputReader
+	log   *Transcript
+}
+
+// NewSessionRecorder creates an input reader that records each line to the writer
+func NewSessionRecorder(input InputReader, writer io.Writer) *SessionRecorder {
+	return &SessionRecorder{
+		input: input,
+		log:   NewTranscript(writer),
+	}
+}
+
+// ReadLine reads a line from the wrapped reader and records it
+func (r *SessionRecorder) ReadLine() (string, error) {
+	line, err := r.input.ReadLine()
+	if err != nil {
+		return line, err
+	}
+	r.log.record(line + "\n")
+	return line, nil
+}
+
+// Err returns the first error that occurred while writing the recording
+func (r *SessionRecorder) Err() error {
+	return r.log.Err()
+}
+
+// LoadSession reads a recorded session and returns an input reader that replays it line by line
+func LoadSession(reader io.Reader) (InputReader, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewMemoryInputReader(lines), nil
+}
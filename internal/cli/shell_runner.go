@@ -0,0 +1,16 @@
+package cli
+
+import (
+	"os/exec"
+)
+
+// ExecCommandRunner runs SHELL commands through the system shell
+// It gives BASIC programs the same access as the interpreter process, so it is
+// only installed when the user passes --allow-shell
+type ExecCommandRunner struct{}
+
+// RunCommand runs the command with sh -c and returns its standard output
+func (r ExecCommandRunner) RunCommand(command string) (string, error) {
+	output, err := exec.Command("sh", "-c", command).Output()
+	return string(output), err
+}
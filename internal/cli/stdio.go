@@ -1,52 +1,26 @@
 package cli
 
 import (
-	"bufio"
-	"fmt"
-	"io"
 	"os"
 )
 
 // StdInputReader implements InputReader using standard input
 type StdInputReader struct {
-	scanner *bufio.Scanner
+	*StreamInputReader
 }
 
 // NewStdInputReader creates a new standard input reader
+// Terminals deliver input line by line, so ReadKey waits for Enter
 func NewStdInputReader() *StdInputReader {
-	return &StdInputReader{
-		scanner: bufio.NewScanner(os.Stdin),
-	}
-}
-
-// ReadLine reads a line from standard input
-func (r *StdInputReader) ReadLine() (string, error) {
-	if r.scanner.Scan() {
-		return r.scanner.Text(), nil
-	}
-	
-	if err := r.scanner.Err(); err != nil {
-		return "", err
-	}
-	
-	return "", io.EOF
+	return &StdInputReader{NewStreamInputReader(os.Stdin)}
 }
 
 // StdOutputWriter implements OutputWriter using standard output
-type StdOutputWriter struct{}
+type StdOutputWriter struct {
+	*StreamOutputWriter
+}
 
 // NewStdOutputWriter creates a new standard output writer
 func NewStdOutputWriter() *StdOutputWriter {
-	return &StdOutputWriter{}
+	return &StdOutputWriter{NewStreamOutputWriter(os.Stdout)}
 }
-
-// WriteLine writes a line to standard output
-func (w *StdOutputWriter) WriteLine(line string) error {
-	_, err := fmt.Println(line)
-	return err
-}
-
-// Write writes data to standard output
-func (w *StdOutputWriter) Write(data []byte) (int, error) {
-	return os.Stdout.Write(data)
-}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// StreamInputReader implements InputReader by reading lines from any io.Reader
+type StreamInputReader struct {
+	scanner *bufio.Scanner
+}
+
+// NewStreamInputReader creates an input reader that reads lines from the given stream
+func NewStreamInputReader(reader io.Reader) *StreamInputReader {
+	return &StreamInputReader{
+		scanner: bufio.NewScanner(reader),
+	}
+}
+
+// ReadLine reads the next line, or returns io.EOF when the stream has no more
+func (r *StreamInputReader) ReadLine() (string, error) {
+	if r.scanner.Scan() {
+		return r.scanner.Text(), nil
+	}
+	
+	if err := r.scanner.Err(); err != nil {
+		return "", err
+	}
+	
+	return "", io.EOF
+}
+
+// ReadKey reads a key from the stream
+// Streams deliver input line by line, so this reads a whole line and returns the first
+// character (or a newline when the line is empty), which works on every platform
+func (r *StreamInputReader) ReadKey() (rune, error) {
+	line, err := r.ReadLine()
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range line {
+		return key, nil
+	}
+	return '\n', nil
+}
+
+// StreamOutputWriter implements OutputWriter by writing to any io.Writer
+type StreamOutputWriter struct {
+	writer io.Writer
+}
+
+// NewStreamOutputWriter creates an output writer that writes to the given stream
+func NewStreamOutputWriter(writer io.Writer) *StreamOutputWriter {
+	return &StreamOutputWriter{writer: writer}
+}
+
+// WriteLine writes a line followed by a newline
+func (w *StreamOutputWriter) WriteLine(line string) error {
+	_, err := fmt.Fprintln(w.writer, line)
+	return err
+}
+
+// Write writes data as it is
+func (w *StreamOutputWriter) Write(data []byte) (int, error) {
+	return w.writer.Write(data)
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"io"
+)
+
+// TranscriptInputPrefix marks lines in a transcript that were read as input
+const TranscriptInputPrefix = "> "
+
+// Transcript records program output and consumed input, interleaved in the order they happen
+// Output is written as it was produced; each input line is written prefixed with TranscriptInputPrefix
+type Transcript struct {
+	writer io.Writer
+	err    error // First write error, reported by Err
+}
+
+// NewTranscript creates a transcript that writes to the given writer
+func NewTranscript(writer io.Writer) *Transcript {
+	return &Transcript{writer: writer}
+}
+
+// Err returns the first error that occurred while writing the transcript
+func (t *Transcript) Err() error {
+	return t.err
+}
+
+// record writes data to the transcript, keeping the first error
+// Recording never fails the program; problems are reported through Err after the run
+func (t *Transcript) record(data string) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = io.WriteString(t.writer, data)
+}
+
+// RecordingInputReader wraps an InputReader and records each line it returns
+type RecordingInputReader struct {
+	input      InputReader
+	transcript *Transcript
+}
+
+// NewRecordingInputReader creates an input reader that records into the transcript
+func NewRecordingInputReader(input InputReader, transcript *Transcript) *RecordingInputReader {
+	return &RecordingInputReader{
+		input:      input,
+		transcript: transcript,
+	}
+}
+
+// ReadLine reads a line from the wrapped reader and records it
+func (r *RecordingInputReader) ReadLine() (string, error) {
+	line, err := r.input.ReadLine()
+	if err != nil {
+		return line, err
+	}
+	r.transcript.record(TranscriptInputPrefix + line + "\n")
+	return line, nil
+}
+
+// RecordingOutputWriter wraps an OutputWriter and records everything written to it
+type RecordingOutputWriter struct {
+	output     OutputWriter
+	transcript *Transcript
+}
+
+// NewRecordingOutputWriter creates an output writer that records into the transcript
+func NewRecordingOutputWriter(output OutputWriter, transcript *Transcript) *RecordingOutputWriter {
+	return &RecordingOutputWriter{
+		output:     output,
+		transcript: transcript,
+	}
+}
+
+// WriteLine writes a line to the wrapped writer and records it
+func (w *RecordingOutputWriter) WriteLine(line string) error {
+	w.transcript.record(line + "\n")
+	return w.output.WriteLine(line)
+}
+
+// Write writes data to the wrapped writer and records it
+func (w *RecordingOutputWriter) Write(data []byte) (int, error) {
+	w.transcript.record(string(data))
+	return w.output.Write(data)
+}
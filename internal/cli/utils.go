@@ -1,6 +1,9 @@
 package cli
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // parseLineNumber parses a string as a line number
 func parseLineNumber(s string) (int, error) {
@@ -24,4 +27,62 @@ func validateLineNumber(lineNum int) error {
 			lineNum, MinLineNumber, MaxLineNumber)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// parseLineRange parses the argument of RUN: "start-end", "start", or empty for the whole program
+func parseLineRange(s string) (int, int, error) {
+	if s == "" {
+		return MinLineNumber, MaxLineNumber, nil
+	}
+	
+	startText, endText, isRange := strings.Cut(s, "-")
+	start, err := parseLineNumber(strings.TrimSpace(startText))
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return start, MaxLineNumber, nil
+	}
+	
+	end, err := parseLineNumber(strings.TrimSpace(endText))
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid line range: %s", s)
+	}
+	return start, end, nil
+}
+
+// parseListRange parses the argument of LIST: empty for every line, "30" for a single
+// line or "20-40" for the lines from 20 to 40 inclusive
+func parseListRange(s string) (int, int, error) {
+	start, end, err := parseLineRange(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	if s != "" && !strings.Contains(s, "-") {
+		end = start
+	}
+	return start, end, nil
+}
+
+// isRemStatement reports whether a statement is a REM comment
+func isRemStatement(statement string) bool {
+	upper := strings.ToUpper(statement)
+	return upper == "REM" || strings.HasPrefix(upper, "REM ")
+}
+
+// stripComment removes a trailing ' comment, leaving apostrophes inside string literals alone
+func stripComment(statement string) string {
+	inString := false
+	for i, ch := range statement {
+		switch {
+		case ch == '"':
+			inString = !inString
+		case ch == '\'' && !inString:
+			return statement[:i]
+		}
+	}
+	return statement
+}
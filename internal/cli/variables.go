@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"basic-interpreter/internal/runtime"
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SaveVariables writes the environment's variables one per line as NAME=value, sorted by name
+// String values are quoted and numbers are written with full precision, so LoadVariables
+// restores both the type and the exact value
+func SaveVariables(env *runtime.Environment, writer io.Writer) error {
+	names := make([]string, 0, len(env.Variables))
+	for name := range env.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := env.Variables[name]
+		text := strconv.FormatFloat(value.NumValue, 'g', -1, 64)
+		if value.Type == runtime.StringValue {
+			text = strconv.Quote(value.StrValue)
+		}
+		if _, err := fmt.Fprintf(writer, "%s=%s\n", name, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadVariables reads variables written by SaveVariables and sets them in the environment
+// Variables not in the dump are left alone. Nothing is set if any line is invalid
+func LoadVariables(env *runtime.Environment, reader io.Reader) error {
+	values := make(map[string]runtime.Value)
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		name, value, err := parseVariableLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		values[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for name, value := range values {
+		env.SetVariable(name, value)
+	}
+	return nil
+}
+
+// parseVariableLine parses a NAME=value line, where quoted values are strings
+func parseVariableLine(line string) (string, runtime.Value, error) {
+	name, text, found := strings.Cut(line, "=")
+	name = strings.TrimSpace(name)
+	text = strings.TrimSpace(text)
+	if !found || !isVariableName(name) {
+		return "", runtime.Value{}, fmt.Errorf("invalid variable %q: expected NAME=value", line)
+	}
+
+	if strings.HasPrefix(text, "\"") {
+		str, err := strconv.Unquote(text)
+		if err != nil {
+			return "", runtime.Value{}, fmt.Errorf("invalid string value for %s: %s", name, text)
+		}
+		return name, runtime.NewStringValue(str), nil
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return "", runtime.Value{}, fmt.Errorf("invalid numeric value for %s: %s", name, text)
+	}
+	return name, runtime.NewNumericValue(number), nil
+}
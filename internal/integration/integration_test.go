@@ -1,7 +1,12 @@
 package integration
 
 import (
+	"basic-interpreter/basic"
 	"basic-interpreter/internal/cli"
+	"basic-interpreter/internal/interpreter"
+	"basic-interpreter/internal/lexer"
+	"basic-interpreter/internal/parser"
+	"basic-interpreter/internal/runtime"
 	"fmt"
 	"os"
 	"strings"
@@ -44,20 +49,15 @@ func (m *MockInputReader) ReadLine() (string, error) {
 
 // Helper function to execute a BASIC program from source code
 func executeProgram(t *testing.T, source string, debugMode bool) ([]string, error) {
-	// Create output capture
-	output := &MockOutputWriter{}
-	
-	// Use the file executor approach which handles the full pipeline
-	input := &MockInputReader{}
-	fileExecutor := cli.NewFileExecutor(input, output)
-	
-	// Create a temporary file with the source code
-	tmpFile := createTempBasicFile(t, source)
-	defer removeTempFile(t, tmpFile)
+	if !debugMode {
+		return basic.RunString(source, nil)
+	}
 	
-	// Execute the file
-	err := fileExecutor.ExecuteFile(tmpFile, debugMode)
-	return output.Lines, err
+	// Debug output is only available through the file executor
+	output := cli.NewMemoryOutputWriter()
+	fileExecutor := cli.NewFileExecutor(cli.NewMemoryInputReader(nil), output)
+	err := fileExecutor.ExecuteSource(source, debugMode)
+	return output.Lines(), err
 }
 
 // Helper function to assert that output contains expected strings
@@ -82,14 +82,14 @@ func assertErrorContains(t *testing.T, err error, expectedText string) {
 
 // Helper function to execute program and expect success
 func executeAndExpectSuccess(t *testing.T, source string) []string {
-	output, err := executeProgram(t, source, false)
+	output, err := basic.RunString(source, nil)
 	require.NoError(t, err)
 	return output
 }
 
 // Helper function to execute program and expect error
 func executeAndExpectError(t *testing.T, source string, expectedError string) {
-	_, err := executeProgram(t, source, false)
+	_, err := basic.RunString(source, nil)
 	assertErrorContains(t, err, expectedError)
 }
 
@@ -139,13 +139,13 @@ func TestIntegration_VariableAssignmentAndArithmetic(t *testing.T) {
 	output := executeAndExpectSuccess(t, source)
 	
 	expected := []string{
-		"A = 5",
-		"B = 10", 
-		"C = 15",
-		"D = 30",
-		"E = 10",
-		"F = 9",
-		"G = 81",
+		"A =            5",
+		"B =            10", 
+		"C =            15",
+		"D =            30",
+		"E =            10",
+		"F =            9",
+		"G =            81",
 	}
 	assert.Equal(t, expected, output)
 }
@@ -168,25 +168,45 @@ func TestIntegration_StringVariablesAndOperations(t *testing.T) {
 	
 	expected := []string{
 		"Hello World!",
-		"Length: 12",
-		"Middle: World",
-		"Number as string: 42",
-		"String as number: 123.45",
+		"Length:        12",
+		"Middle:       World",
+		"Number as string:           42",
+		"String as number:            123.45",
 	}
 	assert.Equal(t, expected, output)
 }
 
 func TestIntegration_ControlFlowGoto(t *testing.T) {
-	// GOTO statements require program references which aren't set up in the current parser
-	// This test demonstrates that GOTO statements are detected but fail due to missing program reference
-	source := `10 PRINT "Start"
-20 GOTO 50
+	t.Run("forward jump", func(t *testing.T) {
+		source := `10 PRINT "Start"
+20 GOTO 40
 30 PRINT "This should be skipped"
 40 PRINT "End"`
+		
+		output := executeAndExpectSuccess(t, source)
+		assert.Equal(t, []string{"Start", "End"}, output)
+	})
 	
-	_, err := executeProgram(t, source, false)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "program reference is nil")
+	t.Run("backward jump", func(t *testing.T) {
+		source := `10 I = 0
+20 I = I + 1
+30 PRINT I
+40 IF I < 3 THEN GOTO 20
+50 PRINT "Done"`
+		
+		output := executeAndExpectSuccess(t, source)
+		assert.Equal(t, []string{" 1", " 2", " 3", "Done"}, output)
+	})
+	
+	t.Run("parsed program is runnable without extra wiring", func(t *testing.T) {
+		program, err := parser.NewParser(lexer.NewLexer(`10 I = I + 1
+20 IF I < 5 THEN GOTO 10`)).ParseProgram()
+		require.NoError(t, err)
+		
+		env := runtime.NewEnvironment()
+		require.NoError(t, interpreter.NewBasicInterpreter(false).Execute(program, env))
+		assert.Equal(t, 5.0, env.GetVariable("I").NumValue)
+	})
 }
 
 func TestIntegration_ConditionalStatements(t *testing.T) {
@@ -217,6 +237,224 @@ func TestIntegration_ConditionalStatements(t *testing.T) {
 	assert.Equal(t, expected, output)
 }
 
+func TestIntegration_IfThenElse(t *testing.T) {
+	source := `10 FOR X = 4 TO 6
+20 IF X > 5 THEN PRINT "big", X ELSE PRINT "small", X
+30 NEXT X
+40 IF X = 0 THEN GOTO 60 ELSE GOTO 70
+50 PRINT "not reached"
+60 PRINT "not reached either"
+70 PRINT "done"`
+	
+	output, err := executeProgram(t, source, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"small          4", "small          5", "big            6", "done"}, output)
+}
+
+func TestIntegration_WhileWend(t *testing.T) {
+	source := `10 I = 1
+20 WHILE I <= 2
+30 FOR J = 1 TO 2
+40 PRINT I, J
+50 NEXT J
+60 I = I + 1
+70 WEND
+80 WHILE I > 10
+90 WHILE 1
+100 WEND
+110 PRINT "never"
+120 WEND
+130 PRINT "done", I`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 1             1", " 1             2", " 2             1", " 2             2", "done           3"}, output)
+	
+	executeAndExpectError(t, "10 PRINT 1\n20 WEND", "WEND without WHILE")
+	executeAndExpectError(t, "10 WHILE 0\n20 PRINT 1", "WHILE without WEND")
+}
+
+func TestIntegration_DoLoop(t *testing.T) {
+	source := `10 I = 0
+20 DO
+30 I = I + 1
+40 LOOP UNTIL I >= 3
+50 PRINT I
+60 DO WHILE I > 0
+70 PRINT "count", I
+80 I = I - 1
+90 LOOP
+100 DO UNTIL 1
+110 PRINT "never"
+120 LOOP
+130 DO
+140 I = I + 1
+150 IF I = 5 THEN GOTO 170
+160 LOOP
+170 PRINT "out", I`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 3", "count          3", "count          2", "count          1", "out            5"}, output)
+	
+	executeAndExpectError(t, "10 PRINT 1\n20 LOOP", "LOOP without DO")
+	executeAndExpectError(t, "10 DO WHILE 0\n20 PRINT 1", "DO without LOOP")
+}
+
+func TestIntegration_PrintTabAndSpc(t *testing.T) {
+	source := `10 PRINT "Name"; TAB(10); "Score"
+20 PRINT "Ada"; TAB(10); 42
+30 PRINT "A"; SPC(3); "B"
+40 PRINT "Too long for it"; TAB(4); "X"`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{"Name     Score", "Ada       42", "A   B", "Too long for it\n   X"}, output)
+}
+
+func TestIntegration_LineInput(t *testing.T) {
+	source := `10 LINE INPUT "Address? "; A$
+20 PRINT "[" + A$ + "]"
+30 PRINT LEN(A$)`
+	
+	input := &MockInputReader{Inputs: []string{"  12 Main St, Springfield  "}}
+	output := &MockOutputWriter{}
+	err := cli.NewFileExecutor(input, output).ExecuteSource(source, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Address? ", "[  12 Main St, Springfield  ]", " 27"}, output.Lines)
+	
+	executeAndExpectError(t, "10 LINE INPUT A", "LINE INPUT requires a string variable")
+}
+
+func TestIntegration_DefFn(t *testing.T) {
+	source := `10 DEF FNSQ(X) = X * X
+20 DEF FNGREET$(N$) = "Hello, " + N$
+30 X = 7
+40 PRINT FNSQ(5) + FNSQ(X)
+50 PRINT FNGREET$("Ada")
+60 PRINT X`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 74", "Hello, Ada", " 7"}, output)
+	
+	executeAndExpectError(t, "10 PRINT FNSQ(2)", "undefined function: FNSQ")
+	executeAndExpectError(t, "10 PRINT FNSQ(2)\n20 DEF FNSQ(X) = X * X", "undefined function: FNSQ")
+}
+
+func TestIntegration_Stop(t *testing.T) {
+	source := `10 PRINT "before"
+20 IF 1 THEN STOP
+30 PRINT "after"`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{"before"}, output)
+	
+	output, err := executeProgram(t, source, true)
+	require.NoError(t, err)
+	assert.Contains(t, output, "STOPPED at line 20")
+	assert.NotContains(t, output, "after")
+}
+
+func TestIntegration_TraceMode(t *testing.T) {
+	source := `10 N = 1
+20 PRINT N
+30 N = N + 1: NAME$ = "Ada"
+40 FOR I = 1 TO 2
+50 NEXT I`
+	
+	output := &MockOutputWriter{}
+	fileExecutor := cli.NewFileExecutorWithConfig(cli.NewMemoryInputReader(nil), output, cli.ExecutorConfig{TraceMode: true})
+	require.NoError(t, fileExecutor.ExecuteSource(source, false))
+	
+	expected := []string{
+		"[10] N = 1",
+		" 1",
+		"[20]",
+		`[30] N = 2, NAME$ = "Ada"`,
+		"[40] I = 1",
+		"[50] I = 2",
+		"[50] I = 3",
+	}
+	assert.Equal(t, expected, output.Lines)
+}
+
+func TestIntegration_Arrays(t *testing.T) {
+	source := `10 DIM SQ(5), NAME$(2)
+20 FOR I = 0 TO 5
+30 SQ(I) = I * I
+40 NEXT I
+50 NAME$(1) = "Ada"
+60 PRINT SQ(3), SQ(5)
+70 PRINT NAME$(1), LEN(NAME$(2))`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 9             25", "Ada            0"}, output)
+	
+	executeAndExpectError(t, "10 DIM A(10)\n20 PRINT A(11)", "index 11 out of bounds for array A(10)")
+	executeAndExpectError(t, "10 DIM A(10)\n20 A(-1) = 1", "index -1 out of bounds for array A(10)")
+}
+
+func TestIntegration_PrintArray(t *testing.T) {
+	source := `10 DIM A(2), N$(2)
+20 FOR I = 0 TO 2
+30 A(I) = I * 2 : N$(I) = MID$("ABC", I + 1, 1)
+40 NEXT I
+50 PRINT A()
+60 PRINT N$(); "."`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 0             2             4", "ABC."}, output)
+}
+
+func TestIntegration_TwoDimensionalArray(t *testing.T) {
+	source := `10 DIM GRID(2, 3)
+20 FOR R = 0 TO 2
+30 FOR C = 0 TO 3
+40 GRID(R, C) = R * 10 + C
+50 NEXT C
+60 NEXT R
+70 FOR R = 0 TO 2
+80 PRINT GRID(R, 0), GRID(R, 1), GRID(R, 2), GRID(R, 3)
+90 NEXT R`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 0             1             2             3", " 10            11            12            13", " 20            21            22            23"}, output)
+	
+	executeAndExpectError(t, "10 DIM G(2, 3)\n20 PRINT G(1, 4)", "index 4 out of bounds in dimension 2 of array G(2,3)")
+}
+
+func TestIntegration_DataReadRestore(t *testing.T) {
+	source := `10 READ A, B, C$
+20 PRINT A, B, C$
+30 RESTORE 110
+40 READ N
+50 PRINT N
+60 RESTORE
+70 READ A
+80 PRINT A
+90 END
+100 DATA 1, -2.5, "three"
+110 DATA "4", 5`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 1            -2.5          three", " 4", " 1"}, output)
+	
+	executeAndExpectError(t, "10 READ A, B\n20 DATA 1", "out of DATA")
+	executeAndExpectError(t, "10 READ A\n20 DATA \"abc\"", "cannot READ DATA 'abc' into numeric variable A")
+}
+
+func TestIntegration_ReadArray(t *testing.T) {
+	source := `10 DIM A(5)
+20 READ A(), 3
+30 FOR I = 0 TO 3
+40 PRINT A(I)
+50 NEXT I
+60 DATA 10, 20, 30`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 10", " 20", " 30", " 0"}, output)
+	
+	executeAndExpectError(t, "10 DIM A(5)\n20 READ A(), 3\n30 DATA 1, 2", "out of DATA")
+	executeAndExpectError(t, "10 DIM A(1)\n20 READ A(), 3\n30 DATA 1, 2, 3", "subscript out of range")
+}
+
 func TestIntegration_SimpleForLoop(t *testing.T) {
 	source := `10 FOR I = 1 TO 5
 20 PRINT "Count:", I
@@ -227,11 +465,11 @@ func TestIntegration_SimpleForLoop(t *testing.T) {
 	require.NoError(t, err)
 	
 	expected := []string{
-		"Count: 1",
-		"Count: 2",
-		"Count: 3",
-		"Count: 4",
-		"Count: 5",
+		"Count:         1",
+		"Count:         2",
+		"Count:         3",
+		"Count:         4",
+		"Count:         5",
 		"Loop finished",
 	}
 	assert.Equal(t, expected, output)
@@ -249,16 +487,16 @@ func TestIntegration_ForLoopWithStep(t *testing.T) {
 	require.NoError(t, err)
 	
 	expected := []string{
-		"Even: 2",
-		"Even: 4",
-		"Even: 6",
-		"Even: 8",
-		"Even: 10",
-		"Countdown: 10",
-		"Countdown: 8",
-		"Countdown: 6",
-		"Countdown: 4",
-		"Countdown: 2",
+		"Even:          2",
+		"Even:          4",
+		"Even:          6",
+		"Even:          8",
+		"Even:          10",
+		"Countdown:     10",
+		"Countdown:     8",
+		"Countdown:     6",
+		"Countdown:     4",
+		"Countdown:     2",
 	}
 	assert.Equal(t, expected, output)
 }
@@ -275,12 +513,12 @@ func TestIntegration_NestedForLoops(t *testing.T) {
 	require.NoError(t, err)
 	
 	expected := []string{
-		"I = 1 J = 1",
-		"I = 1 J = 2",
-		"I = 2 J = 1",
-		"I = 2 J = 2",
-		"I = 3 J = 1",
-		"I = 3 J = 2",
+		"I =            1            J =            1",
+		"I =            1            J =            2",
+		"I =            2            J =            1",
+		"I =            2            J =            2",
+		"I =            3            J =            1",
+		"I =            3            J =            2",
 		"All loops finished",
 	}
 	assert.Equal(t, expected, output)
@@ -302,7 +540,7 @@ func TestIntegration_MathematicalFunctions(t *testing.T) {
 	// Check specific outputs (RND is random, so we just check it's present)
 	found := false
 	for _, line := range output {
-		if strings.Contains(line, "ABS(-15.7) = 15.7") {
+		if strings.Contains(line, "ABS(-15.7) =   15.7") {
 			found = true
 			break
 		}
@@ -311,7 +549,7 @@ func TestIntegration_MathematicalFunctions(t *testing.T) {
 	
 	found = false
 	for _, line := range output {
-		if strings.Contains(line, "INT(3.14159) = 3") {
+		if strings.Contains(line, "INT(3.14159) =               3") {
 			found = true
 			break
 		}
@@ -329,7 +567,7 @@ func TestIntegration_MathematicalFunctions(t *testing.T) {
 	
 	found = false
 	for _, line := range output {
-		if strings.Contains(line, "ABS(-5) + INT(7.8) = 12") {
+		if strings.Contains(line, "ABS(-5) + INT(7.8) =         12") {
 			found = true
 			break
 		}
@@ -356,9 +594,9 @@ func TestIntegration_ComplexExpressions(t *testing.T) {
 	// X = (2+3) * (4-2) / (3+1) = 5 * 2 / 4 = 2.5
 	// Y = 2^(3+1) - 4*(2+3) = 16 - 20 = -4
 	expected := []string{
-		"Complex expression result: 20.75",
-		"Another complex expression: 2.5",
-		"Third expression: -4",
+		"Complex expression result:   20.75",
+		"Another complex expression:  2.5",
+		"Third expression:           -4",
 	}
 	assert.Equal(t, expected, output)
 }
@@ -377,11 +615,11 @@ func TestIntegration_MixedControlFlow(t *testing.T) {
 	
 	// Check that all expected outputs are present
 	expectedContains := []string{
-		"Processing: 1",
-		"Processing: 2",
-		"Processing: 3",
-		"Processing: 4",
-		"Processing: 5",
+		"Processing:    1",
+		"Processing:    2",
+		"Processing:    3",
+		"Processing:    4",
+		"Processing:    5",
 		"Special case for 2",
 		"Special case for 3",
 		"Program end",
@@ -419,9 +657,9 @@ func TestIntegration_NestedControlStructures(t *testing.T) {
 	
 	// This tests nested loops with conditional statements
 	expectedContains := []string{
-		"Outer loop: 1",
-		"Outer loop: 2", 
-		"Outer loop: 3",
+		"Outer loop:    1",
+		"Outer loop:    2", 
+		"Outer loop:    3",
 		"First inner iteration",
 		"Second inner iteration",
 		"All done",
@@ -489,28 +727,28 @@ func TestIntegration_ComprehensiveProgram(t *testing.T) {
 	// Verify key outputs are present
 	expectedContains := []string{
 		"=== BASIC Language Feature Test ===",
-		"Variables: A = 10 B = 20",
-		"Strings: C$ = Hello D$ = World",
-		"Math: Sum = 30",
-		"Combined string: Hello World!",
-		"String length: 12",
-		"Substring: World",
-		"ABS(-15) = 15",
-		"INT(3.14159) = 3",
+		"Variables: A =               10           B =            20",
+		"Strings: C$ = Hello         D$ =          World",
+		"Math: Sum =    30",
+		"Combined string:            Hello World!",
+		"String length:               12",
+		"Substring:    World",
+		"ABS(-15) =     15",
+		"INT(3.14159) =               3",
 		"A is less than B (correct)",
 		"Sum calculation is correct",
 		"Counting from 1 to 3:",
-		" Count: 1",
-		" Count: 2", 
-		" Count: 3",
+		" Count:        1",
+		" Count:        2", 
+		" Count:        3",
 		"Multiplication table (2x2):",
-		"  1 x 1 = 1",
-		"  1 x 2 = 2",
-		"  2 x 1 = 2",
-		"  2 x 2 = 4",
+		"               1            x              1            =              1",
+		"               1            x              2            =              2",
+		"               2            x              1            =              2",
+		"               2            x              2            =              4",
 		"Complex expression result:",
 		"Number as string:",
-		"String as number: 42.5",
+		"String as number:            42.5",
 		"=== Test Complete ===",
 	}
 	
@@ -584,7 +822,7 @@ func TestIntegration_RuntimeErrors(t *testing.T) {
 		{
 			name: "GOTO to non-existent line",
 			source: `10 GOTO 999`,
-			errorContains: "program reference is nil",
+			errorContains: "line 10 jumps to line 999, which does not exist",
 		},
 		{
 			name: "invalid function call",
@@ -614,10 +852,10 @@ func TestIntegration_LogicErrors(t *testing.T) {
 		errorContains string
 	}{
 		{
-			name: "GOTO with nil program reference",
+			name: "GOTO to a deleted line",
 			source: `10 PRINT "Test"
-20 GOTO 10`,
-			errorContains: "program reference is nil",
+20 GOTO 15`,
+			errorContains: "line 20 jumps to line 15, which does not exist",
 		},
 		{
 			name: "nested FOR loops with wrong NEXT order",
@@ -693,7 +931,7 @@ func TestIntegration_PerformanceLimits(t *testing.T) {
 		require.NoError(t, err)
 		found := false
 		for _, line := range output {
-			if strings.Contains(line, "Total iterations: 1000") {
+			if strings.Contains(line, "Total iterations:            1000") {
 				found = true
 				break
 			}
@@ -727,14 +965,22 @@ func TestIntegration_PerformanceLimits(t *testing.T) {
 	})
 	
 	t.Run("execution step limit protection", func(t *testing.T) {
-		// This should hit the GOTO program reference error (since GOTO needs program reference)
 		source := `10 I = 0
 20 I = I + 1
 30 GOTO 20`
 		
-		_, err := executeProgram(t, source, false)
+		program, err := parser.NewParser(lexer.NewLexer(source)).ParseProgram()
+		require.NoError(t, err)
+		
+		limited := interpreter.NewInterpreter(interpreter.InterpreterConfig{MaxSteps: 1000})
+		err = limited.Execute(program, runtime.NewEnvironment())
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "program reference is nil")
+		assert.Contains(t, err.Error(), "execution exceeded 1000 steps (possible infinite loop)")
+		
+		// Without a configured limit the default one still ends a tight loop
+		err = cli.NewFileExecutor(cli.NewMemoryInputReader(nil), &MockOutputWriter{}).ExecuteSource("10 GOTO 10", false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("execution exceeded %d steps", interpreter.DefaultMaxSteps))
 	})
 }
 
@@ -753,7 +999,7 @@ func TestIntegration_FileExecution(t *testing.T) {
 			},
 			{
 				filename: "countdown.bas", 
-				contains: []string{"Countdown: 5", "Countdown: 1", "Blast off!"},
+				contains: []string{"Countdown: 5", "Countdown:     1", "Blast off!"},
 			},
 			{
 				filename: "simple_loop.bas",
@@ -854,7 +1100,7 @@ func TestIntegration_InteractiveMode(t *testing.T) {
 			"30 B = 20",
 			"40 PRINT \"Sum:\", A + B",
 			"Interactive test",
-			"Sum: 30",
+			"Sum:           30",
 			"Program cleared",
 		}
 		
@@ -939,6 +1185,22 @@ func TestIntegration_CLIEndToEnd(t *testing.T) {
 		assert.True(t, interactiveConfig.Interactive)
 	})
 	
+	t.Run("preset variables change the program output", func(t *testing.T) {
+		tmpFile := createTempBasicFile(t, `10 FOR I = 1 TO N
+20 PRINT GREETING$, I
+30 NEXT I`)
+		defer removeTempFile(t, tmpFile)
+		
+		config, err := cli.NewCLI().ParseArgs([]string{"basic-interpreter", "-D", "N=2", "-D", "GREETING$=Hi", tmpFile})
+		require.NoError(t, err)
+		
+		output := &MockOutputWriter{}
+		executorConfig := cli.ExecutorConfig{Variables: config.Variables}
+		fileExecutor := cli.NewFileExecutorWithConfig(cli.NewMemoryInputReader(nil), output, executorConfig)
+		require.NoError(t, fileExecutor.ExecuteFile(config.InputFile, false))
+		assert.Equal(t, []string{"Hi             1", "Hi             2"}, output.Lines)
+	})
+	
 	t.Run("help and version information", func(t *testing.T) {
 		cliInstance := cli.NewCLI()
 		
@@ -969,7 +1231,7 @@ func TestIntegration_StringAndNumericMixing(t *testing.T) {
 	output := executeAndExpectSuccess(t, source)
 	assertOutputContains(t, output, []string{
 		"Number: 5",
-		"Result: 128",
+		"Result:        128",
 	})
 }
 
@@ -983,14 +1245,22 @@ func TestIntegration_ComplexForLoopConditions(t *testing.T) {
 	
 	output := executeAndExpectSuccess(t, source)
 	assertOutputContains(t, output, []string{
-		"Countdown: 10",
-		"Countdown: 7",
-		"Countdown: 4",
-		"Countdown: 1",
-		"Single iteration: 0",
+		"Countdown:     10",
+		"Countdown:     7",
+		"Countdown:     4",
+		"Countdown:     1",
+		"Single iteration:            0",
 	})
 }
 
+func TestIntegration_IntRoundsDown(t *testing.T) {
+	source := `10 PRINT INT(2.5), INT(-2.5)
+20 PRINT FIX(2.5), FIX(-2.5)`
+	
+	output := executeAndExpectSuccess(t, source)
+	assert.Equal(t, []string{" 2            -3", " 2            -2"}, output)
+}
+
 func TestIntegration_NestedFunctionCalls(t *testing.T) {
 	source := `10 A = ABS(INT(-5.7))
 20 PRINT "ABS(INT(-5.7)) =", A
@@ -1002,9 +1272,9 @@ func TestIntegration_NestedFunctionCalls(t *testing.T) {
 	
 	output := executeAndExpectSuccess(t, source)
 	assertOutputContains(t, output, []string{
-		"ABS(INT(-5.7)) = 5",
-		"MID$(STR$(123), 2, 1) = 2",
-		"VAL(MID$(S$, 1, 3)) = 456",
+		"ABS(INT(-5.7)) =             6",
+		"MID$(STR$(123), 2, 1) =     2",
+		"VAL(MID$(S$, 1, 3)) =        456",
 	})
 }
 
@@ -1020,9 +1290,9 @@ func TestIntegration_VariableNameCaseSensitivity(t *testing.T) {
 	output := executeAndExpectSuccess(t, source)
 	// All should refer to the same variable, so final value should be 15
 	assertOutputContains(t, output, []string{
-		"abc = 10",  // After line 20
-		"ABC = 10",  // Same variable
-		"Abc = 15",  // Final value
+		"abc =          10",  // After line 20
+		"ABC =          10",  // Same variable
+		"Abc =          15",  // Final value
 	})
 }
 
@@ -1036,15 +1306,22 @@ func TestIntegration_EdgeCases(t *testing.T) {
 	})
 	
 	t.Run("comments only", func(t *testing.T) {
-		// REM statements aren't supported by the current parser
-		// This test demonstrates that REM statements cause parsing errors
 		source := `10 REM This is a comment
 20 REM Another comment
 30 REM End`
 		
-		_, err := executeProgram(t, source, false)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "expected assignment operator")
+		output, err := executeProgram(t, source, false)
+		require.NoError(t, err)
+		assert.Empty(t, output)
+	})
+	
+	t.Run("comment text is not tokenized", func(t *testing.T) {
+		source := `10 REM it's 100% "unbalanced & odd
+20 X = 1
+30 PRINT X`
+		
+		output := executeAndExpectSuccess(t, source)
+		assert.Equal(t, []string{" 1"}, output, "the line after a REM is not part of the comment")
 	})
 	
 	t.Run("single statement", func(t *testing.T) {
@@ -1084,7 +1361,7 @@ func TestIntegration_EdgeCases(t *testing.T) {
 		
 		output, err := executeProgram(t, source, false)
 		require.NoError(t, err)
-		assert.Contains(t, output, "5 string 10 test")
+		assert.Contains(t, output, " 5            string         10           test")
 	})
 	
 	t.Run("extreme numeric values", func(t *testing.T) {
@@ -1156,9 +1433,9 @@ func TestIntegration_CompletePipeline(t *testing.T) {
 		// Verify the complete execution flow
 		expected := []string{
 			"Pipeline Test",
-			"I = 1 B = 2",
-			"I = 2 B = 4", 
-			"I = 3 B = 6",
+			"I =            1            B =            2",
+			"I =            2            B =            4", 
+			"I =            3            B =            6",
 			"Pipeline Complete",
 		}
 		assert.Equal(t, expected, output)
@@ -3,8 +3,15 @@ package interpreter
 import (
 	"basic-interpreter/internal/ast"
 	"basic-interpreter/internal/runtime"
+	"context"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // OutputWriter interface for debug output
@@ -13,31 +20,102 @@ type OutputWriter interface {
 }
 
 // Interpreter represents the BASIC interpreter
+//
+// Concurrency: an Interpreter runs one program at a time and must not be used from several
+// goroutines at once. The same goes for the Environment it runs, which is updated during the
+// run, and for the statements' input and output. A parsed Program is not changed by running
+// it, but its PRINT and INPUT statements hold their I/O. Separate interpreters may run in
+// parallel as long as each has its own Program, Environment and I/O; each also has its own
+// built-in function registry, so they share no mutable state.
 type Interpreter struct {
 	debugMode    bool
 	debugOutput  OutputWriter
+	traceMode    bool
+	traceOutput  OutputWriter
 	maxSteps     int
 	stepCount    int
+	disabledFeatures []string
+	ctx          context.Context
+	functions    *ast.FunctionRegistry
 }
 
 // InterpreterConfig holds configuration options for the interpreter
 type InterpreterConfig struct {
 	DebugMode   bool
 	DebugOutput OutputWriter
-	MaxSteps    int // -1 for no limit
+	MaxSteps    int // 0 for DefaultMaxSteps, -1 for no limit
+	
+	// TraceMode writes the number of each line after it runs to TraceOutput, followed by
+	// the variables and array elements the line changed and their new values
+	TraceMode   bool
+	TraceOutput OutputWriter
+	
+	// DisabledFeatures lists statements and functions (e.g. "INPUT", "PAUSE", "RND")
+	// that fail with runtime.ErrFeatureDisabled, so hosts can sandbox untrusted programs.
+	// Only INPUT, PAUSE, SHELL and built-in function names are accepted; Execute fails on others
+	DisabledFeatures []string
+	
+	// Context stops the run before the next statement when it is cancelled, for example
+	// on Ctrl-C; the run then fails with an *InterruptedError. Nil means never cancelled
+	Context context.Context
+	
+	// Functions holds the built-in functions programs may call; nil gives the interpreter
+	// its own registry with the standard libraries
+	Functions *ast.FunctionRegistry
+}
+
+// InterruptedError is returned when a run is stopped by cancelling its context
+type InterruptedError struct {
+	Line int // Line that would have run next
+}
+
+// Error returns the message shown to the user when a run is interrupted
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("Interrupted at line %d", e.Line)
+}
+
+// RuntimeError is returned when a statement fails while the program runs
+type RuntimeError struct {
+	Line int // Line of the failing statement
+	Err  error
+}
+
+// Error describes the failing line followed by the reason
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("runtime error at line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the reason the statement failed
+func (e *RuntimeError) Unwrap() error {
+	return e.Err
 }
 
+// DefaultMaxSteps is the number of statements a run may execute before it is stopped as a
+// likely infinite loop, unless the configuration sets another limit
+const DefaultMaxSteps = 10_000_000
+
 // NewInterpreter creates a new interpreter instance with the given configuration
 func NewInterpreter(config InterpreterConfig) *Interpreter {
 	if config.MaxSteps == 0 {
-		config.MaxSteps = -1 // Default to no limit
+		config.MaxSteps = DefaultMaxSteps
+	}
+	if config.Context == nil {
+		config.Context = context.Background()
+	}
+	if config.Functions == nil {
+		config.Functions = ast.NewFunctionRegistry()
 	}
 	
 	return &Interpreter{
 		debugMode:   config.DebugMode,
 		debugOutput: config.DebugOutput,
+		traceMode:   config.TraceMode,
+		traceOutput: config.TraceOutput,
 		maxSteps:    config.MaxSteps,
 		stepCount:   0,
+		disabledFeatures: config.DisabledFeatures,
+		ctx:         config.Context,
+		functions:   config.Functions,
 	}
 }
 
@@ -45,34 +123,108 @@ func NewInterpreter(config InterpreterConfig) *Interpreter {
 func NewBasicInterpreter(debugMode bool) *Interpreter {
 	return NewInterpreter(InterpreterConfig{
 		DebugMode: debugMode,
-		MaxSteps:  -1,
 	})
 }
 
+// RunResult describes how a program run ended, for hosts embedding the interpreter
+type RunResult struct {
+	ExitCode int           // Exit code given by END n; 0 otherwise
+	Steps    int           // Number of statements executed
+	Elapsed  time.Duration // Wall-clock duration of the run
+	Err      error         // Error that stopped the program, nil on normal termination
+}
+
+// Functions returns the interpreter's built-in function registry
+// Programs for this interpreter are parsed with it, by parser.NewParserWithFunctions
+func (i *Interpreter) Functions() *ast.FunctionRegistry {
+	return i.functions
+}
+
+// RegisterLibrary adds a library of built-in functions to this interpreter only
+func (i *Interpreter) RegisterLibrary(library *ast.FunctionLibrary) error {
+	return i.functions.RegisterLibrary(library)
+}
+
 // GetStepCount returns the number of execution steps performed
 func (i *Interpreter) GetStepCount() int {
 	return i.stepCount
 }
 
+// Run executes a BASIC program and reports how the run ended
+func (i *Interpreter) Run(program *ast.Program, env *runtime.Environment) RunResult {
+	start := time.Now()
+	err := i.Execute(program, env)
+	return RunResult{
+		ExitCode: env.ExitCode,
+		Steps:    i.stepCount,
+		Elapsed:  time.Since(start),
+		Err:      err,
+	}
+}
+
 // Execute executes a BASIC program
 func (i *Interpreter) Execute(program *ast.Program, env *runtime.Environment) error {
+	return i.ExecuteRange(program, env, math.MinInt, math.MaxInt)
+}
+
+// ExecuteRange executes only the lines from start to end, inclusive
+// Execution begins at the first line at or after start and stops as soon as
+// control reaches a line outside the range, so a subroutine can be tried in isolation
+func (i *Interpreter) ExecuteRange(program *ast.Program, env *runtime.Environment, start, end int) error {
 	if program == nil {
 		return nil
 	}
 
-	// Reset step counter
+	// Reset step counter, any halt, and any open subroutine calls and loops left by a previous run
 	i.stepCount = 0
+	env.Halted = false
+	env.Stopped = false
+	env.ExitCode = 0
+	env.CallStack = env.CallStack[:0]
+	env.WhileLoops = env.WhileLoops[:0]
+	env.DoLoops = env.DoLoops[:0]
+	
+	// Gather the DATA values up front, so READ finds them wherever the DATA lines are
+	ast.LoadData(program, env)
+	
+	// Only statements that check for it and built-in functions can be disabled;
+	// reject anything else rather than silently sandbox nothing
+	for _, feature := range i.disabledFeatures {
+		if !ast.IsDisableableStatement(feature) && i.functions.Lookup(feature) == nil {
+			return fmt.Errorf("cannot disable %s: not a statement or function that can be disabled", strings.ToUpper(feature))
+		}
+		env.DisableFeature(feature)
+	}
+
+	// Index lines once so jumps find their target without scanning Order
+	lineIndex := buildLineIndex(program)
 
-	// Start execution from the first line
+	// Start execution from the first line in range
 	currentIndex := 0
+	for currentIndex < len(program.Order) && program.Order[currentIndex] < start {
+		currentIndex++
+	}
+	
+	// Statement of the line that execution resumes at, after a jump into the middle of it
+	resumeStatement := 0
 
 	for currentIndex < len(program.Order) {
+		lineNumber := program.Order[currentIndex]
+
 		// Check execution step limit
 		if err := i.checkStepLimit(); err != nil {
-			return err
+			return &RuntimeError{Line: lineNumber, Err: err}
+		}
+		
+		// Stop when control leaves the range
+		if lineNumber < start || lineNumber > end {
+			break
+		}
+		
+		// Stop cleanly between statements when the host cancels the run
+		if i.ctx.Err() != nil {
+			return &InterruptedError{Line: lineNumber}
 		}
-
-		lineNumber := program.Order[currentIndex]
 		
 		// Set current program counter
 		env.ProgramCounter = lineNumber
@@ -84,67 +236,84 @@ func (i *Interpreter) Execute(program *ast.Program, env *runtime.Environment) er
 			continue // Skip missing lines
 		}
 
+		// Resuming after the last statement of a line, as NEXT does when the FOR ends its line,
+		// goes straight on with the next line
+		if resumeStatement >= ast.StatementCount(statement) {
+			currentIndex++
+			resumeStatement = 0
+			continue
+		}
+
 		// Debug output: show line before execution
 		i.outputDebugMessage(lineNumber, statement)
 
 		// Increment step counter
 		i.stepCount++
 
-		// Store original program counter to detect changes
-		originalPC := env.ProgramCounter
+		// Keep the variables and arrays as they were, so the trace can show what the line changed
+		var before traceSnapshot
+		if i.tracing() {
+			before = takeTraceSnapshot(env)
+		}
 
-		// Execute the statement
-		err := statement.Execute(env)
+		// Execute the line's statements, from the one control resumed at
+		err := ast.ExecuteLine(statement, env, resumeStatement)
 		if err != nil {
 			// Wrap error with line number information
-			return fmt.Errorf("runtime error at line %d: %w", lineNumber, err)
+			return &RuntimeError{Line: lineNumber, Err: err}
+		}
+		
+		if i.tracing() {
+			i.traceOutput.WriteLine(formatTraceLine(lineNumber, before, env))
+		}
+		
+		// END and STOP stop the program
+		if env.Halted {
+			if env.Stopped && i.debugMode && i.debugOutput != nil {
+				i.debugOutput.WriteLine(fmt.Sprintf("STOPPED at line %d", lineNumber))
+			}
+			break
 		}
 
-		// Handle program counter changes and determine next execution position
-		nextIndex, shouldBreak := i.handleProgramCounterChange(program, statement, lineNumber, originalPC, currentIndex, env)
-		if shouldBreak {
+		// Follow any jump, or go on with the next line
+		if !env.Jumped {
+			currentIndex++
+			resumeStatement = 0
+			continue
+		}
+		nextIndex := i.findNextLineIndex(lineIndex, env.JumpTarget.Line)
+		if nextIndex == -1 {
+			// Control went to a line that does not exist: an implicit END
+			env.Halt(0)
 			break
 		}
 		currentIndex = nextIndex
+		resumeStatement = env.JumpTarget.Statement
+	}
+	
+	// Running off the last line is an implicit END, so the run halts just as if END were there
+	if currentIndex >= len(program.Order) {
+		env.Halt(0)
 	}
 
 	return nil
 }
 
-// findNextLineIndex finds the index in program.Order for the given line number
-func (i *Interpreter) findNextLineIndex(program *ast.Program, lineNumber int) int {
+// buildLineIndex maps each line number to its position in program.Order
+func buildLineIndex(program *ast.Program) map[int]int {
+	lineIndex := make(map[int]int, len(program.Order))
 	for idx, line := range program.Order {
-		if line == lineNumber {
-			return idx
-		}
+		lineIndex[line] = idx
 	}
-	return -1
+	return lineIndex
 }
 
-// isForStatement checks if the statement at the given line number is a FOR statement
-func (i *Interpreter) isForStatement(program *ast.Program, lineNumber int) bool {
-	statement, exists := program.Lines[lineNumber]
-	if !exists {
-		return false
+// findNextLineIndex finds the index in program.Order for the given line number, or -1
+func (i *Interpreter) findNextLineIndex(lineIndex map[int]int, lineNumber int) int {
+	if idx, exists := lineIndex[lineNumber]; exists {
+		return idx
 	}
-	_, isFor := statement.(*ast.ForStatement)
-	return isFor
-}
-
-// isComingFromNext checks if we're coming from a NEXT statement
-func (i *Interpreter) isComingFromNext(program *ast.Program, previousLineNumber int) bool {
-	statement, exists := program.Lines[previousLineNumber]
-	if !exists {
-		return false
-	}
-	_, isNext := statement.(*ast.NextStatement)
-	return isNext
-}
-
-// isGotoStatement checks if a statement is a GOTO statement
-func (i *Interpreter) isGotoStatement(statement ast.Statement) bool {
-	_, isGoto := statement.(*ast.GotoStatement)
-	return isGoto
+	return -1
 }
 
 // outputDebugMessage outputs debug information if debug mode is enabled
@@ -155,44 +324,87 @@ func (i *Interpreter) outputDebugMessage(lineNumber int, statement ast.Statement
 	}
 }
 
-// checkStepLimit checks if the execution step limit has been exceeded
-func (i *Interpreter) checkStepLimit() error {
-	if i.maxSteps > 0 && i.stepCount >= i.maxSteps {
-		return fmt.Errorf("execution limit exceeded: maximum %d steps reached", i.maxSteps)
+// tracing reports whether each executed line should be traced
+func (i *Interpreter) tracing() bool {
+	return i.traceMode && i.traceOutput != nil
+}
+
+// traceSnapshot holds the variables and array elements as they were before a line ran
+type traceSnapshot struct {
+	variables map[string]runtime.Value
+	arrays    map[string][]runtime.Value
+}
+
+// takeTraceSnapshot copies the variables and the elements of every array
+func takeTraceSnapshot(env *runtime.Environment) traceSnapshot {
+	arrays := make(map[string][]runtime.Value, len(env.Arrays))
+	for name, array := range env.Arrays {
+		arrays[name] = slices.Clone(array.Elements)
 	}
-	return nil
+	return traceSnapshot{variables: maps.Clone(env.Variables), arrays: arrays}
 }
 
-// handleProgramCounterChange handles program counter modifications and returns the next execution index
-func (i *Interpreter) handleProgramCounterChange(program *ast.Program, statement ast.Statement, lineNumber int, originalPC int, currentIndex int, env *runtime.Environment) (int, bool) {
-	// Check if program counter was modified by control flow statements
-	if !i.hasProgramCounterChanged(originalPC, lineNumber, statement, env) {
-		return currentIndex + 1, false // Normal sequential execution
+// formatTraceLine describes an executed line as its number in brackets followed by the
+// variables that differ from before, sorted by name, and then the array elements that do,
+// by array name and index: [20] N = 3, NAME$ = "Ada", A(2) = 7
+func formatTraceLine(lineNumber int, before traceSnapshot, env *runtime.Environment) string {
+	var changed []string
+	for name, value := range env.Variables {
+		if previous, existed := before.variables[name]; !existed || previous != value {
+			changed = append(changed, name)
+		}
 	}
+	sort.Strings(changed)
 	
-	// Find the next line to execute based on the new program counter
-	nextIndex := i.findNextLineIndex(program, env.ProgramCounter)
-	if nextIndex == -1 {
-		return currentIndex, true // Program counter points to non-existent line, end execution
+	line := fmt.Sprintf("[%d]", lineNumber)
+	separator := " "
+	describe := func(name string, value runtime.Value) {
+		text := value.Format(env.NumberFormat)
+		if value.Type == runtime.StringValue {
+			text = "\"" + text + "\""
+		}
+		line += fmt.Sprintf("%s%s = %s", separator, name, text)
+		separator = ", "
 	}
-	
-	// Handle special case for FOR-NEXT loops
-	if i.isForStatement(program, env.ProgramCounter) && i.isComingFromNext(program, lineNumber) {
-		return nextIndex + 1, false // Continue from the line AFTER the FOR statement
+	for _, name := range changed {
+		describe(name, env.Variables[name])
 	}
 	
-	return nextIndex, false // Continue execution from the new position (GOTO case)
+	for _, name := range slices.Sorted(maps.Keys(env.Arrays)) {
+		array := env.Arrays[name]
+		previous := before.arrays[name]
+		for offset, value := range array.Elements {
+			// Elements of an array declared by the line are compared with their initial value
+			initial := runtime.NewNumericValue(0)
+			if strings.HasSuffix(name, "$") {
+				initial = runtime.NewStringValue("")
+			}
+			if offset < len(previous) {
+				initial = previous[offset]
+			}
+			if value != initial {
+				describe(arrayElementName(name, array, offset), value)
+			}
+		}
+	}
+	return line
 }
 
-// hasProgramCounterChanged checks if the program counter was modified by a control flow statement
-func (i *Interpreter) hasProgramCounterChanged(originalPC, currentLine int, statement ast.Statement, env *runtime.Environment) bool {
-	// GOTO statements always change program counter (even to same line)
-	if i.isGotoStatement(statement) {
-		return true
+// arrayElementName writes the element at an offset in an array's elements as NAME(i,j)
+func arrayElementName(name string, array *runtime.Array, offset int) string {
+	indexes := make([]string, len(array.Sizes))
+	for d, stride := range array.Strides {
+		indexes[d] = strconv.Itoa(offset / stride % (array.Sizes[d] + 1))
 	}
-	
-	// Other statements change PC if it differs from current line
-	return env.ProgramCounter != currentLine
+	return name + "(" + strings.Join(indexes, ",") + ")"
+}
+
+// checkStepLimit checks if the execution step limit has been exceeded
+func (i *Interpreter) checkStepLimit() error {
+	if i.maxSteps > 0 && i.stepCount >= i.maxSteps {
+		return fmt.Errorf("execution exceeded %d steps (possible infinite loop)", i.maxSteps)
+	}
+	return nil
 }
 
 // formatDebugMessage formats a debug message for a statement
@@ -203,9 +415,20 @@ func (i *Interpreter) formatDebugMessage(lineNumber int, statement ast.Statement
 	case *ast.PrintStatement:
 		return fmt.Sprintf("Executing line %d: PRINT %s", lineNumber, i.formatExpressionList(stmt.Expressions))
 	case *ast.InputStatement:
+		if stmt.Line {
+			return fmt.Sprintf("Executing line %d: LINE INPUT %s", lineNumber, stmt.Variable)
+		}
 		return fmt.Sprintf("Executing line %d: INPUT %s", lineNumber, stmt.Variable)
+	case *ast.DefFnStatement:
+		return fmt.Sprintf("Executing line %d: DEF %s(%s) = %s", lineNumber, stmt.Name, stmt.Parameter, i.formatExpression(stmt.Body))
 	case *ast.GotoStatement:
 		return fmt.Sprintf("Executing line %d: GOTO %d", lineNumber, stmt.LineNumber)
+	case *ast.GosubStatement:
+		return fmt.Sprintf("Executing line %d: GOSUB %d", lineNumber, stmt.LineNumber)
+	case *ast.ReturnStatement:
+		return fmt.Sprintf("Executing line %d: RETURN", lineNumber)
+	case *ast.StopStatement:
+		return fmt.Sprintf("Executing line %d: STOP", lineNumber)
 	case *ast.IfStatement:
 		return fmt.Sprintf("Executing line %d: IF %s THEN ...", lineNumber, i.formatExpression(stmt.Condition))
 	case *ast.ForStatement:
@@ -213,6 +436,14 @@ func (i *Interpreter) formatDebugMessage(lineNumber int, statement ast.Statement
 			i.formatExpression(stmt.StartExpr), i.formatExpression(stmt.EndExpr), i.formatExpression(stmt.StepExpr))
 	case *ast.NextStatement:
 		return fmt.Sprintf("Executing line %d: NEXT %s", lineNumber, stmt.Variable)
+	case *ast.WhileStatement:
+		return fmt.Sprintf("Executing line %d: WHILE %s", lineNumber, i.formatExpression(stmt.Condition))
+	case *ast.WendStatement:
+		return fmt.Sprintf("Executing line %d: WEND", lineNumber)
+	case *ast.DoStatement:
+		return fmt.Sprintf("Executing line %d: DO", lineNumber)
+	case *ast.LoopStatement:
+		return fmt.Sprintf("Executing line %d: LOOP", lineNumber)
 	default:
 		return fmt.Sprintf("Executing line %d", lineNumber)
 	}
@@ -0,0 +1,63 @@
+package interpreter
+
+import (
+	"basic-interpreter/internal/ast"
+	"basic-interpreter/internal/lexer"
+	"basic-interpreter/internal/parser"
+	"basic-interpreter/internal/runtime"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// parseBenchmarkProgram parses source once so benchmarks measure execution only
+func parseBenchmarkProgram(b *testing.B, source string) *ast.Program {
+	b.Helper()
+	program, err := parser.NewParser(lexer.NewLexer(source)).ParseProgram()
+	if err != nil {
+		b.Fatalf("parse error: %v", err)
+	}
+	return program
+}
+
+// runBenchmarkProgram executes the program b.N times, each in a fresh environment
+func runBenchmarkProgram(b *testing.B, program *ast.Program) {
+	b.Helper()
+	interpreter := NewBasicInterpreter(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := interpreter.Execute(program, runtime.NewEnvironment()); err != nil {
+			b.Fatalf("runtime error: %v", err)
+		}
+	}
+}
+
+func BenchmarkStringConcatenationLoop(b *testing.B) {
+	program := parseBenchmarkProgram(b, `10 S$ = ""
+20 FOR I = 1 TO 1000
+30 S$ = S$ + "x"
+40 NEXT I`)
+	runBenchmarkProgram(b, program)
+}
+
+func BenchmarkMidInLoop(b *testing.B) {
+	program := parseBenchmarkProgram(b, `10 S$ = "THE QUICK BROWN FOX"
+20 FOR I = 1 TO 1000
+30 C$ = MID$(S$, 5, 5)
+40 NEXT I`)
+	runBenchmarkProgram(b, program)
+}
+
+func BenchmarkGotoLoop(b *testing.B) {
+	// The loop sits after many lines, so finding a jump target by scanning Order would be slow
+	var source strings.Builder
+	for line := 10; line <= 5000; line += 10 {
+		fmt.Fprintf(&source, "%d P = 0\n", line)
+	}
+	source.WriteString(`6000 I = 0
+6010 I = I + 1
+6020 IF I < 1000 THEN GOTO 6010`)
+	program := parseBenchmarkProgram(b, source.String())
+	runBenchmarkProgram(b, program)
+}
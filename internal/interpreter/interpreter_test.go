@@ -2,10 +2,17 @@ package interpreter
 
 import (
 	"basic-interpreter/internal/ast"
+	"basic-interpreter/internal/lexer"
+	"basic-interpreter/internal/parser"
 	"basic-interpreter/internal/runtime"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockOutputWriter for testing output operations
@@ -185,7 +192,92 @@ func TestInterpreter_Execute_ForNextLoop(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Verify loop executed correctly
-	assert.Equal(t, []string{"1", "2", "3"}, output.Lines)
+	assert.Equal(t, []string{" 1", " 2", " 3"}, output.Lines)
+}
+
+// Test loops and jumps that start or end in the middle of a colon-separated line
+func TestInterpreter_Execute_StatementsOnOneLine(t *testing.T) {
+	number := func(value float64) ast.Expression { return ast.NewLiteralExpression(runtime.NewNumericValue(value)) }
+	forI := func(line int) ast.Statement { return ast.NewForStatement("I", number(1), number(3), number(1), line) }
+	compound := func(statements ...ast.Statement) ast.Statement { return ast.NewCompoundStatement(statements) }
+
+	t.Run("FOR and NEXT on the same line", func(t *testing.T) {
+		// 10 FOR I = 1 TO 3 : PRINT I : NEXT I
+		output := &MockOutputWriter{}
+		printI := ast.NewPrintStatement([]ast.Expression{ast.NewVariableExpression("I")}, output)
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{10: compound(forI(10), printI, ast.NewNextStatement("I"))},
+			Order: []int{10},
+		}
+
+		err := NewBasicInterpreter(false).Execute(program, runtime.NewEnvironment())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{" 1", " 2", " 3"}, output.Lines)
+	})
+
+	t.Run("FOR in the middle of a line and NEXT on another", func(t *testing.T) {
+		// 10 PRINT "x" : FOR I = 1 TO 3 : PRINT I
+		// 20 NEXT I
+		output := &MockOutputWriter{}
+		printX := ast.NewPrintStatement([]ast.Expression{ast.NewLiteralExpression(runtime.NewStringValue("x"))}, output)
+		printI := ast.NewPrintStatement([]ast.Expression{ast.NewVariableExpression("I")}, output)
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: compound(printX, forI(10), printI),
+				20: ast.NewNextStatement("I"),
+			},
+			Order: []int{10, 20},
+		}
+		interpreter := NewBasicInterpreter(false)
+
+		err := interpreter.Execute(program, runtime.NewEnvironment())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"x", " 1", " 2", " 3"}, output.Lines, "each pass starts after the FOR")
+		assert.Equal(t, 6, interpreter.GetStepCount())
+	})
+
+	t.Run("FOR in an IF branch", func(t *testing.T) {
+		// 10 IF 1 THEN FOR I = 1 TO 3
+		// 20 PRINT I
+		// 30 NEXT I
+		output := &MockOutputWriter{}
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: ast.NewIfStatement(number(1), forI(10)),
+				20: ast.NewPrintStatement([]ast.Expression{ast.NewVariableExpression("I")}, output),
+				30: ast.NewNextStatement("I"),
+			},
+			Order: []int{10, 20, 30},
+		}
+
+		err := NewBasicInterpreter(false).Execute(program, runtime.NewEnvironment())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{" 1", " 2", " 3"}, output.Lines)
+	})
+
+	t.Run("GOTO its own line from an IF", func(t *testing.T) {
+		// 10 X = X + 1 : IF X < 3 THEN GOTO 10
+		// 20 PRINT X
+		output := &MockOutputWriter{}
+		increment := ast.NewAssignmentStatement("X", ast.NewBinaryExpression(ast.NewVariableExpression("X"), ast.OpAdd, number(1)))
+		gotoTen := ast.NewGotoStatement(10, nil)
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: compound(increment, ast.NewIfStatement(ast.NewComparisonExpression(ast.NewVariableExpression("X"), "<", number(3)), gotoTen)),
+				20: ast.NewPrintStatement([]ast.Expression{ast.NewVariableExpression("X")}, output),
+			},
+			Order: []int{10, 20},
+		}
+		gotoTen.Program = program
+
+		err := NewBasicInterpreter(false).Execute(program, runtime.NewEnvironment())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{" 3"}, output.Lines)
+	})
 }
 
 // Test empty program execution
@@ -278,7 +370,7 @@ func TestInterpreter_Execute_NestedForLoops(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Verify nested loops executed correctly
-	expected := []string{"1 , 1", "1 , 2", "2 , 1", "2 , 2"}
+	expected := []string{" 1            ,              1", " 1            ,              2", " 2            ,              1", " 2            ,              2"}
 	assert.Equal(t, expected, output.Lines)
 }
 
@@ -333,7 +425,79 @@ func TestInterpreter_Execute_DebugMode(t *testing.T) {
 	assert.Contains(t, debugOutput.Lines[1], "Executing line 20")
 	
 	// Verify normal output still works
-	assert.Equal(t, []string{"5"}, output.Lines)
+	assert.Equal(t, []string{" 5"}, output.Lines)
+}
+
+// Test that STOP ends the run and is reported in debug mode
+func TestInterpreter_Execute_Stop(t *testing.T) {
+	output := &MockOutputWriter{}
+	debugOutput := &MockOutputWriter{}
+	
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10: ast.NewPrintStatement([]ast.Expression{ast.NewLiteralExpression(runtime.NewStringValue("before"))}, output),
+			20: ast.NewStopStatement(),
+			30: ast.NewPrintStatement([]ast.Expression{ast.NewLiteralExpression(runtime.NewStringValue("after"))}, output),
+		},
+		Order: []int{10, 20, 30},
+	}
+	
+	env := runtime.NewEnvironment()
+	interpreter := NewInterpreter(InterpreterConfig{DebugMode: true, DebugOutput: debugOutput})
+	
+	result := interpreter.Run(program, env)
+	assert.NoError(t, result.Err)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.True(t, env.Stopped)
+	assert.Equal(t, []string{"before"}, output.Lines, "lines after STOP do not run")
+	assert.Equal(t, "STOPPED at line 20", debugOutput.Lines[len(debugOutput.Lines)-1])
+	
+	// Without debug mode STOP ends the run silently
+	output.Lines = nil
+	assert.NoError(t, NewBasicInterpreter(false).Execute(program, runtime.NewEnvironment()))
+	assert.Equal(t, []string{"before"}, output.Lines)
+}
+
+func TestInterpreter_Execute_TraceMode(t *testing.T) {
+	traceOutput := &MockOutputWriter{}
+	
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10: ast.NewAssignmentStatement("N", ast.NewLiteralExpression(runtime.NewNumericValue(1))),
+			20: ast.NewAssignmentStatement("N", ast.NewLiteralExpression(runtime.NewNumericValue(1))),
+			30: ast.NewAssignmentStatement("A$", ast.NewLiteralExpression(runtime.NewStringValue("Ada"))),
+		},
+		Order: []int{10, 20, 30},
+	}
+	
+	interpreter := NewInterpreter(InterpreterConfig{TraceMode: true, TraceOutput: traceOutput})
+	
+	err := interpreter.Execute(program, runtime.NewEnvironment())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[10] N = 1", "[20]", `[30] A$ = "Ada"`}, traceOutput.Lines,
+		"assigning a variable the value it already has is not a change")
+}
+
+func TestInterpreter_Execute_TraceModeArrays(t *testing.T) {
+	traceOutput := &MockOutputWriter{}
+	number := func(value float64) ast.Expression { return ast.NewLiteralExpression(runtime.NewNumericValue(value)) }
+	
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10: ast.NewDimStatement([]ast.ArrayDeclaration{{Name: "A", Sizes: []ast.Expression{number(3)}}, {Name: "M$", Sizes: []ast.Expression{number(1), number(2)}}}),
+			20: ast.NewArrayAssignmentStatement("A", []ast.Expression{number(2)}, number(7)),
+			30: ast.NewArrayAssignmentStatement("A", []ast.Expression{number(2)}, number(7)),
+			40: ast.NewArrayAssignmentStatement("M$", []ast.Expression{number(1), number(2)}, ast.NewLiteralExpression(runtime.NewStringValue("X"))),
+		},
+		Order: []int{10, 20, 30, 40},
+	}
+	
+	interpreter := NewInterpreter(InterpreterConfig{TraceMode: true, TraceOutput: traceOutput})
+	
+	err := interpreter.Execute(program, runtime.NewEnvironment())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[10]", "[20] A(2) = 7", "[30]", `[40] M$(1,2) = "X"`}, traceOutput.Lines,
+		"declaring an array or assigning an element the value it has is not a change")
 }
 
 // Test interrupt handling for infinite loops
@@ -351,7 +515,7 @@ func TestInterpreter_Execute_InfiniteLoopProtection(t *testing.T) {
 
 	err := interpreter.Execute(program, env)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "execution limit exceeded") // Should detect infinite loop
+	assert.Contains(t, err.Error(), "execution exceeded 1000 steps (possible infinite loop)") // Should detect infinite loop
 }
 
 // Test error message formatting
@@ -448,4 +612,407 @@ func TestInterpreter_Execute_StepCounting(t *testing.T) {
 
 	// Should have executed exactly 3 steps
 	assert.Equal(t, 3, interpreter.GetStepCount())
-}
\ No newline at end of file
+}
+
+// Test the structured result reported by Run
+func TestInterpreter_Run_ResultForEndWithExitCode(t *testing.T) {
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10: ast.NewAssignmentStatement("X", ast.NewLiteralExpression(runtime.NewNumericValue(1))),
+			20: ast.NewAssignmentStatement("Y", ast.NewLiteralExpression(runtime.NewNumericValue(2))),
+			30: ast.NewEndStatementWithExitCode(ast.NewLiteralExpression(runtime.NewNumericValue(3))),
+			40: ast.NewAssignmentStatement("Z", ast.NewLiteralExpression(runtime.NewNumericValue(4))),
+		},
+		Order: []int{10, 20, 30, 40},
+	}
+
+	env := runtime.NewEnvironment()
+	interpreter := NewBasicInterpreter(false)
+
+	result := interpreter.Run(program, env)
+	assert.NoError(t, result.Err)
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Equal(t, 3, result.Steps, "END stops the program before line 40")
+	assert.GreaterOrEqual(t, int64(result.Elapsed), int64(0))
+	assert.Equal(t, 0.0, env.GetVariable("Z").NumValue)
+}
+
+// Test that Run reports the error that stopped the program
+func TestInterpreter_Run_ResultForRuntimeError(t *testing.T) {
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10: ast.NewAssignmentStatement("X", ast.NewBinaryExpression(
+				ast.NewLiteralExpression(runtime.NewNumericValue(1)),
+				ast.OpDivide,
+				ast.NewLiteralExpression(runtime.NewNumericValue(0)))),
+		},
+		Order: []int{10},
+	}
+
+	result := NewBasicInterpreter(false).Run(program, runtime.NewEnvironment())
+	assert.Error(t, result.Err)
+	assert.Contains(t, result.Err.Error(), "runtime error at line 10")
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Equal(t, 1, result.Steps)
+}
+
+// Test that disabled features fail while other statements still run
+func TestInterpreter_Execute_DisabledFeatures(t *testing.T) {
+	interpreter := NewInterpreter(InterpreterConfig{DisabledFeatures: []string{"INPUT", "RND"}})
+
+	t.Run("disabled statement errors", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: ast.NewAssignmentStatement("X", ast.NewLiteralExpression(runtime.NewNumericValue(1))),
+				20: ast.NewInputStatement("Y", &MockInputReader{Inputs: []string{"5"}}, &MockOutputWriter{}),
+			},
+			Order: []int{10, 20},
+		}
+		env := runtime.NewEnvironment()
+
+		err := interpreter.Execute(program, env)
+		assert.ErrorIs(t, err, runtime.ErrFeatureDisabled)
+		assert.Contains(t, err.Error(), "runtime error at line 20: feature disabled: INPUT")
+		assert.Equal(t, 1.0, env.GetVariable("X").NumValue, "statements before the disabled one run normally")
+	})
+
+	t.Run("disabled function errors", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: ast.NewAssignmentStatement("X", ast.NewFunctionCallExpression("RND", nil)),
+			},
+			Order: []int{10},
+		}
+
+		err := interpreter.Execute(program, runtime.NewEnvironment())
+		assert.ErrorIs(t, err, runtime.ErrFeatureDisabled)
+	})
+
+	t.Run("other statements still run", func(t *testing.T) {
+		output := &MockOutputWriter{}
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: ast.NewAssignmentStatement("X", ast.NewFunctionCallExpression("ABS", []ast.Expression{
+					ast.NewLiteralExpression(runtime.NewNumericValue(-2))})),
+				20: ast.NewPrintStatement([]ast.Expression{ast.NewVariableExpression("X")}, output),
+			},
+			Order: []int{10, 20},
+		}
+
+		err := interpreter.Execute(program, runtime.NewEnvironment())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{" 2"}, output.Lines)
+	})
+
+	t.Run("unsupported names are rejected", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: ast.NewAssignmentStatement("X", ast.NewLiteralExpression(runtime.NewNumericValue(1))),
+			},
+			Order: []int{10},
+		}
+
+		for _, feature := range []string{"GOSUB", "randomize", "NOPE"} {
+			sandboxed := NewInterpreter(InterpreterConfig{DisabledFeatures: []string{"INPUT", feature}})
+			env := runtime.NewEnvironment()
+
+			err := sandboxed.Execute(program, env)
+			require.Error(t, err, feature)
+			assert.Contains(t, err.Error(), "cannot disable "+strings.ToUpper(feature))
+			assert.Equal(t, 0.0, env.GetVariable("X").NumValue, "nothing runs with an invalid sandbox")
+		}
+	})
+
+	t.Run("statement and function names are case insensitive", func(t *testing.T) {
+		sandboxed := NewInterpreter(InterpreterConfig{DisabledFeatures: []string{"pause", "Shell", "left$"}})
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: ast.NewAssignmentStatement("X", ast.NewLiteralExpression(runtime.NewNumericValue(1))),
+			},
+			Order: []int{10},
+		}
+
+		assert.NoError(t, sandboxed.Execute(program, runtime.NewEnvironment()))
+	})
+}
+
+// Test running only a range of lines
+func TestInterpreter_ExecuteRange(t *testing.T) {
+	assign := func(name string, value float64) ast.Statement {
+		return ast.NewAssignmentStatement(name, ast.NewLiteralExpression(runtime.NewNumericValue(value)))
+	}
+
+	t.Run("only lines in range run", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: assign("A", 1),
+				30: assign("B", 2),
+				50: assign("C", 3),
+				70: assign("D", 4),
+			},
+			Order: []int{10, 30, 50, 70},
+		}
+		env := runtime.NewEnvironment()
+
+		err := NewBasicInterpreter(false).ExecuteRange(program, env, 20, 60)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0.0, env.GetVariable("A").NumValue)
+		assert.Equal(t, 2.0, env.GetVariable("B").NumValue)
+		assert.Equal(t, 3.0, env.GetVariable("C").NumValue)
+		assert.Equal(t, 0.0, env.GetVariable("D").NumValue)
+	})
+
+	t.Run("stops when control jumps out of range", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: assign("A", 1),
+				30: ast.NewGotoStatement(10, nil),
+				40: assign("B", 2),
+			},
+			Order: []int{10, 30, 40},
+		}
+		program.Lines[30].(*ast.GotoStatement).Program = program
+		env := runtime.NewEnvironment()
+
+		interpreter := NewBasicInterpreter(false)
+		err := interpreter.ExecuteRange(program, env, 30, 40)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0.0, env.GetVariable("A").NumValue)
+		assert.Equal(t, 0.0, env.GetVariable("B").NumValue)
+		assert.Equal(t, 1, interpreter.GetStepCount())
+	})
+}
+
+// Test the precomputed line lookup used for jumps
+func TestInterpreter_LineIndexLookup(t *testing.T) {
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{},
+		Order: []int{10, 20, 100, 5000},
+	}
+	lineIndex := buildLineIndex(program)
+	interpreter := NewBasicInterpreter(false)
+
+	assert.Equal(t, map[int]int{10: 0, 20: 1, 100: 2, 5000: 3}, lineIndex)
+	assert.Equal(t, 3, interpreter.findNextLineIndex(lineIndex, 5000))
+	assert.Equal(t, 0, interpreter.findNextLineIndex(lineIndex, 10))
+	assert.Equal(t, -1, interpreter.findNextLineIndex(lineIndex, 30), "missing lines are not found")
+}
+
+// Test GOSUB and RETURN, including a nested subroutine call
+func TestInterpreter_Execute_GosubReturn(t *testing.T) {
+	output := &MockOutputWriter{}
+	print := func(text string) ast.Statement {
+		return ast.NewPrintStatement([]ast.Expression{ast.NewLiteralExpression(runtime.NewStringValue(text))}, output)
+	}
+
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10:  ast.NewGosubStatement(100, nil),
+			20:  print("back in main"),
+			30:  ast.NewEndStatement(),
+			100: print("outer"),
+			110: ast.NewGosubStatement(200, nil),
+			120: print("outer again"),
+			130: ast.NewReturnStatement(),
+			200: print("inner"),
+			210: ast.NewReturnStatement(),
+		},
+		Order: []int{10, 20, 30, 100, 110, 120, 130, 200, 210},
+	}
+	program.Lines[10].(*ast.GosubStatement).Program = program
+	program.Lines[110].(*ast.GosubStatement).Program = program
+
+	env := runtime.NewEnvironment()
+	err := NewBasicInterpreter(false).Execute(program, env)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"outer", "inner", "outer again", "back in main"}, output.Lines)
+	assert.Empty(t, env.CallStack)
+}
+
+// Test that RETURN resumes with the statement after the GOSUB, on the same line
+func TestInterpreter_Execute_GosubInTheMiddleOfALine(t *testing.T) {
+	output := &MockOutputWriter{}
+	print := func(text string) ast.Statement {
+		return ast.NewPrintStatement([]ast.Expression{ast.NewLiteralExpression(runtime.NewStringValue(text))}, output)
+	}
+	gosub := ast.NewGosubStatement(100, nil)
+	gosubInThen := ast.NewGosubStatement(100, nil)
+
+	// 10 GOSUB 100 : PRINT "after"
+	// 20 IF 1 THEN GOSUB 100 ELSE PRINT "else" : PRINT "not reached"
+	// 30 END
+	// 100 PRINT "sub" : RETURN
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10:  ast.NewCompoundStatement([]ast.Statement{gosub, print("after")}),
+			20:  ast.NewIfElseStatement(ast.NewLiteralExpression(runtime.NewNumericValue(1)), gosubInThen,
+				ast.NewCompoundStatement([]ast.Statement{print("else"), print("not reached")})),
+			30:  ast.NewEndStatement(),
+			100: ast.NewCompoundStatement([]ast.Statement{print("sub"), ast.NewReturnStatement()}),
+		},
+		Order: []int{10, 20, 30, 100},
+	}
+	gosub.Program = program
+	gosubInThen.Program = program
+
+	err := NewBasicInterpreter(false).Execute(program, runtime.NewEnvironment())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sub", "after", "sub"}, output.Lines, "returning into a THEN branch skips the ELSE")
+}
+
+// Test that RETURN with nothing on the call stack is a runtime error
+func TestInterpreter_Execute_ReturnWithoutGosub(t *testing.T) {
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{10: ast.NewReturnStatement()},
+		Order: []int{10},
+	}
+
+	err := NewBasicInterpreter(false).Execute(program, runtime.NewEnvironment())
+	assert.EqualError(t, err, "runtime error at line 10: RETURN without GOSUB")
+}
+
+// cancellingOutputWriter cancels the run when a line is written, like Ctrl-C during a PRINT
+type cancellingOutputWriter struct {
+	MockOutputWriter
+	cancel context.CancelFunc
+}
+
+func (w *cancellingOutputWriter) WriteLine(line string) error {
+	w.cancel()
+	return w.MockOutputWriter.WriteLine(line)
+}
+
+func TestInterpreter_Execute_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	output := &cancellingOutputWriter{cancel: cancel}
+	program := &ast.Program{
+		Lines: map[int]ast.Statement{
+			10: ast.NewPrintStatement([]ast.Expression{ast.NewLiteralExpression(runtime.NewStringValue("tick"))}, output),
+			20: ast.NewGotoStatement(10, nil),
+		},
+		Order: []int{10, 20},
+	}
+	program.Lines[20].(*ast.GotoStatement).Program = program
+
+	interpreter := NewInterpreter(InterpreterConfig{Context: ctx})
+	err := interpreter.Execute(program, runtime.NewEnvironment())
+
+	var interrupted *InterruptedError
+	assert.ErrorAs(t, err, &interrupted)
+	assert.EqualError(t, err, "Interrupted at line 20")
+	assert.Equal(t, []string{"tick"}, output.Lines, "the statement running when cancelled completes")
+	assert.Equal(t, 1, interpreter.GetStepCount())
+}
+
+// scaleFunction multiplies its single numeric argument by a fixed factor
+type scaleFunction struct {
+	factor float64
+}
+
+func (f *scaleFunction) Name() string  { return "SCALE" }
+func (f *scaleFunction) ArgCount() int { return 1 }
+func (f *scaleFunction) Call(args []runtime.Value, env *runtime.Environment) (runtime.Value, error) {
+	return runtime.NewNumericValue(args[0].NumValue * f.factor), nil
+}
+
+// Two interpreters with their own program and environment can run in parallel
+// Each registers its own SCALE function, which the other does not see; run with -race to
+// check that they share no mutable state, the function registry included
+func TestInterpreter_Execute_ParallelRuns(t *testing.T) {
+	source := `10 S = S + SCALE(N)
+20 C = C + 1
+30 IF C < 200 THEN GOTO 10
+40 PRINT S`
+
+	outputs := []*MockOutputWriter{{}, {}}
+	errs := make([]error, len(outputs))
+	var wg sync.WaitGroup
+	for run, output := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			interpreter := NewBasicInterpreter(false)
+			library := ast.NewFunctionLibrary("scale", &scaleFunction{factor: float64(run + 2)})
+			if errs[run] = interpreter.RegisterLibrary(library); errs[run] != nil {
+				return
+			}
+
+			program, err := parser.NewParserWithFunctions(lexer.NewLexer(source), interpreter.Functions()).ParseProgram()
+			if errs[run] = err; err != nil {
+				return
+			}
+			program.Lines[40].(*ast.PrintStatement).Output = output
+
+			env := runtime.NewEnvironment()
+			env.SetVariable("N", runtime.NewNumericValue(float64(run+1)))
+			errs[run] = interpreter.Execute(program, env)
+		}()
+	}
+	wg.Wait()
+
+	for run, output := range outputs {
+		assert.NoError(t, errs[run])
+		assert.Equal(t, []string{fmt.Sprint(" ", 200*(run+2)*(run+1))}, output.Lines, "each run sees only its own variables and functions")
+	}
+	assert.Nil(t, NewBasicInterpreter(false).Functions().Lookup("SCALE"), "registering a library leaves other interpreters unchanged")
+}
+
+func TestInterpreter_Execute_ImplicitEnd(t *testing.T) {
+	assign := func(name string, value float64) ast.Statement {
+		return ast.NewAssignmentStatement(name, ast.NewLiteralExpression(runtime.NewNumericValue(value)))
+	}
+
+	t.Run("running off the last line halts cleanly", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{10: assign("A", 1), 20: assign("B", 2)},
+			Order: []int{10, 20},
+		}
+		env := runtime.NewEnvironment()
+
+		err := NewBasicInterpreter(false).Execute(program, env)
+
+		assert.NoError(t, err)
+		assert.True(t, env.Halted, "the end of the program is an implicit END")
+		assert.Equal(t, 0, env.ExitCode)
+		assert.Equal(t, 2.0, env.GetVariable("B").NumValue)
+	})
+
+	t.Run("returning past the last line halts cleanly", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{
+				10: ast.NewGotoStatement(40, nil),
+				20: assign("A", 1),
+				30: ast.NewReturnStatement(),
+				40: ast.NewGosubStatement(20, nil),
+			},
+			Order: []int{10, 20, 30, 40},
+		}
+		program.Lines[10].(*ast.GotoStatement).Program = program
+		program.Lines[40].(*ast.GosubStatement).Program = program
+		env := runtime.NewEnvironment()
+
+		err := NewBasicInterpreter(false).Execute(program, env)
+
+		assert.NoError(t, err)
+		assert.True(t, env.Halted)
+		assert.Equal(t, 1.0, env.GetVariable("A").NumValue)
+	})
+
+	t.Run("leaving the range does not halt", func(t *testing.T) {
+		program := &ast.Program{
+			Lines: map[int]ast.Statement{10: assign("A", 1), 20: assign("B", 2)},
+			Order: []int{10, 20},
+		}
+		env := runtime.NewEnvironment()
+
+		err := NewBasicInterpreter(false).ExecuteRange(program, env, 10, 10)
+
+		assert.NoError(t, err)
+		assert.False(t, env.Halted, "the program did not end, only the range did")
+	})
+}
@@ -2,6 +2,7 @@ package lexer
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -24,6 +25,7 @@ const (
 	LET
 	IF
 	THEN
+	ELSE
 	GOTO
 	FOR
 	TO
@@ -31,6 +33,24 @@ const (
 	STEP
 	END
 	REM
+	PAUSE
+	ASSERT
+	SHELL
+	GOSUB
+	RETURN
+	RANDOMIZE
+	DATA
+	READ
+	RESTORE
+	DIM
+	WHILE
+	WEND
+	DO
+	LOOP
+	UNTIL
+	LINE
+	DEF
+	STOP
 
 	// Operators
 	ASSIGN  // =
@@ -38,7 +58,12 @@ const (
 	MINUS   // -
 	MULTIPLY // *
 	DIVIDE  // /
+	BACKSLASH // \ (integer division)
 	POWER   // ^
+	MOD     // MOD
+	AND     // AND
+	OR      // OR
+	NOT     // NOT
 
 	// Comparison operators
 	EQ // =
@@ -90,6 +115,8 @@ func (t TokenType) String() string {
 		return "IF"
 	case THEN:
 		return "THEN"
+	case ELSE:
+		return "ELSE"
 	case GOTO:
 		return "GOTO"
 	case FOR:
@@ -104,6 +131,42 @@ func (t TokenType) String() string {
 		return "END"
 	case REM:
 		return "REM"
+	case PAUSE:
+		return "PAUSE"
+	case ASSERT:
+		return "ASSERT"
+	case SHELL:
+		return "SHELL"
+	case GOSUB:
+		return "GOSUB"
+	case RETURN:
+		return "RETURN"
+	case RANDOMIZE:
+		return "RANDOMIZE"
+	case DATA:
+		return "DATA"
+	case READ:
+		return "READ"
+	case RESTORE:
+		return "RESTORE"
+	case DIM:
+		return "DIM"
+	case WHILE:
+		return "WHILE"
+	case WEND:
+		return "WEND"
+	case DO:
+		return "DO"
+	case LOOP:
+		return "LOOP"
+	case UNTIL:
+		return "UNTIL"
+	case LINE:
+		return "LINE"
+	case DEF:
+		return "DEF"
+	case STOP:
+		return "STOP"
 	case ASSIGN:
 		return "ASSIGN"
 	case PLUS:
@@ -114,8 +177,18 @@ func (t TokenType) String() string {
 		return "MULTIPLY"
 	case DIVIDE:
 		return "DIVIDE"
+	case BACKSLASH:
+		return "BACKSLASH"
 	case POWER:
 		return "POWER"
+	case MOD:
+		return "MOD"
+	case AND:
+		return "AND"
+	case OR:
+		return "OR"
+	case NOT:
+		return "NOT"
 	case EQ:
 		return "EQ"
 	case LT:
@@ -228,8 +301,9 @@ func (l *BasicLexer) readString() (string, bool) {
 	return result, true
 }
 
-// readNumber reads a numeric literal (integer or decimal)
-func (l *BasicLexer) readNumber() string {
+// readNumber reads a numeric literal (integer or decimal), with an optional exponent as in
+// 6.02E23 or 2e-4. It reports false when an exponent has no digits, as in 1E or 1E+
+func (l *BasicLexer) readNumber() (string, bool) {
 	position := l.position
 	
 	// Read integer part
@@ -245,15 +319,33 @@ func (l *BasicLexer) readNumber() string {
 		}
 	}
 	
-	return l.input[position:l.position]
+	// An E followed by a letter starts a word, as in 1ELSE, rather than an exponent
+	if (l.ch == 'E' || l.ch == 'e') && !isLetter(l.peekChar()) {
+		l.readChar() // consume 'E'
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
+		}
+		if !isDigit(l.ch) {
+			return l.input[position:l.position], false
+		}
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
+	
+	return l.input[position:l.position], true
 }
 
 // readIdentifier reads an identifier (letters, digits, and $ for string variables)
+// A trailing % marks an integer variable and ends the identifier
 func (l *BasicLexer) readIdentifier() string {
 	position := l.position
 	for isLetter(l.ch) || isDigit(l.ch) || l.ch == '$' {
 		l.readChar()
 	}
+	if l.ch == '%' {
+		l.readChar()
+	}
 	return l.input[position:l.position]
 }
 
@@ -269,18 +361,41 @@ func isDigit(ch byte) bool {
 
 // keywords maps keyword strings to their token types (case-insensitive)
 var keywords = map[string]TokenType{
-	"PRINT": PRINT,
-	"INPUT": INPUT,
-	"LET":   LET,
-	"IF":    IF,
-	"THEN":  THEN,
-	"GOTO":  GOTO,
-	"FOR":   FOR,
-	"TO":    TO,
-	"NEXT":  NEXT,
-	"STEP":  STEP,
-	"END":   END,
-	"REM":   REM,
+	"PRINT":     PRINT,
+	"INPUT":     INPUT,
+	"LET":       LET,
+	"IF":        IF,
+	"THEN":      THEN,
+	"ELSE":      ELSE,
+	"GOTO":      GOTO,
+	"FOR":       FOR,
+	"TO":        TO,
+	"NEXT":      NEXT,
+	"STEP":      STEP,
+	"END":       END,
+	"REM":       REM,
+	"PAUSE":     PAUSE,
+	"ASSERT":    ASSERT,
+	"SHELL":     SHELL,
+	"GOSUB":     GOSUB,
+	"RETURN":    RETURN,
+	"RANDOMIZE": RANDOMIZE,
+	"DATA":      DATA,
+	"READ":      READ,
+	"RESTORE":   RESTORE,
+	"DIM":       DIM,
+	"WHILE":     WHILE,
+	"WEND":      WEND,
+	"DO":        DO,
+	"LOOP":      LOOP,
+	"UNTIL":     UNTIL,
+	"LINE":      LINE,
+	"DEF":       DEF,
+	"STOP":      STOP,
+	"MOD":       MOD,
+	"AND":       AND,
+	"OR":        OR,
+	"NOT":       NOT,
 }
 
 // lookupIdent checks if identifier is a keyword (case-insensitive)
@@ -331,6 +446,8 @@ func (l *BasicLexer) NextToken() Token {
 		tok = l.makeSingleCharToken(MULTIPLY, startLine, startColumn)
 	case '/':
 		tok = l.makeSingleCharToken(DIVIDE, startLine, startColumn)
+	case '\\':
+		tok = l.makeSingleCharToken(BACKSLASH, startLine, startColumn)
 	case '^':
 		tok = l.makeSingleCharToken(POWER, startLine, startColumn)
 	case '<':
@@ -359,6 +476,14 @@ func (l *BasicLexer) NextToken() Token {
 		tok = l.makeSingleCharToken(COLON, startLine, startColumn)
 	case '"':
 		return l.readStringToken(startLine, startColumn)
+	case '&':
+		if prefix := l.peekChar(); prefix == 'H' || prefix == 'h' || prefix == 'B' || prefix == 'b' {
+			return l.readPrefixedNumberToken(startLine, startColumn)
+		}
+		tok = Token{Type: ILLEGAL, Value: string(l.ch), Line: startLine, Column: startColumn}
+		l.isAtLineStart = false
+	case '\'':
+		return l.readCommentToken(startLine, startColumn)
 	case '\n':
 		return l.handleNewline()
 	case 0:
@@ -393,12 +518,36 @@ func (l *BasicLexer) readIdentifierToken(line, column int) Token {
 	value := l.readIdentifier()
 	tokenType := lookupIdent(value)
 	l.isAtLineStart = false
+	if tokenType == REM {
+		// The rest of the line is comment text, kept verbatim in the token value
+		value += l.readRestOfLine()
+	}
 	return Token{Type: tokenType, Value: value, Line: line, Column: column}
 }
 
+// readCommentToken handles the ' shorthand comment, which runs to the end of the line
+// It becomes a REM token whose value is the apostrophe followed by the comment text
+func (l *BasicLexer) readCommentToken(line, column int) Token {
+	l.isAtLineStart = false
+	return Token{Type: REM, Value: l.readRestOfLine(), Line: line, Column: column}
+}
+
+// readRestOfLine reads up to, but not including, the end of the current line
+func (l *BasicLexer) readRestOfLine() string {
+	position := l.position
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+	return l.input[position:l.position]
+}
+
 // readNumberToken handles number and line number tokenization
 func (l *BasicLexer) readNumberToken(line, column int) Token {
-	value := l.readNumber()
+	value, ok := l.readNumber()
+	if !ok {
+		l.isAtLineStart = false
+		return Token{Type: ILLEGAL, Value: fmt.Sprintf("invalid number %s: exponent has no digits", value), Line: line, Column: column}
+	}
 	var tokenType TokenType
 	if l.isAtLineStart && l.isFollowedByKeyword() {
 		tokenType = LINENUMBER
@@ -409,6 +558,33 @@ func (l *BasicLexer) readNumberToken(line, column int) Token {
 	return Token{Type: tokenType, Value: value, Line: line, Column: column}
 }
 
+// readPrefixedNumberToken handles &H hexadecimal and &B binary literals such as &HFF and &B1010
+// They become NUMBER tokens holding the decimal value, or an ILLEGAL token when a digit is invalid
+func (l *BasicLexer) readPrefixedNumberToken(line, column int) Token {
+	position := l.position
+	l.readChar() // consume '&'
+	
+	base, name := 16, "hexadecimal"
+	if l.ch == 'B' || l.ch == 'b' {
+		base, name = 2, "binary"
+	}
+	l.readChar() // consume H or B
+	
+	// Read every letter and digit, so a bad digit is reported rather than starting a new token
+	digits := l.position
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	l.isAtLineStart = false
+	
+	literal := l.input[position:l.position]
+	value, err := strconv.ParseUint(l.input[digits:l.position], base, 53)
+	if err != nil {
+		return Token{Type: ILLEGAL, Value: fmt.Sprintf("invalid %s number %s", name, literal), Line: line, Column: column}
+	}
+	return Token{Type: NUMBER, Value: strconv.FormatUint(value, 10), Line: line, Column: column}
+}
+
 // handleNewline processes newline characters
 func (l *BasicLexer) handleNewline() Token {
 	l.readChar()
@@ -433,6 +609,11 @@ func (l *BasicLexer) isFollowedByKeyword() bool {
 		pos++
 	}
 	
+	// A ' comment starts a statement just like REM
+	if pos < len(l.input) && l.input[pos] == '\'' {
+		return true
+	}
+	
 	// Check if we have a letter (start of identifier/keyword)
 	if pos >= len(l.input) || !isLetter(l.input[pos]) {
 		return false
@@ -449,8 +630,20 @@ func (l *BasicLexer) isFollowedByKeyword() bool {
 	}
 	
 	// Check if it's a keyword
+	// Operator keywords such as MOD and AND continue an expression, so the number is not a line number
 	ident := l.input[start:pos]
-	return lookupIdent(ident) != IDENTIFIER
+	tokenType := lookupIdent(ident)
+	return tokenType != IDENTIFIER && !isOperatorKeyword(tokenType)
+}
+
+// isOperatorKeyword reports whether a keyword token is an operator used inside expressions
+func isOperatorKeyword(tokenType TokenType) bool {
+	switch tokenType {
+	case MOD, AND, OR, NOT:
+		return true
+	default:
+		return false
+	}
 }
 
 // HasMoreTokens returns true if there are more tokens to read
@@ -30,18 +30,42 @@ func TestTokenType_String(t *testing.T) {
 		{LET, "LET"},
 		{IF, "IF"},
 		{THEN, "THEN"},
+		{ELSE, "ELSE"},
 		{GOTO, "GOTO"},
 		{FOR, "FOR"},
 		{TO, "TO"},
 		{NEXT, "NEXT"},
 		{STEP, "STEP"},
 		{END, "END"},
+		{PAUSE, "PAUSE"},
+		{ASSERT, "ASSERT"},
+		{SHELL, "SHELL"},
+		{GOSUB, "GOSUB"},
+		{RETURN, "RETURN"},
+		{RANDOMIZE, "RANDOMIZE"},
+		{DATA, "DATA"},
+		{READ, "READ"},
+		{RESTORE, "RESTORE"},
+		{DIM, "DIM"},
+		{WHILE, "WHILE"},
+		{WEND, "WEND"},
+		{DO, "DO"},
+		{LOOP, "LOOP"},
+		{UNTIL, "UNTIL"},
+		{LINE, "LINE"},
+		{DEF, "DEF"},
+		{STOP, "STOP"},
 		{ASSIGN, "ASSIGN"},
 		{PLUS, "PLUS"},
 		{MINUS, "MINUS"},
 		{MULTIPLY, "MULTIPLY"},
 		{DIVIDE, "DIVIDE"},
+		{BACKSLASH, "BACKSLASH"},
 		{POWER, "POWER"},
+		{MOD, "MOD"},
+		{AND, "AND"},
+		{OR, "OR"},
+		{NOT, "NOT"},
 		{EQ, "EQ"},
 		{LT, "LT"},
 		{GT, "GT"},
@@ -117,6 +141,51 @@ func TestLexer_Numbers(t *testing.T) {
 				{Type: EOF, Value: "", Line: 1, Column: 2},
 			},
 		},
+		{
+			name:  "exponent",
+			input: "6.02E23 1e3",
+			expected: []Token{
+				{Type: NUMBER, Value: "6.02E23", Line: 1, Column: 1},
+				{Type: NUMBER, Value: "1e3", Line: 1, Column: 9},
+				{Type: EOF, Value: "", Line: 1, Column: 12},
+			},
+		},
+		{
+			name:  "signed exponents",
+			input: "1.5E+3 2e-4",
+			expected: []Token{
+				{Type: NUMBER, Value: "1.5E+3", Line: 1, Column: 1},
+				{Type: NUMBER, Value: "2e-4", Line: 1, Column: 8},
+				{Type: EOF, Value: "", Line: 1, Column: 12},
+			},
+		},
+		{
+			name:  "E starting a word is not an exponent",
+			input: "1ELSE",
+			expected: []Token{
+				{Type: NUMBER, Value: "1", Line: 1, Column: 1},
+				{Type: ELSE, Value: "ELSE", Line: 1, Column: 2},
+				{Type: EOF, Value: "", Line: 1, Column: 6},
+			},
+		},
+		{
+			name:  "hexadecimal literal",
+			input: "&HFF &h1a",
+			expected: []Token{
+				{Type: NUMBER, Value: "255", Line: 1, Column: 1},
+				{Type: NUMBER, Value: "26", Line: 1, Column: 6},
+				{Type: EOF, Value: "", Line: 1, Column: 10},
+			},
+		},
+		{
+			name:  "binary literal",
+			input: "&B1010 &b0",
+			expected: []Token{
+				{Type: NUMBER, Value: "10", Line: 1, Column: 1},
+				{Type: NUMBER, Value: "0", Line: 1, Column: 8},
+				{Type: EOF, Value: "", Line: 1, Column: 11},
+			},
+		},
 		{
 			name:  "multiple numbers with spaces",
 			input: "10 20 30",
@@ -234,6 +303,16 @@ func TestLexer_Identifiers(t *testing.T) {
 				{Type: EOF, Value: "", Line: 1, Column: 6},
 			},
 		},
+		{
+			name:  "integer variable",
+			input: "I%=1",
+			expected: []Token{
+				{Type: IDENTIFIER, Value: "I%", Line: 1, Column: 1},
+				{Type: ASSIGN, Value: "=", Line: 1, Column: 3},
+				{Type: NUMBER, Value: "1", Line: 1, Column: 4},
+				{Type: EOF, Value: "", Line: 1, Column: 5},
+			},
+		},
 		{
 			name:  "mixed case identifier",
 			input: "MyVar",
@@ -289,6 +368,80 @@ func TestLexer_Keywords(t *testing.T) {
 				{Type: EOF, Value: "", Line: 1, Column: 6},
 			},
 		},
+		{
+			name:  "PAUSE keyword",
+			input: "pause",
+			expected: []Token{
+				{Type: PAUSE, Value: "pause", Line: 1, Column: 1},
+				{Type: EOF, Value: "", Line: 1, Column: 6},
+			},
+		},
+		{
+			name:  "MOD operator keyword",
+			input: "7 mod 3",
+			expected: []Token{
+				{Type: NUMBER, Value: "7", Line: 1, Column: 1},
+				{Type: MOD, Value: "mod", Line: 1, Column: 3},
+				{Type: NUMBER, Value: "3", Line: 1, Column: 7},
+				{Type: EOF, Value: "", Line: 1, Column: 8},
+			},
+		},
+		{
+			name:  "logical operator keywords",
+			input: "not a and b or c",
+			expected: []Token{
+				{Type: NOT, Value: "not", Line: 1, Column: 1},
+				{Type: IDENTIFIER, Value: "a", Line: 1, Column: 5},
+				{Type: AND, Value: "and", Line: 1, Column: 7},
+				{Type: IDENTIFIER, Value: "b", Line: 1, Column: 11},
+				{Type: OR, Value: "or", Line: 1, Column: 13},
+				{Type: IDENTIFIER, Value: "c", Line: 1, Column: 16},
+				{Type: EOF, Value: "", Line: 1, Column: 17},
+			},
+		},
+		{
+			name:  "a number before AND is not a line number",
+			input: "1 AND 0",
+			expected: []Token{
+				{Type: NUMBER, Value: "1", Line: 1, Column: 1},
+				{Type: AND, Value: "AND", Line: 1, Column: 3},
+				{Type: NUMBER, Value: "0", Line: 1, Column: 7},
+				{Type: EOF, Value: "", Line: 1, Column: 8},
+			},
+		},
+		{
+			name:  "REM keeps the rest of the line as one token",
+			input: "10 REM it's \"odd\n20 PRINT",
+			expected: []Token{
+				{Type: LINENUMBER, Value: "10", Line: 1, Column: 1},
+				{Type: REM, Value: "REM it's \"odd", Line: 1, Column: 4},
+				{Type: LINENUMBER, Value: "20", Line: 2, Column: 1},
+				{Type: PRINT, Value: "PRINT", Line: 2, Column: 4},
+				{Type: EOF, Value: "", Line: 2, Column: 9},
+			},
+		},
+		{
+			name:  "apostrophe comment after a statement",
+			input: "10 PRINT X ' show \"value\n20 ' note",
+			expected: []Token{
+				{Type: LINENUMBER, Value: "10", Line: 1, Column: 1},
+				{Type: PRINT, Value: "PRINT", Line: 1, Column: 4},
+				{Type: IDENTIFIER, Value: "X", Line: 1, Column: 10},
+				{Type: REM, Value: "' show \"value", Line: 1, Column: 12},
+				{Type: LINENUMBER, Value: "20", Line: 2, Column: 1},
+				{Type: REM, Value: "' note", Line: 2, Column: 4},
+				{Type: EOF, Value: "", Line: 2, Column: 10},
+			},
+		},
+		{
+			name:  "apostrophe inside a string is literal",
+			input: `PRINT "it's"`,
+			expected: []Token{
+				{Type: PRINT, Value: "PRINT", Line: 1, Column: 1},
+				{Type: STRING, Value: "it's", Line: 1, Column: 7},
+				{Type: EOF, Value: "", Line: 1, Column: 13},
+			},
+		},
 		{
 			name:  "LET keyword",
 			input: "LET",
@@ -377,6 +530,16 @@ func TestLexer_Operators(t *testing.T) {
 				{Type: EOF, Value: "", Line: 1, Column: 10},
 			},
 		},
+		{
+			name:  "integer division operator",
+			input: "7 \\ 2",
+			expected: []Token{
+				{Type: NUMBER, Value: "7", Line: 1, Column: 1},
+				{Type: BACKSLASH, Value: "\\", Line: 1, Column: 3},
+				{Type: NUMBER, Value: "2", Line: 1, Column: 5},
+				{Type: EOF, Value: "", Line: 1, Column: 6},
+			},
+		},
 		{
 			name:  "assignment operator",
 			input: "=",
@@ -445,6 +608,57 @@ func TestLexer_ErrorCases(t *testing.T) {
 				{Type: EOF, Value: "", Line: 1, Column: 2},
 			},
 		},
+		{
+			name:  "exponent without digits",
+			input: "1E)",
+			expected: []Token{
+				{Type: ILLEGAL, Value: "invalid number 1E: exponent has no digits", Line: 1, Column: 1},
+				{Type: RPAREN, Value: ")", Line: 1, Column: 3},
+				{Type: EOF, Value: "", Line: 1, Column: 4},
+			},
+		},
+		{
+			name:  "signed exponent without digits",
+			input: "2.5e-",
+			expected: []Token{
+				{Type: ILLEGAL, Value: "invalid number 2.5e-: exponent has no digits", Line: 1, Column: 1},
+				{Type: EOF, Value: "", Line: 1, Column: 6},
+			},
+		},
+		{
+			name:  "invalid hexadecimal digit",
+			input: "&HFG)",
+			expected: []Token{
+				{Type: ILLEGAL, Value: "invalid hexadecimal number &HFG", Line: 1, Column: 1},
+				{Type: RPAREN, Value: ")", Line: 1, Column: 5},
+				{Type: EOF, Value: "", Line: 1, Column: 6},
+			},
+		},
+		{
+			name:  "invalid binary digit",
+			input: "&B102",
+			expected: []Token{
+				{Type: ILLEGAL, Value: "invalid binary number &B102", Line: 1, Column: 1},
+				{Type: EOF, Value: "", Line: 1, Column: 6},
+			},
+		},
+		{
+			name:  "prefix without digits",
+			input: "&H",
+			expected: []Token{
+				{Type: ILLEGAL, Value: "invalid hexadecimal number &H", Line: 1, Column: 1},
+				{Type: EOF, Value: "", Line: 1, Column: 3},
+			},
+		},
+		{
+			name:  "ampersand alone",
+			input: "& 1",
+			expected: []Token{
+				{Type: ILLEGAL, Value: "&", Line: 1, Column: 1},
+				{Type: NUMBER, Value: "1", Line: 1, Column: 3},
+				{Type: EOF, Value: "", Line: 1, Column: 4},
+			},
+		},
 		{
 			name:  "invalid character in middle",
 			input: "A @ B",
@@ -6,6 +6,7 @@ import (
 	"basic-interpreter/internal/runtime"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // Parser interface defines the contract for parsing BASIC source code
@@ -18,15 +19,26 @@ type Parser interface {
 // BasicParser implements the Parser interface
 type BasicParser struct {
 	lexer           lexer.Lexer
+	prevToken       lexer.Token // Last token consumed
 	curToken        lexer.Token
 	peekToken       lexer.Token
 	currentLineNumber int
+	arrays          map[string]bool // Names declared by DIM so far, whose NAME(...) is an array element
+	functions       *ast.FunctionRegistry // Built-in functions that calls are resolved to
 }
 
-// NewParser creates a new parser instance
+// NewParser creates a new parser instance that knows the standard built-in functions
 func NewParser(l lexer.Lexer) *BasicParser {
+	return NewParserWithFunctions(l, ast.NewFunctionRegistry())
+}
+
+// NewParserWithFunctions creates a parser that resolves function calls with the given
+// registry, usually that of the interpreter that will run the program
+func NewParserWithFunctions(l lexer.Lexer, functions *ast.FunctionRegistry) *BasicParser {
 	p := &BasicParser{
-		lexer: l,
+		lexer:     l,
+		arrays:    make(map[string]bool),
+		functions: functions,
 	}
 	
 	// Read two tokens, so curToken and peekToken are both set
@@ -38,12 +50,58 @@ func NewParser(l lexer.Lexer) *BasicParser {
 
 // nextToken advances the parser to the next token
 func (p *BasicParser) nextToken() {
+	p.prevToken = p.curToken
 	p.curToken = p.peekToken
 	p.peekToken = p.lexer.NextToken()
+	
+	// A number starting a later source line is a line number even when no keyword follows,
+	// as in 40 X = 1, so that a statement cut short does not run on into the next line
+	if p.peekToken.Type == lexer.NUMBER && p.curToken.Line > 0 && p.peekToken.Line > p.curToken.Line {
+		p.peekToken.Type = lexer.LINENUMBER
+	}
+}
+
+// SyntaxError reports a statement that could not be parsed, with where the parser gave up
+type SyntaxError struct {
+	Line       int // BASIC line number of the statement
+	SourceLine int // Line of the source text
+	Column     int
+	Err        error
+}
+
+// Error describes the failing statement followed by the reason
+// The reason already ends with the position of the offending token, so the source
+// line and column are left to the fields rather than repeated here
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 {
+		// The line number itself could not be parsed
+		return fmt.Sprintf("error parsing line: %v", e.Err)
+	}
+	return fmt.Sprintf("error parsing statement at BASIC line %d: %v", e.Line, e.Err)
 }
 
-// ParseProgram parses a complete BASIC program
+// Unwrap returns the reason the statement could not be parsed
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// ParseProgram parses a complete BASIC program, rejecting jumps to lines it does not have
 func (p *BasicParser) ParseProgram() (*ast.Program, error) {
+	program, err := p.ParseProgramLines()
+	if err != nil {
+		return nil, err
+	}
+	
+	// Catch mistyped jumps before the program runs rather than when they are reached
+	if err := CheckJumpTargets(program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// ParseProgramLines parses a complete BASIC program without checking where its jumps go,
+// for callers that report missing lines themselves or parse a single line on its own
+func (p *BasicParser) ParseProgramLines() (*ast.Program, error) {
 	program := &ast.Program{
 		Lines: make(map[int]ast.Statement),
 		Order: []int{},
@@ -51,61 +109,178 @@ func (p *BasicParser) ParseProgram() (*ast.Program, error) {
 	
 	// Parse all statements in the program
 	for p.curToken.Type != lexer.EOF {
-		// Skip empty lines and whitespace
-		if p.curToken.Type == lexer.EOF {
-			break
-		}
-		
-		// Expect line number
-		if p.curToken.Type != lexer.LINENUMBER && p.curToken.Type != lexer.NUMBER {
-			return nil, fmt.Errorf("expected line number at start of statement at line %d, column %d, found '%s' (%s)", 
-				p.curToken.Line, p.curToken.Column, p.curToken.Value, p.curToken.Type.String())
+		if _, err := p.parseProgramLine(program); err != nil {
+			return nil, err
 		}
-		
-		// Parse line number
-		lineNumber, err := p.parseLineNumber()
-		if err != nil {
-			return nil, fmt.Errorf("at line %d, column %d: %w", p.curToken.Line, p.curToken.Column, err)
+	}
+	
+	p.finishProgram(program)
+	return program, nil
+}
+
+// ParseProgramCollectingErrors parses a complete BASIC program, going on past lines that cannot be parsed
+// It returns the program made of the lines that parsed, and an error for each line that did not, in source order
+func (p *BasicParser) ParseProgramCollectingErrors() (*ast.Program, []*SyntaxError) {
+	program := &ast.Program{
+		Lines: make(map[int]ast.Statement),
+		Order: []int{},
+	}
+	
+	var syntaxErrors []*SyntaxError
+	for p.curToken.Type != lexer.EOF {
+		start := p.curToken
+		lineNumber, err := p.parseProgramLine(program)
+		if err == nil {
+			continue
 		}
 		
-		// Check for duplicate line numbers
-		if _, exists := program.Lines[lineNumber]; exists {
-			return nil, fmt.Errorf("duplicate line number: %d at line %d, column %d", lineNumber, p.curToken.Line, p.curToken.Column)
+		syntaxError, ok := err.(*SyntaxError)
+		if !ok {
+			// The line number itself is wrong, so the error is reported where the line starts
+			syntaxError = &SyntaxError{Line: lineNumber, SourceLine: start.Line, Column: start.Column, Err: err}
 		}
+		syntaxErrors = append(syntaxErrors, syntaxError)
 		
-		// Set current line number for statement parsing
-		p.currentLineNumber = lineNumber
-		
-		// Parse the statement for this line
-		stmt, err := p.ParseStatement()
-		if err != nil {
-			return nil, fmt.Errorf("error parsing statement at BASIC line %d (source line %d, column %d): %w", 
-				lineNumber, p.curToken.Line, p.curToken.Column, err)
+		// Resume with the next numbered line, which a statement cut short may already have
+		// reached, so that a line is never skipped or started in its middle
+		for p.curToken.Type != lexer.EOF && (p.curToken.Type != lexer.LINENUMBER || p.curToken.Line <= start.Line) {
+			p.nextToken()
 		}
-		
-		// Handle multiple statements on the same line separated by colons
-		if p.curToken.Type == lexer.COLON {
-			// For now, we'll create a compound statement or handle it differently
-			// This is a simplified approach - in a full implementation, you might want
-			// to create a compound statement type or handle this differently
-			p.nextToken() // consume colon
-			
-			// If there's another statement after the colon, we need to handle it
-			// For now, let's skip to the next line number or EOF
-			for p.curToken.Type != lexer.EOF && p.curToken.Type != lexer.LINENUMBER {
-				p.nextToken()
-			}
+	}
+	
+	p.finishProgram(program)
+	return program, syntaxErrors
+}
+
+// parseProgramLine parses one numbered line and adds it to the program, returning its line number
+// The line number is 0 when it could not be parsed
+func (p *BasicParser) parseProgramLine(program *ast.Program) (int, error) {
+	// Expect line number
+	if p.curToken.Type != lexer.LINENUMBER && p.curToken.Type != lexer.NUMBER {
+		return 0, fmt.Errorf("expected line number at start of statement at line %d, column %d, found '%s' (%s)", 
+			p.curToken.Line, p.curToken.Column, p.curToken.Value, p.curToken.Type.String())
+	}
+	
+	// Parse line number
+	lineNumber, err := p.parseLineNumber()
+	if err != nil {
+		return 0, fmt.Errorf("%w at line %d, column %d", err, p.curToken.Line, p.curToken.Column)
+	}
+	
+	// Check for duplicate line numbers
+	if _, exists := program.Lines[lineNumber]; exists {
+		return lineNumber, fmt.Errorf("duplicate line number: %d at line %d, column %d", lineNumber, p.curToken.Line, p.curToken.Column)
+	}
+	
+	// Set current line number for statement parsing
+	p.currentLineNumber = lineNumber
+	
+	// Parse the statements for this line, which may be separated by colons
+	stmt, err := p.parseStatementList()
+	if err != nil {
+		// A statement cut short fails at the next line's number, so it is reported where its own line ends
+		position := p.curToken
+		if p.atEndOfLine() {
+			position = p.prevToken
 		}
-		
-		// Add to program
-		program.Lines[lineNumber] = stmt
-		program.Order = append(program.Order, lineNumber)
+		return lineNumber, &SyntaxError{Line: lineNumber, SourceLine: position.Line, Column: position.Column, Err: err}
 	}
 	
+	// Add to program
+	program.Lines[lineNumber] = stmt
+	program.Order = append(program.Order, lineNumber)
+	return lineNumber, nil
+}
+
+// CheckJumpTargets checks that every GOTO and GOSUB, including those after THEN and ELSE,
+// jumps to a line of the program, listing each jump that does not
+func CheckJumpTargets(program *ast.Program) error {
+	missing := ast.MissingJumpTargets(program)
+	if len(missing) == 0 {
+		return nil
+	}
+	
+	jumps := make([]string, len(missing))
+	for i, jump := range missing {
+		jumps[i] = jump.Error()
+	}
+	return fmt.Errorf("%s", strings.Join(jumps, "; "))
+}
+
+// finishProgram sorts the lines of a parsed program and links the statements that refer to it
+func (p *BasicParser) finishProgram(program *ast.Program) {
 	// Sort line numbers
 	p.sortLineNumbers(program)
 	
-	return program, nil
+	// Back-patch GOTO and GOSUB statements now that the whole program is known
+	for _, stmt := range program.Lines {
+		p.linkGotoStatements(stmt, program)
+	}
+}
+
+// linkGotoStatements points every GOTO, GOSUB, WHILE and DO in a statement, including nested ones, at the program
+func (p *BasicParser) linkGotoStatements(statement ast.Statement, program *ast.Program) {
+	switch stmt := statement.(type) {
+	case *ast.GotoStatement:
+		stmt.Program = program
+	case *ast.GosubStatement:
+		stmt.Program = program
+	case *ast.WhileStatement:
+		stmt.Program = program
+	case *ast.DoStatement:
+		stmt.Program = program
+	case *ast.IfStatement:
+		if stmt.ThenStatement != nil {
+			p.linkGotoStatements(stmt.ThenStatement, program)
+		}
+		if stmt.ElseStatement != nil {
+			p.linkGotoStatements(stmt.ElseStatement, program)
+		}
+	case *ast.CompoundStatement:
+		for _, inner := range stmt.Statements {
+			p.linkGotoStatements(inner, program)
+		}
+	}
+}
+
+// parseStatementList parses one or more statements separated by colons, up to the end of the line
+// A single statement is returned as is; several are wrapped in a CompoundStatement
+func (p *BasicParser) parseStatementList() (ast.Statement, error) {
+	stmt, err := p.ParseStatement()
+	if err != nil {
+		return nil, err
+	}
+	
+	statements := []ast.Statement{stmt}
+	p.skipTrailingComment()
+	for p.curToken.Type == lexer.COLON {
+		colonLine := p.curToken.Line
+		p.nextToken() // consume colon
+		
+		// A trailing colon ends the line
+		if p.curToken.Type == lexer.EOF || p.curToken.Line != colonLine {
+			break
+		}
+		
+		next, err := p.ParseStatement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, next)
+		p.skipTrailingComment()
+	}
+	
+	if len(statements) == 1 {
+		return stmt, nil
+	}
+	return ast.NewCompoundStatement(statements), nil
+}
+
+// skipTrailingComment consumes a comment that follows a statement on the same line, as in PRINT X ' note
+func (p *BasicParser) skipTrailingComment() {
+	if p.curToken.Type == lexer.REM {
+		p.nextToken()
+	}
 }
 
 // parseLineNumber parses and validates a line number
@@ -189,7 +364,7 @@ func (p *BasicParser) parseBinaryExpression(
 		
 		// Check for end of input
 		if p.curToken.Type == lexer.EOF {
-			return nil, fmt.Errorf("unexpected end of input after operator %s", operator)
+			return nil, p.errorAt("unexpected end of input after operator %s", operator)
 		}
 		
 		right, err := parseNext()
@@ -222,9 +397,25 @@ func (p *BasicParser) expectToken(expected lexer.TokenType, name string) error {
 	return nil
 }
 
+// errorAt reports an error at the current token, ending the message with the token's position
+// When the current token numbers the next line, the error is at the end of the previous one
+func (p *BasicParser) errorAt(format string, args ...any) error {
+	if p.atEndOfLine() {
+		return fmt.Errorf(format+" at end of line %d", append(args, p.prevToken.Line)...)
+	}
+	return fmt.Errorf(format+" at line %d, column %d", append(args, p.curToken.Line, p.curToken.Column)...)
+}
+
+// atEndOfLine reports whether the current token is the number of a later source line
+// than the last token consumed, so that the statement being parsed has run out of tokens
+func (p *BasicParser) atEndOfLine() bool {
+	return p.curToken.Type == lexer.LINENUMBER && p.prevToken.Line > 0 && p.curToken.Line > p.prevToken.Line
+}
+
 // isEndOfStatement checks if we're at the end of a statement
 func (p *BasicParser) isEndOfStatement() bool {
-	return p.curToken.Type == lexer.EOF || p.curToken.Type == lexer.LINENUMBER || p.curToken.Type == lexer.COLON
+	return p.curToken.Type == lexer.EOF || p.curToken.Type == lexer.LINENUMBER || p.curToken.Type == lexer.COLON ||
+		p.curToken.Type == lexer.REM || p.curToken.Type == lexer.ELSE
 }
 
 // parseExpressionList parses a comma-separated list of expressions
@@ -253,33 +444,77 @@ func (p *BasicParser) parseExpressionList() ([]ast.Expression, error) {
 }
 
 // parsePrintExpressionList parses expressions separated by commas or semicolons (for PRINT statements)
-func (p *BasicParser) parsePrintExpressionList() ([]ast.Expression, error) {
+// It returns the separator after each expression, including a trailing one, which is allowed in PRINT
+func (p *BasicParser) parsePrintExpressionList() ([]ast.Expression, []rune, error) {
 	var expressions []ast.Expression
+	var separators []rune
 	
 	// Parse first expression
-	expr, err := p.ParseExpression()
+	expr, err := p.parsePrintItem()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	expressions = append(expressions, expr)
 	
 	// Parse additional expressions separated by commas or semicolons
 	for p.curToken.Type == lexer.COMMA || p.curToken.Type == lexer.SEMICOLON {
+		separators = append(separators, printSeparator(p.curToken))
+		separatorLine := p.curToken.Line
 		p.nextToken() // consume separator
 		
-		// Check if we're at the end of the statement after the separator
-		if p.isEndOfStatement() {
+		// Check if we're at the end of the statement after the separator, which includes
+		// the next line starting with an assignment such as 20 I = 1
+		if p.isEndOfStatement() || p.curToken.Line != separatorLine {
 			break // Trailing separator is allowed in PRINT statements
 		}
 		
-		expr, err := p.ParseExpression()
+		expr, err := p.parsePrintItem()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		expressions = append(expressions, expr)
 	}
 	
-	return expressions, nil
+	return expressions, separators, nil
+}
+
+// parsePrintItem parses one item of a PRINT list: an expression, or TAB(n) or SPC(n),
+// which position the print head and so are only valid in PRINT lists
+func (p *BasicParser) parsePrintItem() (ast.Expression, error) {
+	if p.curToken.Type != lexer.IDENTIFIER || p.peekToken.Type != lexer.LPAREN || p.arrays[ast.NormalizeVariableName(p.curToken.Value)] {
+		return p.ParseExpression()
+	}
+	
+	name := strings.ToUpper(p.curToken.Value)
+	if name != "TAB" && name != "SPC" {
+		return p.ParseExpression()
+	}
+	
+	p.nextToken() // consume TAB or SPC
+	p.nextToken() // consume (
+	
+	arg, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s argument: %w", name, err)
+	}
+	
+	if p.curToken.Type != lexer.RPAREN {
+		return nil, p.errorAt("expected ) after %s argument", name)
+	}
+	p.nextToken() // consume )
+	
+	if name == "TAB" {
+		return ast.NewTabExpression(arg), nil
+	}
+	return ast.NewSpcExpression(arg), nil
+}
+
+// printSeparator returns the rune of a comma or semicolon token separating PRINT items
+func printSeparator(token lexer.Token) rune {
+	if token.Type == lexer.SEMICOLON {
+		return ';'
+	}
+	return ','
 }
 
 // ParseStatement parses a single BASIC statement (without line number)
@@ -290,18 +525,57 @@ func (p *BasicParser) ParseStatement() (ast.Statement, error) {
 		return p.parsePrintStatement()
 	case lexer.INPUT:
 		return p.parseInputStatement()
+	case lexer.LINE:
+		return p.parseLineInputStatement()
+	case lexer.DEF:
+		return p.parseDefFnStatement()
 	case lexer.GOTO:
 		return p.parseGotoStatement()
+	case lexer.GOSUB:
+		return p.parseGosubStatement()
+	case lexer.RETURN:
+		p.nextToken() // consume RETURN
+		return ast.NewReturnStatement(), nil
+	case lexer.RANDOMIZE:
+		return p.parseRandomizeStatement()
+	case lexer.DATA:
+		return p.parseDataStatement()
+	case lexer.READ:
+		return p.parseReadStatement()
+	case lexer.RESTORE:
+		return p.parseRestoreStatement()
+	case lexer.DIM:
+		return p.parseDimStatement()
 	case lexer.IF:
 		return p.parseIfStatement()
 	case lexer.FOR:
 		return p.parseForStatement()
 	case lexer.NEXT:
 		return p.parseNextStatement()
+	case lexer.WHILE:
+		return p.parseWhileStatement()
+	case lexer.WEND:
+		p.nextToken() // consume WEND
+		return ast.NewWendStatement(), nil
+	case lexer.DO:
+		return p.parseDoStatement()
+	case lexer.LOOP:
+		return p.parseLoopStatement()
 	case lexer.END:
 		return p.parseEndStatement()
+	case lexer.STOP:
+		p.nextToken() // consume STOP
+		return ast.NewStopStatement(), nil
 	case lexer.REM:
 		return p.parseRemStatement()
+	case lexer.PAUSE:
+		return p.parsePauseStatement()
+	case lexer.ASSERT:
+		return p.parseAssertStatement()
+	case lexer.SHELL:
+		return p.parseShellStatement()
+	case lexer.LET:
+		return p.parseLetStatement()
 	case lexer.IDENTIFIER:
 		return p.parseAssignmentStatement()
 	case lexer.NUMBER:
@@ -329,13 +603,14 @@ func (p *BasicParser) parsePrintStatement() (ast.Statement, error) {
 	}
 	
 	// Parse expressions separated by commas or semicolons
-	expressions, err := p.parsePrintExpressionList()
+	expressions, separators, err := p.parsePrintExpressionList()
 	if err != nil {
-		return nil, fmt.Errorf("error parsing PRINT expressions at line %d, column %d: %w", 
-			p.curToken.Line, p.curToken.Column, err)
+		return nil, fmt.Errorf("error parsing PRINT expressions: %w", err)
 	}
 	
-	return ast.NewPrintStatement(expressions, nil), nil
+	// A trailing semicolon leaves the line open
+	trailingSemicolon := len(separators) == len(expressions) && separators[len(separators)-1] == ';'
+	return ast.NewPrintStatementWithSeparators(expressions, separators[:len(expressions)-1], trailingSemicolon, nil), nil
 }
 
 // parseInputStatement parses an INPUT statement
@@ -368,10 +643,72 @@ func (p *BasicParser) parseInputStatement() (ast.Statement, error) {
 	variable := p.curToken.Value
 	p.nextToken() // consume variable
 	
+	var stmt *ast.InputStatement
 	if prompt != "" {
-		return ast.NewInputStatementWithPrompt(prompt, variable, nil, nil), nil
+		stmt = ast.NewInputStatementWithPrompt(prompt, variable, nil, nil)
+	} else {
+		stmt = ast.NewInputStatement(variable, nil, nil)
+	}
+	
+	// Optional bounds: RANGE low TO high
+	if p.curToken.Type == lexer.IDENTIFIER && strings.ToUpper(p.curToken.Value) == "RANGE" {
+		inputRange, err := p.parseInputRange(variable)
+		if err != nil {
+			return nil, err
+		}
+		stmt.Range = inputRange
+	}
+	return stmt, nil
+}
+
+// parseLineInputStatement parses LINE INPUT, which takes the same optional prompt as INPUT
+// but reads the whole line into a string variable
+func (p *BasicParser) parseLineInputStatement() (ast.Statement, error) {
+	p.nextToken() // consume LINE
+	
+	if p.curToken.Type != lexer.INPUT {
+		return nil, fmt.Errorf("expected INPUT after LINE, found '%s' (%s) at line %d, column %d",
+			p.curToken.Value, p.curToken.Type.String(), p.curToken.Line, p.curToken.Column)
+	}
+	
+	stmt, err := p.parseInputStatement()
+	if err != nil {
+		return nil, err
+	}
+	input := stmt.(*ast.InputStatement)
+	if !ast.IsStringVariable(input.Variable) {
+		return nil, fmt.Errorf("LINE INPUT requires a string variable, found %s", input.Variable)
+	}
+	if input.Range != nil {
+		return nil, fmt.Errorf("RANGE cannot be used with LINE INPUT")
+	}
+	input.Line = true
+	return input, nil
+}
+
+// parseInputRange parses the RANGE low TO high clause of a numeric INPUT
+func (p *BasicParser) parseInputRange(variable string) (*ast.InputRange, error) {
+	if ast.IsStringVariable(variable) {
+		return nil, fmt.Errorf("RANGE requires a numeric variable, found %s", variable)
+	}
+	p.nextToken() // consume RANGE
+	
+	low, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing INPUT range: %w", err)
+	}
+	
+	if p.curToken.Type != lexer.TO {
+		return nil, fmt.Errorf("expected TO in INPUT range at line %d, column %d", p.curToken.Line, p.curToken.Column)
+	}
+	p.nextToken() // consume TO
+	
+	high, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing INPUT range: %w", err)
 	}
-	return ast.NewInputStatement(variable, nil, nil), nil
+	
+	return &ast.InputRange{Low: low, High: high}, nil
 }
 
 // parseGotoStatement parses a GOTO statement
@@ -382,22 +719,46 @@ func (p *BasicParser) parseGotoStatement() (ast.Statement, error) {
 	
 	p.nextToken() // consume GOTO
 	
-	// Expect line number
+	lineNumber, err := p.parseJumpTarget("GOTO")
+	if err != nil {
+		return nil, err
+	}
+	
+	return ast.NewGotoStatement(lineNumber, nil), nil
+}
+
+// parseGosubStatement parses a GOSUB statement
+func (p *BasicParser) parseGosubStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.GOSUB {
+		return nil, fmt.Errorf("expected GOSUB")
+	}
+	
+	p.nextToken() // consume GOSUB
+	
+	lineNumber, err := p.parseJumpTarget("GOSUB")
+	if err != nil {
+		return nil, err
+	}
+	
+	return ast.NewGosubStatement(lineNumber, nil), nil
+}
+
+// parseJumpTarget parses the line number that follows GOTO, GOSUB or RESTORE
+func (p *BasicParser) parseJumpTarget(keyword string) (int, error) {
 	if p.curToken.Type != lexer.NUMBER {
-		return nil, fmt.Errorf("expected line number after GOTO")
+		return 0, p.errorAt("expected line number after %s", keyword)
 	}
 	
 	lineNumber, err := strconv.Atoi(p.curToken.Value)
 	if err != nil {
-		return nil, fmt.Errorf("invalid line number: %s", p.curToken.Value)
+		return 0, p.errorAt("invalid line number: %s", p.curToken.Value)
 	}
 	
 	p.nextToken() // consume line number
-	
-	return ast.NewGotoStatement(lineNumber, nil), nil
+	return lineNumber, nil
 }
 
-// parseIfStatement parses an IF-THEN or IF-GOTO statement
+// parseIfStatement parses an IF-THEN or IF-GOTO statement, with an optional ELSE branch
 func (p *BasicParser) parseIfStatement() (ast.Statement, error) {
 	if p.curToken.Type != lexer.IF {
 		return nil, fmt.Errorf("expected IF at line %d, column %d", p.curToken.Line, p.curToken.Column)
@@ -416,8 +777,8 @@ func (p *BasicParser) parseIfStatement() (ast.Statement, error) {
 	if p.curToken.Type == lexer.THEN {
 		p.nextToken() // consume THEN
 		
-		// Parse THEN statement
-		thenStatement, err = p.ParseStatement()
+		// The rest of the line up to any ELSE, including statements after colons, belongs to THEN
+		thenStatement, err = p.parseStatementList()
 		if err != nil {
 			return nil, fmt.Errorf("error parsing THEN statement: %w", err)
 		}
@@ -432,7 +793,19 @@ func (p *BasicParser) parseIfStatement() (ast.Statement, error) {
 			p.curToken.Value, p.curToken.Type.String(), p.curToken.Line, p.curToken.Column)
 	}
 	
-	return ast.NewIfStatement(condition, thenStatement), nil
+	if p.curToken.Type != lexer.ELSE {
+		return ast.NewIfStatement(condition, thenStatement), nil
+	}
+	
+	p.nextToken() // consume ELSE
+	
+	// Everything after ELSE, to the end of the line, belongs to ELSE
+	elseStatement, err := p.parseStatementList()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ELSE statement: %w", err)
+	}
+	
+	return ast.NewIfElseStatement(condition, thenStatement, elseStatement), nil
 }
 
 // parseForStatement parses a FOR statement
@@ -445,7 +818,7 @@ func (p *BasicParser) parseForStatement() (ast.Statement, error) {
 	
 	// Expect variable name
 	if p.curToken.Type != lexer.IDENTIFIER {
-		return nil, fmt.Errorf("expected variable name in FOR statement")
+		return nil, p.errorAt("expected variable name in FOR statement")
 	}
 	
 	variable := p.curToken.Value
@@ -453,7 +826,7 @@ func (p *BasicParser) parseForStatement() (ast.Statement, error) {
 	
 	// Expect assignment operator
 	if p.curToken.Type != lexer.ASSIGN {
-		return nil, fmt.Errorf("expected = in FOR statement")
+		return nil, p.errorAt("expected = in FOR statement")
 	}
 	
 	p.nextToken() // consume =
@@ -466,7 +839,7 @@ func (p *BasicParser) parseForStatement() (ast.Statement, error) {
 	
 	// Expect TO
 	if p.curToken.Type != lexer.TO {
-		return nil, fmt.Errorf("expected TO in FOR statement")
+		return nil, p.errorAt("expected TO in FOR statement")
 	}
 	
 	p.nextToken() // consume TO
@@ -511,106 +884,633 @@ func (p *BasicParser) parseNextStatement() (ast.Statement, error) {
 	return ast.NewNextStatement(variable), nil
 }
 
-// parseEndStatement parses an END statement
-func (p *BasicParser) parseEndStatement() (ast.Statement, error) {
-	if p.curToken.Type != lexer.END {
-		return nil, fmt.Errorf("expected END")
+// parseWhileStatement parses a WHILE statement: WHILE condition
+func (p *BasicParser) parseWhileStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.WHILE {
+		return nil, fmt.Errorf("expected WHILE")
 	}
 	
-	p.nextToken() // consume END
+	p.nextToken() // consume WHILE
 	
-	return ast.NewEndStatement(), nil
+	condition, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing WHILE condition: %w", err)
+	}
+	
+	return ast.NewWhileStatement(condition, p.currentLineNumber), nil
 }
 
-// parseRemStatement parses a REM (comment) statement
-func (p *BasicParser) parseRemStatement() (ast.Statement, error) {
-	if p.curToken.Type != lexer.REM {
-		return nil, fmt.Errorf("expected REM")
+// parseDoStatement parses a DO statement: DO [WHILE|UNTIL condition]
+func (p *BasicParser) parseDoStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.DO {
+		return nil, fmt.Errorf("expected DO")
 	}
 	
-	p.nextToken() // consume REM
+	keywordLine := p.curToken.Line
+	p.nextToken() // consume DO
 	
-	// Consume the rest of the line as comment text
-	var comment string
-	for !p.isEndOfStatement() {
-		comment += p.curToken.Value + " "
-		p.nextToken()
+	condition, until, err := p.parseLoopCondition("DO", keywordLine)
+	if err != nil {
+		return nil, err
 	}
-	
-	return ast.NewRemStatement(comment), nil
+	return ast.NewDoStatement(condition, until, p.currentLineNumber), nil
 }
 
-// parseAssignmentStatement parses an assignment statement
-func (p *BasicParser) parseAssignmentStatement() (ast.Statement, error) {
-	if p.curToken.Type != lexer.IDENTIFIER && p.curToken.Type != lexer.NUMBER {
-		return nil, fmt.Errorf("expected variable name")
+// parseLoopStatement parses a LOOP statement: LOOP [WHILE|UNTIL condition]
+func (p *BasicParser) parseLoopStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.LOOP {
+		return nil, fmt.Errorf("expected LOOP")
 	}
 	
-	variable := p.curToken.Value
-	p.nextToken() // consume variable
+	keywordLine := p.curToken.Line
+	p.nextToken() // consume LOOP
 	
-	// Expect assignment operator
-	if p.curToken.Type != lexer.ASSIGN {
-		return nil, fmt.Errorf("expected assignment operator")
+	condition, until, err := p.parseLoopCondition("LOOP", keywordLine)
+	if err != nil {
+		return nil, err
+	}
+	return ast.NewLoopStatement(condition, until), nil
+}
+
+// parseLoopCondition parses the optional WHILE or UNTIL condition after DO or LOOP
+// It returns a nil condition when there is none, including when the next line starts
+// right after the keyword
+func (p *BasicParser) parseLoopCondition(keyword string, keywordLine int) (ast.Expression, bool, error) {
+	if p.isEndOfStatement() || p.curToken.Line != keywordLine {
+		return nil, false, nil
 	}
 	
-	p.nextToken() // consume =
+	if p.curToken.Type != lexer.WHILE && p.curToken.Type != lexer.UNTIL {
+		return nil, false, fmt.Errorf("expected WHILE or UNTIL after %s, found '%s' (%s) at line %d, column %d",
+			keyword, p.curToken.Value, p.curToken.Type, p.curToken.Line, p.curToken.Column)
+	}
+	until := p.curToken.Type == lexer.UNTIL
+	p.nextToken() // consume WHILE or UNTIL
 	
-	// Parse expression
-	expr, err := p.ParseExpression()
+	condition, err := p.ParseExpression()
 	if err != nil {
-		return nil, fmt.Errorf("error parsing assignment expression: %w", err)
+		return nil, false, fmt.Errorf("error parsing %s condition: %w", keyword, err)
 	}
-	
-	return ast.NewAssignmentStatement(variable, expr), nil
-}
-
-// ParseExpression parses an expression (placeholder implementation)
-func (p *BasicParser) ParseExpression() (ast.Expression, error) {
-	return p.parseComparison()
+	return condition, until, nil
 }
 
-// parseComparison parses comparison expressions
-func (p *BasicParser) parseComparison() (ast.Expression, error) {
-	left, err := p.parseArithmetic()
-	if err != nil {
-		return nil, err
+// parseDefFnStatement parses a function definition: DEF FNname(parameter) = expression
+func (p *BasicParser) parseDefFnStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.DEF {
+		return nil, fmt.Errorf("expected DEF")
 	}
 	
-	// Check for comparison operators
-	if p.isComparisonOperator(p.curToken.Type) {
-		operator := p.getComparisonOperator(p.curToken.Type)
-		p.nextToken() // consume operator
-		
-		right, err := p.parseArithmetic()
-		if err != nil {
-			return nil, err
-		}
-		
-		return ast.NewComparisonExpression(left, operator, right), nil
+	p.nextToken() // consume DEF
+	
+	if p.curToken.Type != lexer.IDENTIFIER || !ast.IsUserFunctionName(p.curToken.Value) {
+		return nil, fmt.Errorf("expected function name starting with FN after DEF, found '%s' (%s) at line %d, column %d",
+			p.curToken.Value, p.curToken.Type.String(), p.curToken.Line, p.curToken.Column)
 	}
+	name := p.curToken.Value
+	p.nextToken() // consume function name
 	
-	return left, nil
-}
-
-// parseArithmetic parses arithmetic expressions with precedence
-func (p *BasicParser) parseArithmetic() (ast.Expression, error) {
-	return p.parseAddition()
-}
-
-// parseAddition parses addition and subtraction (lowest precedence)
-func (p *BasicParser) parseAddition() (ast.Expression, error) {
-	return p.parseBinaryExpression(
-		p.parseMultiplication,
-		[]lexer.TokenType{lexer.PLUS, lexer.MINUS},
-	)
+	if p.curToken.Type != lexer.LPAREN {
+		return nil, fmt.Errorf("expected ( after %s at line %d, column %d", name, p.curToken.Line, p.curToken.Column)
+	}
+	p.nextToken() // consume (
+	
+	if p.curToken.Type != lexer.IDENTIFIER {
+		return nil, fmt.Errorf("expected parameter name in DEF %s, found '%s' (%s) at line %d, column %d",
+			name, p.curToken.Value, p.curToken.Type.String(), p.curToken.Line, p.curToken.Column)
+	}
+	parameter := p.curToken.Value
+	p.nextToken() // consume parameter
+	
+	if p.curToken.Type != lexer.RPAREN {
+		return nil, fmt.Errorf("expected ) after parameter of %s at line %d, column %d", name, p.curToken.Line, p.curToken.Column)
+	}
+	p.nextToken() // consume )
+	
+	if p.curToken.Type != lexer.ASSIGN {
+		return nil, fmt.Errorf("expected = in DEF %s at line %d, column %d", name, p.curToken.Line, p.curToken.Column)
+	}
+	p.nextToken() // consume =
+	
+	body, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing body of %s: %w", name, err)
+	}
+	return ast.NewDefFnStatement(name, parameter, body), nil
+}
+
+// parseEndStatement parses an END statement
+func (p *BasicParser) parseEndStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.END {
+		return nil, fmt.Errorf("expected END")
+	}
+	
+	p.nextToken() // consume END
+	
+	// Optional exit code: END n
+	if !p.isEndOfStatement() {
+		exitCode, err := p.ParseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing END exit code: %w", err)
+		}
+		return ast.NewEndStatementWithExitCode(exitCode), nil
+	}
+	
+	return ast.NewEndStatement(), nil
+}
+
+// parseRandomizeStatement parses a RANDOMIZE statement: RANDOMIZE [seed]
+func (p *BasicParser) parseRandomizeStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.RANDOMIZE {
+		return nil, fmt.Errorf("expected RANDOMIZE")
+	}
+	
+	p.nextToken() // consume RANDOMIZE
+	
+	if p.isEndOfStatement() {
+		return ast.NewRandomizeStatement(nil), nil
+	}
+	
+	seed, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing RANDOMIZE seed: %w", err)
+	}
+	return ast.NewRandomizeStatement(seed), nil
+}
+
+// parseDataStatement parses a DATA statement: DATA value, value, ...
+// Each value is a number, optionally signed, or a quoted string
+func (p *BasicParser) parseDataStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.DATA {
+		return nil, fmt.Errorf("expected DATA")
+	}
+	
+	p.nextToken() // consume DATA
+	
+	var values []runtime.Value
+	for {
+		value, err := p.parseDataValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		
+		if p.curToken.Type != lexer.COMMA {
+			break
+		}
+		p.nextToken() // consume comma
+	}
+	
+	return ast.NewDataStatement(values), nil
+}
+
+// parseDataValue parses one constant of a DATA statement
+func (p *BasicParser) parseDataValue() (runtime.Value, error) {
+	switch p.curToken.Type {
+	case lexer.STRING:
+		value := runtime.NewStringValue(p.curToken.Value)
+		p.nextToken() // consume string
+		return value, nil
+	case lexer.MINUS, lexer.PLUS:
+		sign := 1.0
+		if p.curToken.Type == lexer.MINUS {
+			sign = -1.0
+		}
+		p.nextToken() // consume sign
+		if p.curToken.Type != lexer.NUMBER {
+			return runtime.Value{}, fmt.Errorf("expected number after sign in DATA at line %d, column %d", p.curToken.Line, p.curToken.Column)
+		}
+		value, err := p.parseDataNumber()
+		if err != nil {
+			return runtime.Value{}, err
+		}
+		return runtime.NewNumericValue(sign * value.NumValue), nil
+	case lexer.NUMBER:
+		return p.parseDataNumber()
+	default:
+		return runtime.Value{}, fmt.Errorf("expected number or string in DATA, found '%s' at line %d, column %d",
+			p.curToken.Value, p.curToken.Line, p.curToken.Column)
+	}
+}
+
+// parseDataNumber parses an unsigned numeric constant of a DATA statement
+func (p *BasicParser) parseDataNumber() (runtime.Value, error) {
+	number, err := strconv.ParseFloat(p.curToken.Value, 64)
+	if err != nil {
+		return runtime.Value{}, p.errorAt("invalid number in DATA: %s", p.curToken.Value)
+	}
+	p.nextToken() // consume number
+	return runtime.NewNumericValue(number), nil
+}
+
+// parseReadStatement parses a READ statement: READ variable, variable, ...
+// or the array-fill form READ A(), n
+func (p *BasicParser) parseReadStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.READ {
+		return nil, fmt.Errorf("expected READ")
+	}
+	
+	p.nextToken() // consume READ
+	
+	var variables []string
+	for {
+		if p.curToken.Type != lexer.IDENTIFIER {
+			return nil, fmt.Errorf("expected variable name in READ statement, found '%s' (%s) at line %d, column %d",
+				p.curToken.Value, p.curToken.Type.String(), p.curToken.Line, p.curToken.Column)
+		}
+		variables = append(variables, p.curToken.Value)
+		p.nextToken() // consume variable
+		
+		if p.curToken.Type == lexer.LPAREN && len(variables) == 1 {
+			return p.parseReadArray(variables[0])
+		}
+		
+		if p.curToken.Type != lexer.COMMA {
+			break
+		}
+		p.nextToken() // consume comma
+	}
+	
+	return ast.NewReadStatement(variables), nil
+}
+
+// parseReadArray parses the rest of READ A(), n after the array name
+func (p *BasicParser) parseReadArray(name string) (ast.Statement, error) {
+	p.nextToken() // consume (
+	if p.curToken.Type != lexer.RPAREN {
+		return nil, p.errorAt("expected ) after array %s in READ %s(), n", name, name)
+	}
+	p.nextToken() // consume )
+	
+	if p.curToken.Type != lexer.COMMA {
+		return nil, p.errorAt("expected , and the number of values to READ into array %s", name)
+	}
+	p.nextToken() // consume comma
+	
+	count, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing READ count: %w", err)
+	}
+	return ast.NewReadArrayStatement(name, count), nil
+}
+
+// parseRestoreStatement parses a RESTORE statement: RESTORE [line]
+func (p *BasicParser) parseRestoreStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.RESTORE {
+		return nil, fmt.Errorf("expected RESTORE")
+	}
+	
+	p.nextToken() // consume RESTORE
+	
+	if p.isEndOfStatement() {
+		return ast.NewRestoreStatement(0), nil
+	}
+	
+	lineNumber, err := p.parseJumpTarget("RESTORE")
+	if err != nil {
+		return nil, err
+	}
+	return ast.NewRestoreStatement(lineNumber), nil
+}
+
+// parseRemStatement parses a REM (comment) statement
+func (p *BasicParser) parseRemStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.REM {
+		return nil, fmt.Errorf("expected REM")
+	}
+	
+	// The lexer keeps the rest of the line in the REM token, after the keyword or the ' shorthand
+	comment := p.curToken.Value
+	if strings.HasPrefix(comment, "'") {
+		comment = comment[len("'"):]
+	} else {
+		comment = comment[len("REM"):]
+	}
+	comment = strings.TrimSpace(comment)
+	p.nextToken() // consume REM
+	
+	return ast.NewRemStatement(comment), nil
+}
+
+// parsePauseStatement parses a PAUSE statement with an optional prompt string
+func (p *BasicParser) parsePauseStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.PAUSE {
+		return nil, fmt.Errorf("expected PAUSE")
+	}
+	
+	p.nextToken() // consume PAUSE
+	
+	var prompt string
+	if p.curToken.Type == lexer.STRING {
+		prompt = p.curToken.Value
+		p.nextToken() // consume string
+	}
+	
+	if !p.isEndOfStatement() {
+		return nil, fmt.Errorf("expected prompt string or end of statement after PAUSE, found '%s' (%s) at line %d, column %d", 
+			p.curToken.Value, p.curToken.Type.String(), p.curToken.Line, p.curToken.Column)
+	}
+	
+	return ast.NewPauseStatement(prompt, nil, nil), nil
+}
+
+// parseAssertStatement parses an ASSERT statement: ASSERT condition
+func (p *BasicParser) parseAssertStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.ASSERT {
+		return nil, fmt.Errorf("expected ASSERT")
+	}
+	
+	p.nextToken() // consume ASSERT
+	
+	if p.isEndOfStatement() {
+		return nil, fmt.Errorf("expected condition after ASSERT at line %d, column %d", p.curToken.Line, p.curToken.Column)
+	}
+	
+	condition, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ASSERT condition: %w", err)
+	}
+	
+	return ast.NewAssertStatement(condition, p.currentLineNumber), nil
+}
+
+// parseShellStatement parses a SHELL statement: SHELL command [, variable$]
+func (p *BasicParser) parseShellStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.SHELL {
+		return nil, fmt.Errorf("expected SHELL")
+	}
+	
+	p.nextToken() // consume SHELL
+	
+	if p.isEndOfStatement() {
+		return nil, fmt.Errorf("expected command after SHELL at line %d, column %d", p.curToken.Line, p.curToken.Column)
+	}
+	
+	command, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing SHELL command: %w", err)
+	}
+	
+	if p.curToken.Type != lexer.COMMA {
+		return ast.NewShellStatement(command, "", nil, nil), nil
+	}
+	p.nextToken() // consume comma
+	
+	if p.curToken.Type != lexer.IDENTIFIER || !strings.HasSuffix(p.curToken.Value, "$") {
+		return nil, fmt.Errorf("expected string variable after SHELL command, found '%s' at line %d, column %d",
+			p.curToken.Value, p.curToken.Line, p.curToken.Column)
+	}
+	variable := p.curToken.Value
+	p.nextToken() // consume variable
+	
+	return ast.NewShellStatement(command, variable, nil, nil), nil
+}
+
+// parseAssignmentStatement parses an assignment statement
+func (p *BasicParser) parseAssignmentStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.IDENTIFIER && p.curToken.Type != lexer.NUMBER {
+		return nil, p.errorAt("expected variable name")
+	}
+	
+	variable := p.curToken.Value
+	p.nextToken() // consume variable
+	
+	// NAME(index) = value assigns to an array element
+	if p.curToken.Type == lexer.LPAREN {
+		return p.parseArrayAssignment(variable)
+	}
+	
+	// Expect assignment operator
+	if p.curToken.Type != lexer.ASSIGN {
+		return nil, p.errorAt("expected assignment operator")
+	}
+	
+	p.nextToken() // consume =
+	
+	// Parse expression
+	expr, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing assignment expression: %w", err)
+	}
+	
+	return ast.NewAssignmentStatement(variable, expr), nil
+}
+
+// parseLetStatement parses an assignment written with the optional LET keyword: LET X = value
+func (p *BasicParser) parseLetStatement() (ast.Statement, error) {
+	if err := p.expectToken(lexer.LET, "LET"); err != nil {
+		return nil, err
+	}
+	
+	p.nextToken() // consume LET
+	
+	if p.curToken.Type != lexer.IDENTIFIER {
+		return nil, fmt.Errorf("expected variable name after LET, found '%s' (%s) at line %d, column %d",
+			p.curToken.Value, p.curToken.Type, p.curToken.Line, p.curToken.Column)
+	}
+	return p.parseAssignmentStatement()
+}
+
+// parseArrayAssignment parses the rest of an assignment to an array element: (indexes) = value
+func (p *BasicParser) parseArrayAssignment(name string) (ast.Statement, error) {
+	indexes, err := p.parseArrayIndexes(name)
+	if err != nil {
+		return nil, err
+	}
+	
+	if p.curToken.Type != lexer.ASSIGN {
+		return nil, p.errorAt("expected assignment operator")
+	}
+	p.nextToken() // consume =
+	
+	expr, err := p.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing assignment expression: %w", err)
+	}
+	
+	return ast.NewArrayAssignmentStatement(name, indexes, expr), nil
+}
+
+// parseArrayIndexes parses the parenthesized, comma separated indexes that follow an array name
+func (p *BasicParser) parseArrayIndexes(name string) ([]ast.Expression, error) {
+	if p.curToken.Type != lexer.LPAREN {
+		return nil, p.errorAt("expected ( after array %s", name)
+	}
+	p.nextToken() // consume (
+	
+	var indexes []ast.Expression
+	for {
+		index, err := p.ParseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing index of array %s: %w", name, err)
+		}
+		indexes = append(indexes, index)
+		
+		if p.curToken.Type != lexer.COMMA {
+			break
+		}
+		p.nextToken() // consume comma
+	}
+	
+	if p.curToken.Type != lexer.RPAREN {
+		return nil, p.errorAt("expected ) after index of array %s", name)
+	}
+	p.nextToken() // consume )
+	
+	return indexes, nil
+}
+
+// parseDimStatement parses a DIM statement: DIM A(10), M(3, 4), B$(n), ...
+// Each declared name is remembered, so later NAME(...) references parse as array elements
+func (p *BasicParser) parseDimStatement() (ast.Statement, error) {
+	if p.curToken.Type != lexer.DIM {
+		return nil, fmt.Errorf("expected DIM")
+	}
+	
+	p.nextToken() // consume DIM
+	
+	var arrays []ast.ArrayDeclaration
+	for {
+		if p.curToken.Type != lexer.IDENTIFIER {
+			return nil, fmt.Errorf("expected array name in DIM statement, found '%s' (%s) at line %d, column %d",
+				p.curToken.Value, p.curToken.Type.String(), p.curToken.Line, p.curToken.Column)
+		}
+		name := p.curToken.Value
+		p.nextToken() // consume name
+		
+		sizes, err := p.parseArrayIndexes(name)
+		if err != nil {
+			return nil, err
+		}
+		arrays = append(arrays, ast.ArrayDeclaration{Name: name, Sizes: sizes})
+		p.arrays[ast.NormalizeVariableName(name)] = true
+		
+		if p.curToken.Type != lexer.COMMA {
+			break
+		}
+		p.nextToken() // consume comma
+	}
+	
+	return ast.NewDimStatement(arrays), nil
+}
+
+// ParseExpression parses an expression (placeholder implementation)
+func (p *BasicParser) ParseExpression() (ast.Expression, error) {
+	return p.parseOr()
+}
+
+// ParseStandaloneExpression parses an expression that must make up the whole input
+// Used where an expression is entered on its own, such as the interactive CALC command
+func (p *BasicParser) ParseStandaloneExpression() (ast.Expression, error) {
+	expr, err := p.ParseExpression()
+	if err != nil {
+		return nil, err
+	}
+	
+	if p.curToken.Type != lexer.EOF {
+		return nil, fmt.Errorf("unexpected '%s' (%s) after expression at line %d, column %d", 
+			p.curToken.Value, p.curToken.Type, p.curToken.Line, p.curToken.Column)
+	}
+	
+	return expr, nil
+}
+
+// parseOr parses OR expressions (lowest precedence)
+func (p *BasicParser) parseOr() (ast.Expression, error) {
+	return p.parseLogical(p.parseAnd, lexer.OR, ast.OpOr)
+}
+
+// parseAnd parses AND expressions, which bind tighter than OR
+func (p *BasicParser) parseAnd() (ast.Expression, error) {
+	return p.parseLogical(p.parseNot, lexer.AND, ast.OpAnd)
+}
+
+// parseLogical parses a left-associative chain of one logical operator
+func (p *BasicParser) parseLogical(
+	parseNext func() (ast.Expression, error),
+	tokenType lexer.TokenType,
+	operator string,
+) (ast.Expression, error) {
+	left, err := parseNext()
+	if err != nil {
+		return nil, err
+	}
+	
+	for p.curToken.Type == tokenType {
+		p.nextToken() // consume operator
+		
+		if p.curToken.Type == lexer.EOF {
+			return nil, p.errorAt("unexpected end of input after operator %s", operator)
+		}
+		
+		right, err := parseNext()
+		if err != nil {
+			return nil, err
+		}
+		
+		left = ast.NewLogicalExpression(left, operator, right)
+	}
+	
+	return left, nil
+}
+
+// parseNot parses unary NOT, which binds tighter than AND and OR but looser than comparisons,
+// so NOT A = B negates the whole comparison
+func (p *BasicParser) parseNot() (ast.Expression, error) {
+	if p.curToken.Type != lexer.NOT {
+		return p.parseComparison()
+	}
+	
+	p.nextToken() // consume NOT
+	
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	
+	return ast.NewNotExpression(operand), nil
+}
+
+// parseComparison parses comparison expressions
+func (p *BasicParser) parseComparison() (ast.Expression, error) {
+	left, err := p.parseArithmetic()
+	if err != nil {
+		return nil, err
+	}
+	
+	// Check for comparison operators
+	if p.isComparisonOperator(p.curToken.Type) {
+		operator := p.getComparisonOperator(p.curToken.Type)
+		p.nextToken() // consume operator
+		
+		right, err := p.parseArithmetic()
+		if err != nil {
+			return nil, err
+		}
+		
+		return ast.NewComparisonExpression(left, operator, right), nil
+	}
+	
+	return left, nil
+}
+
+// parseArithmetic parses arithmetic expressions with precedence
+func (p *BasicParser) parseArithmetic() (ast.Expression, error) {
+	return p.parseAddition()
+}
+
+// parseAddition parses addition and subtraction (lowest precedence)
+func (p *BasicParser) parseAddition() (ast.Expression, error) {
+	return p.parseBinaryExpression(
+		p.parseMultiplication,
+		[]lexer.TokenType{lexer.PLUS, lexer.MINUS},
+	)
 }
 
-// parseMultiplication parses multiplication and division (medium precedence)
+// parseMultiplication parses multiplication, division, integer division and MOD (medium precedence)
 func (p *BasicParser) parseMultiplication() (ast.Expression, error) {
 	return p.parseBinaryExpression(
 		p.parsePower,
-		[]lexer.TokenType{lexer.MULTIPLY, lexer.DIVIDE},
+		[]lexer.TokenType{lexer.MULTIPLY, lexer.DIVIDE, lexer.BACKSLASH, lexer.MOD},
 	)
 }
 
@@ -627,7 +1527,7 @@ func (p *BasicParser) parsePower() (ast.Expression, error) {
 		
 		// Check for end of input
 		if p.curToken.Type == lexer.EOF {
-			return nil, fmt.Errorf("unexpected end of input after operator %s", operator)
+			return nil, p.errorAt("unexpected end of input after operator %s", operator)
 		}
 		
 		// Power is right-associative
@@ -657,9 +1557,11 @@ func (p *BasicParser) parsePrimary() (ast.Expression, error) {
 		// Handle unary minus
 		return p.parseUnaryMinus()
 	case lexer.EOF:
-		return nil, fmt.Errorf("unexpected end of input")
+		return nil, p.errorAt("unexpected end of input")
+	case lexer.LINENUMBER:
+		return nil, p.errorAt("missing expression")
 	default:
-		return nil, fmt.Errorf("unexpected token in expression: %s", p.curToken.Value)
+		return nil, p.errorAt("unexpected token in expression: %s", p.curToken.Value)
 	}
 }
 
@@ -689,7 +1591,7 @@ func (p *BasicParser) parseNumberLiteral() (ast.Expression, error) {
 	
 	value, err := strconv.ParseFloat(p.curToken.Value, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid number: %s", p.curToken.Value)
+		return nil, p.errorAt("invalid number: %s", p.curToken.Value)
 	}
 	
 	p.nextToken() // consume number
@@ -709,24 +1611,58 @@ func (p *BasicParser) parseStringLiteral() (ast.Expression, error) {
 	return ast.NewLiteralExpression(runtime.NewStringValue(value)), nil
 }
 
-// parseIdentifierOrFunction parses a variable reference or function call
+// parseIdentifierOrFunction parses a variable reference, array element or function call
 func (p *BasicParser) parseIdentifierOrFunction() (ast.Expression, error) {
 	if p.curToken.Type != lexer.IDENTIFIER {
 		return nil, fmt.Errorf("expected identifier")
 	}
 	
 	name := p.curToken.Value
+	line, column := p.curToken.Line, p.curToken.Column
 	p.nextToken() // consume identifier
 	
+	// SPRINT$ takes a PRINT-style list, so it cannot be parsed as an ordinary function call
+	if strings.ToUpper(name) == "SPRINT$" {
+		return p.parseSprintExpression()
+	}
+	
+	// An array declared by DIM is indexed with parentheses, just like a function call;
+	// with empty parentheses it stands for all of its elements, which only PRINT can list
+	if p.curToken.Type == lexer.LPAREN && p.peekToken.Type == lexer.RPAREN && p.arrays[ast.NormalizeVariableName(name)] {
+		p.nextToken() // consume (
+		p.nextToken() // consume )
+		return ast.NewArrayListExpression(name), nil
+	}
+	if p.curToken.Type == lexer.LPAREN && p.arrays[ast.NormalizeVariableName(name)] {
+		indexes, err := p.parseArrayIndexes(name)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewArrayElementExpression(name, indexes), nil
+	}
+	
+	// TAB and SPC position the print head, which only PRINT lists have
+	if p.curToken.Type == lexer.LPAREN && (strings.ToUpper(name) == "TAB" || strings.ToUpper(name) == "SPC") {
+		return nil, fmt.Errorf("%s can only be used in PRINT at line %d, column %d", strings.ToUpper(name), line, column)
+	}
+	
 	// Check if this is a function call with parentheses (even for unknown functions)
 	if p.curToken.Type == lexer.LPAREN {
 		return p.parseFunctionCall(name)
 	}
 	
+	// A string function taking arguments must be given them in parentheses, or the bare
+	// name would be taken as a call without arguments that only fails when it runs
+	if strings.HasSuffix(name, "$") {
+		if function := p.functions.Lookup(name); function != nil && function.ArgCount() != 0 {
+			return nil, fmt.Errorf("function %s needs its arguments in parentheses at line %d, column %d", strings.ToUpper(name), line, column)
+		}
+	}
+	
 	// Check if this is a known function without parentheses (like RND)
-	if ast.IsFunctionRegistered(name) {
+	if p.functions.Lookup(name) != nil {
 		// Create function call with no arguments
-		return ast.NewFunctionCallExpression(name, []ast.Expression{}), nil
+		return p.newFunctionCall(name, []ast.Expression{}), nil
 	}
 	
 	// It's a variable reference
@@ -746,7 +1682,7 @@ func (p *BasicParser) parseFunctionCall(name string) (ast.Expression, error) {
 	// Handle empty argument list
 	if p.curToken.Type == lexer.RPAREN {
 		p.nextToken() // consume )
-		return ast.NewFunctionCallExpression(name, args), nil
+		return p.newFunctionCall(name, args), nil
 	}
 	
 	// Parse arguments
@@ -767,12 +1703,54 @@ func (p *BasicParser) parseFunctionCall(name string) (ast.Expression, error) {
 	
 	// Expect closing parenthesis
 	if p.curToken.Type != lexer.RPAREN {
-		return nil, fmt.Errorf("expected ) in function call")
+		return nil, p.errorAt("expected ) in function call")
+	}
+	
+	p.nextToken() // consume )
+	
+	return p.newFunctionCall(name, args), nil
+}
+
+// newFunctionCall creates a call to the named function, resolving a built-in one now so
+// that running the program never looks it up and leaves the parsed tree unchanged
+func (p *BasicParser) newFunctionCall(name string, args []ast.Expression) *ast.FunctionCallExpression {
+	call := ast.NewFunctionCallExpression(name, args)
+	call.Function = p.functions.Lookup(name)
+	return call
+}
+
+// parseSprintExpression parses the parenthesized PRINT list of SPRINT$
+func (p *BasicParser) parseSprintExpression() (ast.Expression, error) {
+	if p.curToken.Type != lexer.LPAREN {
+		return nil, p.errorAt("expected ( after SPRINT$")
+	}
+	
+	p.nextToken() // consume (
+	
+	expressions := []ast.Expression{}
+	var separators []rune
+	for p.curToken.Type != lexer.RPAREN {
+		expr, err := p.parsePrintItem()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing SPRINT$ argument: %w", err)
+		}
+		expressions = append(expressions, expr)
+		
+		if p.curToken.Type != lexer.COMMA && p.curToken.Type != lexer.SEMICOLON {
+			break
+		}
+		separators = append(separators, printSeparator(p.curToken))
+		p.nextToken() // consume separator
+	}
+	
+	// Expect closing parenthesis
+	if p.curToken.Type != lexer.RPAREN {
+		return nil, p.errorAt("expected ) after SPRINT$ arguments")
 	}
 	
 	p.nextToken() // consume )
 	
-	return ast.NewFunctionCallExpression(name, args), nil
+	return ast.NewSprintExpression(expressions, separators), nil
 }
 
 // parseParentheses parses parenthesized expressions
@@ -789,7 +1767,7 @@ func (p *BasicParser) parseParentheses() (ast.Expression, error) {
 	}
 	
 	if p.curToken.Type != lexer.RPAREN {
-		return nil, fmt.Errorf("expected )")
+		return nil, p.errorAt("expected )")
 	}
 	
 	p.nextToken() // consume )
@@ -840,8 +1818,12 @@ func (p *BasicParser) getArithmeticOperator(tokenType lexer.TokenType) string {
 		return "*"
 	case lexer.DIVIDE:
 		return "/"
+	case lexer.BACKSLASH:
+		return ast.OpIntDivide
 	case lexer.POWER:
 		return "^"
+	case lexer.MOD:
+		return ast.OpModulo
 	default:
 		return ""
 	}
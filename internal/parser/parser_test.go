@@ -125,6 +125,26 @@ func TestParser_ParseStatement_AssignmentExpression(t *testing.T) {
 	assert.Equal(t, 20.0, value.NumValue) // 10 + (5 * 2) = 20
 }
 
+func TestParser_ParseStatement_Let(t *testing.T) {
+	withLet, err := createParser("LET A = 3").ParseStatement()
+	require.NoError(t, err)
+	implicit, err := createParser("A = 3").ParseStatement()
+	require.NoError(t, err)
+	assert.Equal(t, implicit, withLet)
+	
+	withLet, err = createParser("let B$(2) = \"x\"").ParseStatement()
+	require.NoError(t, err)
+	assert.IsType(t, &ast.ArrayAssignmentStatement{}, withLet)
+	
+	program, err := createParser("10 LET X = 1\n20 IF X = 1 THEN LET Y = 2 ELSE LET Y = 3").ParseProgram()
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 20}, program.Order)
+	assert.IsType(t, &ast.AssignmentStatement{}, program.Lines[20].(*ast.IfStatement).ThenStatement)
+	
+	_, err = createParser("LET = 3").ParseStatement()
+	assert.EqualError(t, err, "expected variable name after LET, found '=' (ASSIGN) at line 1, column 5")
+}
+
 // Test ParseStatement method - PRINT statements
 
 func TestParser_ParseStatement_PrintSingle(t *testing.T) {
@@ -166,7 +186,61 @@ func TestParser_ParseStatement_PrintMultiple(t *testing.T) {
 	
 	err = printStmt.Execute(env)
 	require.NoError(t, err)
-	assert.Equal(t, "Value: 42 End", output.GetLastOutput())
+	assert.Equal(t, "Value:         42           End", output.GetLastOutput())
+}
+
+func TestParser_ParseStatement_PrintSeparators(t *testing.T) {
+	stmt, err := createParser(`PRINT "A"; "B", "C"`).ParseStatement()
+	require.NoError(t, err)
+	
+	printStmt, ok := stmt.(*ast.PrintStatement)
+	require.True(t, ok, "Expected PrintStatement")
+	assert.Equal(t, []rune{';', ','}, printStmt.Separators)
+	assert.False(t, printStmt.TrailingSemicolon)
+	
+	stmt, err = createParser(`PRINT "A"; X;`).ParseStatement()
+	require.NoError(t, err)
+	printStmt = stmt.(*ast.PrintStatement)
+	assert.Len(t, printStmt.Expressions, 2)
+	assert.Equal(t, []rune{';'}, printStmt.Separators)
+	assert.True(t, printStmt.TrailingSemicolon)
+	
+	stmt, err = createParser(`PRINT "A",`).ParseStatement()
+	require.NoError(t, err)
+	assert.False(t, stmt.(*ast.PrintStatement).TrailingSemicolon, "a trailing comma still ends the line")
+	
+	// The next line may start with an implicit assignment after a trailing semicolon
+	program, err := createParser("10 PRINT X;\n20 X = 1").ParseProgram()
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 20}, program.Order)
+	assert.True(t, program.Lines[10].(*ast.PrintStatement).TrailingSemicolon)
+}
+
+func TestParser_ParseStatement_PrintTabAndSpc(t *testing.T) {
+	stmt, err := createParser(`PRINT TAB(10); "X"; spc(N + 1); "Y"`).ParseStatement()
+	require.NoError(t, err)
+	
+	printStmt, ok := stmt.(*ast.PrintStatement)
+	require.True(t, ok, "Expected PrintStatement")
+	require.Len(t, printStmt.Expressions, 4)
+	assert.Equal(t, "TAB(10)", ast.FormatExpression(printStmt.Expressions[0]))
+	assert.Equal(t, "SPC((N + 1))", ast.FormatExpression(printStmt.Expressions[2]))
+	
+	expr, err := createParser(`SPRINT$("A"; TAB(4); "B")`).ParseExpression()
+	require.NoError(t, err)
+	value, err := expr.Evaluate(runtime.NewEnvironment())
+	require.NoError(t, err)
+	assert.Equal(t, "A  B", value.StrValue)
+	
+	program, err := createParser("10 DIM TAB(3)\n20 PRINT TAB(1)").ParseProgram()
+	require.NoError(t, err)
+	assert.IsType(t, &ast.ArrayElementExpression{}, program.Lines[20].(*ast.PrintStatement).Expressions[0], "an array named TAB is still an array")
+	
+	_, err = createParser("X = TAB(3)").ParseStatement()
+	assert.ErrorContains(t, err, "TAB can only be used in PRINT")
+	
+	_, err = createParser("PRINT SPC(3").ParseStatement()
+	assert.ErrorContains(t, err, "expected ) after SPC argument")
 }
 
 func TestParser_ParseStatement_PrintVariable(t *testing.T) {
@@ -188,7 +262,7 @@ func TestParser_ParseStatement_PrintVariable(t *testing.T) {
 	
 	err = printStmt.Execute(env)
 	require.NoError(t, err)
-	assert.Equal(t, "123", output.GetLastOutput())
+	assert.Equal(t, " 123", output.GetLastOutput())
 }
 
 func TestParser_ParseStatement_PrintEmpty(t *testing.T) {
@@ -374,6 +448,69 @@ func TestParser_ParseStatement_IfThenAssignment(t *testing.T) {
 	assert.Equal(t, "C", assignStmt.Variable)
 }
 
+func TestParser_ParseStatement_IfThenElse(t *testing.T) {
+	t.Run("ELSE branch is parsed", func(t *testing.T) {
+		stmt, err := createParser(`IF X > 5 THEN PRINT "big" ELSE PRINT "small"`).ParseStatement()
+		require.NoError(t, err)
+
+		ifStmt, ok := stmt.(*ast.IfStatement)
+		require.True(t, ok, "Expected IfStatement")
+		assert.IsType(t, &ast.PrintStatement{}, ifStmt.ThenStatement)
+		assert.IsType(t, &ast.PrintStatement{}, ifStmt.ElseStatement)
+	})
+
+	t.Run("GOTO in both branches keeps both targets", func(t *testing.T) {
+		stmt, err := createParser("IF C THEN GOTO 10 ELSE GOTO 20").ParseStatement()
+		require.NoError(t, err)
+
+		ifStmt := stmt.(*ast.IfStatement)
+		assert.Equal(t, 10, ifStmt.ThenStatement.(*ast.GotoStatement).LineNumber)
+		assert.Equal(t, 20, ifStmt.ElseStatement.(*ast.GotoStatement).LineNumber)
+	})
+
+	t.Run("IF GOTO form accepts ELSE", func(t *testing.T) {
+		stmt, err := createParser("IF C GOTO 10 ELSE X = 1").ParseStatement()
+		require.NoError(t, err)
+
+		ifStmt := stmt.(*ast.IfStatement)
+		assert.IsType(t, &ast.GotoStatement{}, ifStmt.ThenStatement)
+		assert.IsType(t, &ast.AssignmentStatement{}, ifStmt.ElseStatement)
+	})
+
+	t.Run("colons group statements in each branch", func(t *testing.T) {
+		stmt, err := createParser("IF C THEN A = 1 : B = 2 ELSE A = 3 : B = 4 : PRINT").ParseStatement()
+		require.NoError(t, err)
+
+		ifStmt := stmt.(*ast.IfStatement)
+		assert.Len(t, ifStmt.ThenStatement.(*ast.CompoundStatement).Statements, 2)
+		assert.Len(t, ifStmt.ElseStatement.(*ast.CompoundStatement).Statements, 3)
+	})
+
+	t.Run("ELSE belongs to the nearest IF", func(t *testing.T) {
+		stmt, err := createParser(`IF A THEN IF B THEN PRINT "1" ELSE PRINT "2"`).ParseStatement()
+		require.NoError(t, err)
+
+		outer := stmt.(*ast.IfStatement)
+		assert.Nil(t, outer.ElseStatement)
+		inner, ok := outer.ThenStatement.(*ast.IfStatement)
+		require.True(t, ok, "Expected nested IfStatement")
+		assert.NotNil(t, inner.ElseStatement)
+	})
+
+	t.Run("GOTO in ELSE is linked to the program", func(t *testing.T) {
+		program, err := createParser("10 IF C THEN GOTO 20 ELSE GOTO 30\n20 END\n30 END").ParseProgram()
+		require.NoError(t, err)
+
+		ifStmt := program.Lines[10].(*ast.IfStatement)
+		assert.Same(t, program, ifStmt.ElseStatement.(*ast.GotoStatement).Program)
+	})
+
+	t.Run("ELSE without a statement is an error", func(t *testing.T) {
+		_, err := createParser("IF C THEN X = 1 ELSE").ParseStatement()
+		assert.Error(t, err)
+	})
+}
+
 // Test ParseStatement method - FOR statements
 
 func TestParser_ParseStatement_ForBasic(t *testing.T) {
@@ -608,6 +745,10 @@ func TestParser_ParseExpression_OperatorPrecedence(t *testing.T) {
 		{"Complex precedence", "2 + 3 * 4 ^ 2 - 1", 49.0},        // 2 + (3 * (4 ^ 2)) - 1 = 49
 		{"Left associativity", "10 - 5 - 2", 3.0},                 // (10 - 5) - 2 = 3
 		{"Right associativity power", "2 ^ 3 ^ 2", 512.0},         // 2 ^ (3 ^ 2) = 512
+		{"MOD and addition", "2 + 10 MOD 4", 4.0},                 // 2 + (10 MOD 4) = 4
+		{"MOD left associativity", "2 * 7 MOD 4", 2.0},            // (2 * 7) MOD 4 = 2
+		{"Integer division and addition", "7 \\ 2 + 1", 4.0},      // (7 \ 2) + 1 = 4
+		{"Negative integer division", "-7 \\ 2", -3.0},            // truncated toward zero
 	}
 	
 	for _, tc := range testCases {
@@ -641,6 +782,7 @@ func TestParser_ParseExpression_ArithmeticOperators(t *testing.T) {
 		{"Power", "2 ^ 3", 8.0},
 		{"Negative numbers", "-5 + 3", -2.0},
 		{"Decimal numbers", "3.5 + 2.1", 5.6},
+		{"Scientific notation", "1.5E3 + 2e-1", 1500.2},
 	}
 	
 	for _, tc := range testCases {
@@ -661,6 +803,38 @@ func TestParser_ParseExpression_ArithmeticOperators(t *testing.T) {
 	}
 }
 
+func TestParser_ParseExpression_HexAndBinaryLiterals(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected float64
+	}{
+		{"Hexadecimal", "&HFF", 255.0},
+		{"Lower case hexadecimal", "&hff", 255.0},
+		{"Binary", "&B101", 5.0},
+		{"In arithmetic", "&H10 + &B1", 17.0},
+		{"Hexadecimal compared with decimal", "&HFF = 255", -1.0},
+		{"Binary compared with decimal", "&B101 = 5", -1.0},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := createParser(tc.source).ParseExpression()
+			require.NoError(t, err)
+			
+			value, err := expr.Evaluate(runtime.NewEnvironment())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value.NumValue)
+		})
+	}
+	
+	_, err := createParser("&HFG").ParseExpression()
+	assert.ErrorContains(t, err, "invalid hexadecimal number &HFG")
+	
+	_, err = createParser("&B102").ParseExpression()
+	assert.ErrorContains(t, err, "invalid binary number &B102")
+}
+
 func TestParser_ParseExpression_ComparisonOperators(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -701,6 +875,48 @@ func TestParser_ParseExpression_ComparisonOperators(t *testing.T) {
 	}
 }
 
+func TestParser_ParseExpression_LogicalOperators(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected float64 // -1 for true, 0 for false in BASIC
+	}{
+		{"AND of comparisons", "A > 0 AND B > 0", -1.0},
+		{"AND with a false side", "A > 0 AND B < 0", 0.0},
+		{"OR outside the range", "A < 1 OR A > 10", 0.0},
+		{"OR inside one side", "A < 1 OR A > 4", -1.0},
+		{"NOT negates the whole comparison", "NOT A = B", 0.0},
+		{"NOT binds tighter than AND", "NOT A = 3 AND B = 5", -1.0},
+		{"AND binds tighter than OR", "1 OR 1 AND 0", -1.0},
+		{"Double NOT", "NOT NOT 7", -1.0},
+		{"Lowercase keywords", "a = 5 and not b = 3", -1.0},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := createParser(tc.source)
+			
+			expr, err := parser.ParseExpression()
+			require.NoError(t, err)
+			require.NotNil(t, expr)
+			
+			// Test evaluation with A = 5 and B = 5
+			env := runtime.NewEnvironment()
+			env.SetVariable("A", runtime.NewNumericValue(5))
+			env.SetVariable("B", runtime.NewNumericValue(5))
+			value, err := expr.Evaluate(env)
+			require.NoError(t, err)
+			assert.Equal(t, runtime.NumericValue, value.Type)
+			assert.Equal(t, tc.expected, value.NumValue)
+		})
+	}
+	
+	t.Run("missing operand is an error", func(t *testing.T) {
+		_, err := createParser("A > 0 AND").ParseExpression()
+		assert.Error(t, err)
+	})
+}
+
 func TestParser_ParseExpression_StringComparisons(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -837,6 +1053,7 @@ func TestParser_ParseExpression_FunctionCallsWithArgs(t *testing.T) {
 			require.True(t, ok, "Expected FunctionCallExpression")
 			assert.Equal(t, tc.funcName, funcCall.Name)
 			assert.Len(t, funcCall.Args, tc.argCount)
+			assert.Equal(t, tc.funcName, funcCall.Function.Name(), "the parser resolves the built-in function")
 			
 			// Test evaluation for numeric functions
 			if tc.funcName != "MID$" {
@@ -850,6 +1067,45 @@ func TestParser_ParseExpression_FunctionCallsWithArgs(t *testing.T) {
 	}
 }
 
+func TestParser_ParseExpression_StringFunctionCallSpacing(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"unspaced", `MID$(A$, 2, 3)`, "ELL"},
+		{"space before parenthesis", `MID$ (A$, 2, 3)`, "ELL"},
+		{"spaces everywhere", `MID$ ( A$ , 2 , 3 )`, "ELL"},
+		{"nested with spaces", `LEFT$ (STR$ (12345), 3)`, "123"},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := createParser(tc.source).ParseExpression()
+			require.NoError(t, err)
+			
+			env := runtime.NewEnvironment()
+			env.SetVariable("A$", runtime.NewStringValue("HELLO"))
+			value, err := expr.Evaluate(env)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value.StrValue)
+		})
+	}
+	
+	t.Run("string function without parentheses", func(t *testing.T) {
+		_, err := createParser(`X$ = STR$ + "A"`).ParseStatement()
+		assert.ErrorContains(t, err, "function STR$ needs its arguments in parentheses at line 1, column 6")
+		
+		_, err = createParser(`PRINT mid$`).ParseStatement()
+		assert.ErrorContains(t, err, "function MID$ needs its arguments in parentheses")
+	})
+	
+	t.Run("string variables are unaffected", func(t *testing.T) {
+		_, err := createParser(`PRINT NAME$ + TITLE$`).ParseStatement()
+		require.NoError(t, err)
+	})
+}
+
 func TestParser_ParseExpression_FunctionCallsInExpressions(t *testing.T) {
 	parser := createParser("ABS(-5) + INT(3.7)")
 	
@@ -877,7 +1133,7 @@ func TestParser_ParseExpression_NestedFunctionCalls(t *testing.T) {
 	value, err := expr.Evaluate(env)
 	require.NoError(t, err)
 	assert.Equal(t, runtime.NumericValue, value.Type)
-	assert.Equal(t, 3.0, value.NumValue) // ABS(INT(-3.7)) = ABS(-3) = 3
+	assert.Equal(t, 4.0, value.NumValue) // ABS(INT(-3.7)) = ABS(-4) = 4
 }
 
 // Test ParseExpression method - Variables
@@ -1497,4 +1753,875 @@ func TestParser_ParseProgram_NonSequentialLines(t *testing.T) {
 		_, exists := program.Lines[lineNum]
 		assert.True(t, exists, "Line %d should exist", lineNum)
 	}
-}
\ No newline at end of file
+}
+
+func TestParser_ParseExpression_Sprint(t *testing.T) {
+	tests := []struct {
+		name      string
+		printList string
+		expected  string
+	}{
+		{name: "string and number", printList: `"A", 5`, expected: "A              5"},
+		{name: "semicolon separator", printList: `"X"; 1 + 2`, expected: "X 3"},
+		{name: "mixed separators", printList: `"A"; "B", "C"`, expected: "AB            C"},
+		{name: "single value", printList: `42`, expected: " 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := runtime.NewEnvironment()
+
+			// Capture the line PRINT emits for the same list
+			output := &MockOutputWriter{}
+			printStmt, err := createParser("PRINT " + tt.printList).ParseStatement()
+			require.NoError(t, err)
+			printStmt.(*ast.PrintStatement).Output = output
+			require.NoError(t, printStmt.Execute(env))
+
+			expr, err := createParser("SPRINT$(" + tt.printList + ")").ParseExpression()
+			require.NoError(t, err)
+			require.IsType(t, &ast.SprintExpression{}, expr)
+
+			value, err := expr.Evaluate(env)
+			require.NoError(t, err)
+			assert.Equal(t, runtime.StringValue, value.Type)
+			assert.Equal(t, output.GetLastOutput(), value.StrValue)
+			assert.Equal(t, tt.expected, value.StrValue)
+		})
+	}
+
+	t.Run("empty list", func(t *testing.T) {
+		expr, err := createParser("SPRINT$()").ParseExpression()
+		require.NoError(t, err)
+
+		value, err := expr.Evaluate(runtime.NewEnvironment())
+		require.NoError(t, err)
+		assert.Equal(t, "", value.StrValue)
+	})
+
+	t.Run("used in assignment", func(t *testing.T) {
+		stmt, err := createParser(`A$ = SPRINT$("Total:", 2 * 21)`).ParseStatement()
+		require.NoError(t, err)
+
+		env := runtime.NewEnvironment()
+		require.NoError(t, stmt.Execute(env))
+		assert.Equal(t, "Total:         42", env.GetVariable("A$").StrValue)
+	})
+
+	t.Run("missing closing parenthesis", func(t *testing.T) {
+		_, err := createParser(`SPRINT$("A", 5`).ParseExpression()
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_ParseStatement_Pause(t *testing.T) {
+	t.Run("bare PAUSE", func(t *testing.T) {
+		stmt, err := createParser("PAUSE").ParseStatement()
+		require.NoError(t, err)
+
+		pause, ok := stmt.(*ast.PauseStatement)
+		require.True(t, ok, "Expected PauseStatement")
+		assert.Equal(t, "", pause.Prompt)
+	})
+
+	t.Run("PAUSE with custom prompt", func(t *testing.T) {
+		stmt, err := createParser(`PAUSE "Press a key"`).ParseStatement()
+		require.NoError(t, err)
+
+		pause, ok := stmt.(*ast.PauseStatement)
+		require.True(t, ok, "Expected PauseStatement")
+		assert.Equal(t, "Press a key", pause.Prompt)
+	})
+
+	t.Run("PAUSE with non-string argument", func(t *testing.T) {
+		_, err := createParser("PAUSE 5").ParseStatement()
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_ParseStandaloneExpression(t *testing.T) {
+	t.Run("whole input is an expression", func(t *testing.T) {
+		expr, err := createParser("2+3*4").ParseStandaloneExpression()
+		require.NoError(t, err)
+
+		value, err := expr.Evaluate(runtime.NewEnvironment())
+		require.NoError(t, err)
+		assert.Equal(t, 14.0, value.NumValue)
+	})
+
+	t.Run("trailing tokens are rejected", func(t *testing.T) {
+		_, err := createParser("2 3").ParseStandaloneExpression()
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_FormatExpression_FullyParenthesized(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"2+3*4", "(2 + (3 * 4))"},
+		{"(2+3)*4", "((2 + 3) * 4)"},
+		{"10 - 5 - 2", "((10 - 5) - 2)"},
+		{"2 ^ 3 ^ 2", "(2 ^ (3 ^ 2))"},
+		{"A + B * C > 10", "((A + (B * C)) > 10)"},
+		{"LEN(\"HI\") * 2", "(LEN(\"HI\") * 2)"},
+		{"7 MOD 3 + 1", "((7 MOD 3) + 1)"},
+		{"7 \\ 2 * 3", "((7 \\ 2) * 3)"},
+		{"NOT A = B", "(NOT (A = B))"},
+		{"A < 1 OR A > 10", "((A < 1) OR (A > 10))"},
+		{"A OR B AND NOT C", "(A OR (B AND (NOT C)))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := createParser(tt.source).ParseStandaloneExpression()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ast.FormatExpression(expr))
+		})
+	}
+}
+
+func TestParser_ParseStatement_Assert(t *testing.T) {
+	t.Run("ASSERT records its line number", func(t *testing.T) {
+		program, err := createParser("10 A = 1\n20 ASSERT A = 1").ParseProgram()
+		require.NoError(t, err)
+
+		assertStmt, ok := program.Lines[20].(*ast.AssertStatement)
+		require.True(t, ok, "Expected AssertStatement")
+		assert.Equal(t, 20, assertStmt.LineNum)
+		assert.IsType(t, &ast.ComparisonExpression{}, assertStmt.Condition)
+	})
+
+	t.Run("ASSERT without condition is an error", func(t *testing.T) {
+		_, err := createParser("ASSERT").ParseStatement()
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_ParseProgram_ColonSeparatedStatements(t *testing.T) {
+	t.Run("statements on one line form a compound statement", func(t *testing.T) {
+		program, err := createParser("10 A = 1 : B = 2 : PRINT A\n20 END").ParseProgram()
+		require.NoError(t, err)
+
+		compound, ok := program.Lines[10].(*ast.CompoundStatement)
+		require.True(t, ok, "Expected CompoundStatement")
+		require.Len(t, compound.Statements, 3)
+		assert.IsType(t, &ast.PrintStatement{}, compound.Statements[2])
+		assert.IsType(t, &ast.EndStatement{}, program.Lines[20])
+	})
+
+	t.Run("the rest of the line belongs to THEN", func(t *testing.T) {
+		program, err := createParser("10 IF X > 0 THEN A = 1 : B = 2 : PRINT A\n20 PRINT B").ParseProgram()
+		require.NoError(t, err)
+
+		ifStmt, ok := program.Lines[10].(*ast.IfStatement)
+		require.True(t, ok, "Expected IfStatement")
+		compound, ok := ifStmt.ThenStatement.(*ast.CompoundStatement)
+		require.True(t, ok, "Expected CompoundStatement in THEN branch")
+		assert.Len(t, compound.Statements, 3)
+		assert.IsType(t, &ast.PrintStatement{}, program.Lines[20])
+	})
+
+	t.Run("trailing colon ends the line", func(t *testing.T) {
+		program, err := createParser("10 A = 1 :\n20 END").ParseProgram()
+		require.NoError(t, err)
+
+		assert.IsType(t, &ast.AssignmentStatement{}, program.Lines[10])
+		assert.Equal(t, []int{10, 20}, program.Order)
+	})
+
+	t.Run("colon inside a string does not split the statement", func(t *testing.T) {
+		program, err := createParser(`10 PRINT "a:b"`).ParseProgram()
+		require.NoError(t, err)
+
+		printStmt, ok := program.Lines[10].(*ast.PrintStatement)
+		require.True(t, ok, "Expected a single PrintStatement")
+		require.Len(t, printStmt.Expressions, 1)
+		assert.Equal(t, "a:b", printStmt.Expressions[0].(*ast.LiteralExpression).Value.StrValue)
+	})
+
+	t.Run("top-level colons separate statements", func(t *testing.T) {
+		program, err := createParser(`10 PRINT A : PRINT "x:y"; B : PRINT "c"; : PRINT`).ParseProgram()
+		require.NoError(t, err)
+
+		compound, ok := program.Lines[10].(*ast.CompoundStatement)
+		require.True(t, ok, "Expected CompoundStatement")
+		require.Len(t, compound.Statements, 4)
+		second, ok := compound.Statements[1].(*ast.PrintStatement)
+		require.True(t, ok, "Expected PrintStatement")
+		assert.Len(t, second.Expressions, 2)
+	})
+
+	t.Run("colons in function arguments and parentheses stay in the expression", func(t *testing.T) {
+		program, err := createParser(`10 A$ = MID$("12:34", 3, 1) : B = (LEN(":") + 1) * 2`).ParseProgram()
+		require.NoError(t, err)
+
+		compound, ok := program.Lines[10].(*ast.CompoundStatement)
+		require.True(t, ok, "Expected CompoundStatement")
+		require.Len(t, compound.Statements, 2)
+		call, ok := compound.Statements[0].(*ast.AssignmentStatement).Expression.(*ast.FunctionCallExpression)
+		require.True(t, ok, "Expected FunctionCallExpression")
+		assert.Len(t, call.Args, 3)
+	})
+
+	t.Run("bare colon inside parentheses is an error, not a separator", func(t *testing.T) {
+		_, err := createParser(`10 PRINT (1 : 2)`).ParseProgram()
+		assert.Error(t, err)
+
+		_, err = createParser(`10 PRINT LEN("a" : "b")`).ParseProgram()
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_ParseEndStatement_ExitCode(t *testing.T) {
+	t.Run("END without exit code", func(t *testing.T) {
+		stmt, err := createParser("END").ParseStatement()
+		require.NoError(t, err)
+
+		endStmt, ok := stmt.(*ast.EndStatement)
+		require.True(t, ok, "Expected EndStatement")
+		assert.Nil(t, endStmt.ExitCode)
+	})
+
+	t.Run("END with exit code", func(t *testing.T) {
+		stmt, err := createParser("END 3").ParseStatement()
+		require.NoError(t, err)
+
+		endStmt, ok := stmt.(*ast.EndStatement)
+		require.True(t, ok, "Expected EndStatement")
+		assert.IsType(t, &ast.LiteralExpression{}, endStmt.ExitCode)
+	})
+}
+
+func TestParser_ParseStopStatement(t *testing.T) {
+	program, err := createParser("10 IF X THEN STOP\n20 STOP\n30 PRINT 1").ParseProgram()
+	require.NoError(t, err)
+
+	ifStmt, ok := program.Lines[10].(*ast.IfStatement)
+	require.True(t, ok, "Expected IfStatement")
+	assert.IsType(t, &ast.StopStatement{}, ifStmt.ThenStatement)
+	assert.IsType(t, &ast.StopStatement{}, program.Lines[20])
+	assert.IsType(t, &ast.PrintStatement{}, program.Lines[30])
+}
+
+func TestParser_ParseStatement_Shell(t *testing.T) {
+	t.Run("SHELL with command only", func(t *testing.T) {
+		stmt, err := createParser(`SHELL "ls"`).ParseStatement()
+		require.NoError(t, err)
+
+		shell, ok := stmt.(*ast.ShellStatement)
+		require.True(t, ok, "Expected ShellStatement")
+		assert.Equal(t, "", shell.Variable)
+	})
+
+	t.Run("SHELL capturing output", func(t *testing.T) {
+		stmt, err := createParser(`SHELL "ls", OUT$`).ParseStatement()
+		require.NoError(t, err)
+
+		shell, ok := stmt.(*ast.ShellStatement)
+		require.True(t, ok, "Expected ShellStatement")
+		assert.Equal(t, "OUT$", shell.Variable)
+	})
+
+	t.Run("capture needs a string variable", func(t *testing.T) {
+		_, err := createParser(`SHELL "ls", N`).ParseStatement()
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_ParseInputStatement_Range(t *testing.T) {
+	t.Run("INPUT with RANGE", func(t *testing.T) {
+		stmt, err := createParser(`INPUT "Pick"; N RANGE 1 TO 10`).ParseStatement()
+		require.NoError(t, err)
+
+		input, ok := stmt.(*ast.InputStatement)
+		require.True(t, ok, "Expected InputStatement")
+		assert.Equal(t, "N", input.Variable)
+		require.NotNil(t, input.Range)
+		assert.IsType(t, &ast.LiteralExpression{}, input.Range.Low)
+		assert.IsType(t, &ast.LiteralExpression{}, input.Range.High)
+	})
+
+	t.Run("RANGE needs TO", func(t *testing.T) {
+		_, err := createParser(`INPUT N RANGE 1, 10`).ParseStatement()
+		assert.Error(t, err)
+	})
+
+	t.Run("RANGE needs a numeric variable", func(t *testing.T) {
+		_, err := createParser(`INPUT N$ RANGE 1 TO 10`).ParseStatement()
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_ParseStatement_LineInput(t *testing.T) {
+	t.Run("LINE INPUT with prompt", func(t *testing.T) {
+		stmt, err := createParser(`LINE INPUT "Name? "; N$`).ParseStatement()
+		require.NoError(t, err)
+
+		input, ok := stmt.(*ast.InputStatement)
+		require.True(t, ok, "Expected InputStatement")
+		assert.True(t, input.Line)
+		assert.Equal(t, "N$", input.Variable)
+		assert.Equal(t, "Name? ", input.Prompt)
+	})
+
+	t.Run("plain INPUT is not a LINE INPUT", func(t *testing.T) {
+		stmt, err := createParser(`INPUT N$`).ParseStatement()
+		require.NoError(t, err)
+		assert.False(t, stmt.(*ast.InputStatement).Line)
+	})
+
+	t.Run("LINE needs INPUT", func(t *testing.T) {
+		_, err := createParser(`LINE N$`).ParseStatement()
+		assert.ErrorContains(t, err, "expected INPUT after LINE")
+	})
+
+	t.Run("LINE INPUT needs a string variable", func(t *testing.T) {
+		_, err := createParser(`LINE INPUT N`).ParseStatement()
+		assert.ErrorContains(t, err, "LINE INPUT requires a string variable, found N")
+	})
+}
+
+func TestParser_ParseProgram_LinksGotoStatements(t *testing.T) {
+	program, err := createParser("10 GOTO 30\n20 IF X > 0 THEN GOTO 10\n30 A = 1 : GOTO 20").ParseProgram()
+	require.NoError(t, err)
+
+	gotoStmt, ok := program.Lines[10].(*ast.GotoStatement)
+	require.True(t, ok, "Expected GotoStatement")
+	assert.Same(t, program, gotoStmt.Program)
+
+	ifStmt, ok := program.Lines[20].(*ast.IfStatement)
+	require.True(t, ok, "Expected IfStatement")
+	assert.Same(t, program, ifStmt.ThenStatement.(*ast.GotoStatement).Program, "GOTO after THEN is linked too")
+
+	compound, ok := program.Lines[30].(*ast.CompoundStatement)
+	require.True(t, ok, "Expected CompoundStatement")
+	assert.Same(t, program, compound.Statements[1].(*ast.GotoStatement).Program)
+}
+
+func TestParser_ParseRemStatement(t *testing.T) {
+	t.Run("comment text is kept verbatim", func(t *testing.T) {
+		stmt, err := createParser(`REM it's 100% "odd`).ParseStatement()
+		require.NoError(t, err)
+
+		rem, ok := stmt.(*ast.RemStatement)
+		require.True(t, ok, "Expected RemStatement")
+		assert.Equal(t, `it's 100% "odd`, rem.Comment)
+	})
+
+	t.Run("program of comments only", func(t *testing.T) {
+		program, err := createParser("10 REM first\n20 REM\n30 X = 1").ParseProgram()
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{10, 20, 30}, program.Order)
+		assert.IsType(t, &ast.RemStatement{}, program.Lines[20])
+		assert.IsType(t, &ast.AssignmentStatement{}, program.Lines[30])
+	})
+
+	t.Run("apostrophe starts a comment line", func(t *testing.T) {
+		program, err := createParser("10 ' it's a \"note\n20 X = 1").ParseProgram()
+		require.NoError(t, err)
+
+		rem, ok := program.Lines[10].(*ast.RemStatement)
+		require.True(t, ok, "Expected RemStatement")
+		assert.Equal(t, `it's a "note`, rem.Comment)
+	})
+
+	t.Run("apostrophe comment trails a statement", func(t *testing.T) {
+		program, err := createParser("10 X = 1 ' set X\n20 PRINT ' blank\n30 PRINT \"a'b\": END ' done").ParseProgram()
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{10, 20, 30}, program.Order)
+		assert.IsType(t, &ast.AssignmentStatement{}, program.Lines[10])
+		assert.IsType(t, &ast.PrintStatement{}, program.Lines[20])
+		compound, ok := program.Lines[30].(*ast.CompoundStatement)
+		require.True(t, ok, "Expected CompoundStatement")
+		require.Len(t, compound.Statements, 2)
+		assert.IsType(t, &ast.EndStatement{}, compound.Statements[1])
+	})
+}
+
+func TestParser_ParseGosubAndReturn(t *testing.T) {
+	program, err := createParser("10 GOSUB 100\n20 END\n100 PRINT \"sub\"\n110 IF X > 0 THEN GOSUB 100\n120 RETURN").ParseProgram()
+	require.NoError(t, err)
+
+	gosub, ok := program.Lines[10].(*ast.GosubStatement)
+	require.True(t, ok, "Expected GosubStatement")
+	assert.Equal(t, 100, gosub.LineNumber)
+	assert.Same(t, program, gosub.Program)
+
+	ifStmt, ok := program.Lines[110].(*ast.IfStatement)
+	require.True(t, ok, "Expected IfStatement")
+	assert.Same(t, program, ifStmt.ThenStatement.(*ast.GosubStatement).Program, "GOSUB after THEN is linked too")
+
+	assert.IsType(t, &ast.ReturnStatement{}, program.Lines[120])
+
+	_, err = createParser("GOSUB").ParseStatement()
+	assert.EqualError(t, err, "expected line number after GOSUB at line 1, column 6")
+}
+
+func TestParser_ParseWhileAndWend(t *testing.T) {
+	program, err := createParser("10 WHILE I < 3 AND NOT DONE\n20 I = I + 1\n30 WEND").ParseProgram()
+	require.NoError(t, err)
+
+	while, ok := program.Lines[10].(*ast.WhileStatement)
+	require.True(t, ok, "Expected WhileStatement")
+	assert.Equal(t, "((I < 3) AND (NOT DONE))", ast.FormatExpression(while.Condition))
+	assert.Equal(t, 10, while.LineNum)
+	assert.Same(t, program, while.Program)
+
+	assert.IsType(t, &ast.WendStatement{}, program.Lines[30])
+
+	_, err = createParser("WHILE").ParseStatement()
+	assert.Error(t, err)
+}
+
+func TestParser_ParseDoAndLoop(t *testing.T) {
+	program, err := createParser("10 DO\n20 I = I + 1\n30 LOOP UNTIL I = 3\n40 DO WHILE I > 0 : I = I - 1 : LOOP").ParseProgram()
+	require.NoError(t, err)
+
+	do, ok := program.Lines[10].(*ast.DoStatement)
+	require.True(t, ok, "Expected DoStatement")
+	assert.Nil(t, do.Condition)
+	assert.Equal(t, 10, do.LineNum)
+	assert.Same(t, program, do.Program)
+
+	loop, ok := program.Lines[30].(*ast.LoopStatement)
+	require.True(t, ok, "Expected LoopStatement")
+	assert.True(t, loop.Until)
+	assert.Equal(t, "(I = 3)", ast.FormatExpression(loop.Condition))
+
+	compound, ok := program.Lines[40].(*ast.CompoundStatement)
+	require.True(t, ok, "Expected CompoundStatement")
+	doWhile := compound.Statements[0].(*ast.DoStatement)
+	assert.False(t, doWhile.Until)
+	assert.Equal(t, "(I > 0)", ast.FormatExpression(doWhile.Condition))
+	assert.Same(t, program, doWhile.Program, "DO after a colon is linked too")
+	assert.Nil(t, compound.Statements[2].(*ast.LoopStatement).Condition)
+
+	_, err = createParser("DO X").ParseStatement()
+	assert.EqualError(t, err, "expected WHILE or UNTIL after DO, found 'X' (IDENTIFIER) at line 1, column 4")
+}
+
+func TestParser_ParseDefFnStatement(t *testing.T) {
+	stmt, err := createParser("DEF FNSQ(X) = X * X + 1").ParseStatement()
+	require.NoError(t, err)
+
+	def, ok := stmt.(*ast.DefFnStatement)
+	require.True(t, ok, "Expected DefFnStatement")
+	assert.Equal(t, "FNSQ", def.Name)
+	assert.Equal(t, "X", def.Parameter)
+	assert.Equal(t, "((X * X) + 1)", ast.FormatExpression(def.Body))
+
+	call, err := createParser("FNSQ(5) * 2").ParseExpression()
+	require.NoError(t, err)
+	assert.Equal(t, "(FNSQ(5) * 2)", ast.FormatExpression(call))
+
+	_, err = createParser("DEF SQ(X) = X * X").ParseStatement()
+	assert.EqualError(t, err, "expected function name starting with FN after DEF, found 'SQ' (IDENTIFIER) at line 1, column 5")
+
+	_, err = createParser("DEF FNSQ(X) X * X").ParseStatement()
+	assert.EqualError(t, err, "expected = in DEF FNSQ at line 1, column 13")
+}
+
+func TestParser_ParseDimStatement(t *testing.T) {
+	stmt, err := createParser("DIM A(10), N$(N + 1)").ParseStatement()
+	require.NoError(t, err)
+	dim, ok := stmt.(*ast.DimStatement)
+	require.True(t, ok, "Expected DimStatement")
+	require.Len(t, dim.Arrays, 2)
+	assert.Equal(t, "A", dim.Arrays[0].Name)
+	assert.Equal(t, "N$", dim.Arrays[1].Name)
+	assert.Equal(t, "(N + 1)", ast.FormatExpression(dim.Arrays[1].Sizes[0]))
+	
+	stmt, err = createParser("DIM M(3, 4)").ParseStatement()
+	require.NoError(t, err)
+	assert.Len(t, stmt.(*ast.DimStatement).Arrays[0].Sizes, 2)
+	
+	_, err = createParser("DIM A").ParseStatement()
+	assert.Error(t, err)
+	
+	_, err = createParser("DIM A(10").ParseStatement()
+	assert.Error(t, err)
+}
+
+func TestParser_ArrayReferences(t *testing.T) {
+	t.Run("a dimensioned name with parentheses is an array element", func(t *testing.T) {
+		program, err := createParser("10 DIM A(10)\n20 A(3) = 5\n30 PRINT A(3) + LEN(\"X\")").ParseProgram()
+		require.NoError(t, err)
+		
+		assignment, ok := program.Lines[20].(*ast.ArrayAssignmentStatement)
+		require.True(t, ok, "Expected ArrayAssignmentStatement")
+		assert.Equal(t, "A", assignment.Name)
+		
+		print := program.Lines[30].(*ast.PrintStatement)
+		sum := print.Expressions[0].(*ast.BinaryExpression)
+		assert.IsType(t, &ast.ArrayElementExpression{}, sum.Left)
+		assert.IsType(t, &ast.FunctionCallExpression{}, sum.Right)
+	})
+	
+	t.Run("multi-dimensional elements take one index per dimension", func(t *testing.T) {
+		program, err := createParser("10 DIM M(3, 4)\n20 M(1, J + 1) = M(0, 0)").ParseProgram()
+		require.NoError(t, err)
+		
+		assignment := program.Lines[20].(*ast.ArrayAssignmentStatement)
+		assert.Len(t, assignment.Indexes, 2)
+		assert.Equal(t, "M(0, 0)", ast.FormatExpression(assignment.Expression))
+	})
+	
+	t.Run("without DIM the name is a function call", func(t *testing.T) {
+		expr, err := createParser("A(3)").ParseStandaloneExpression()
+		require.NoError(t, err)
+		assert.IsType(t, &ast.FunctionCallExpression{}, expr)
+	})
+	
+	t.Run("DIM earlier on the same line counts", func(t *testing.T) {
+		program, err := createParser("10 DIM A(3) : PRINT A(I + 1)").ParseProgram()
+		require.NoError(t, err)
+		
+		compound := program.Lines[10].(*ast.CompoundStatement)
+		print := compound.Statements[1].(*ast.PrintStatement)
+		assert.Equal(t, "A((I + 1))", ast.FormatExpression(print.Expressions[0]))
+	})
+	
+	t.Run("empty parentheses list the whole array", func(t *testing.T) {
+		program, err := createParser("10 DIM A(3)\n20 PRINT A(); A(1)").ParseProgram()
+		require.NoError(t, err)
+		
+		print := program.Lines[20].(*ast.PrintStatement)
+		assert.IsType(t, &ast.ArrayListExpression{}, print.Expressions[0])
+		assert.IsType(t, &ast.ArrayElementExpression{}, print.Expressions[1])
+		assert.Equal(t, "PRINT A(); A(1)", print.String())
+	})
+}
+
+func TestParser_ParseDataStatement(t *testing.T) {
+	stmt, err := createParser(`DATA 1, -2.5, +3, "three"`).ParseStatement()
+	require.NoError(t, err)
+	data, ok := stmt.(*ast.DataStatement)
+	require.True(t, ok, "Expected DataStatement")
+	assert.Equal(t, []runtime.Value{
+		runtime.NewNumericValue(1),
+		runtime.NewNumericValue(-2.5),
+		runtime.NewNumericValue(3),
+		runtime.NewStringValue("three"),
+	}, data.Values)
+	
+	_, err = createParser("DATA").ParseStatement()
+	assert.Error(t, err)
+	
+	_, err = createParser("DATA 1, X").ParseStatement()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected number or string in DATA")
+}
+
+func TestParser_ParseReadStatement(t *testing.T) {
+	stmt, err := createParser("READ A, B$").ParseStatement()
+	require.NoError(t, err)
+	read, ok := stmt.(*ast.ReadStatement)
+	require.True(t, ok, "Expected ReadStatement")
+	assert.Equal(t, []string{"A", "B$"}, read.Variables)
+	
+	_, err = createParser("READ").ParseStatement()
+	assert.Error(t, err)
+	
+	_, err = createParser("READ A,").ParseStatement()
+	assert.Error(t, err)
+	
+	stmt, err = createParser("READ A(), 3").ParseStatement()
+	require.NoError(t, err)
+	fill, ok := stmt.(*ast.ReadArrayStatement)
+	require.True(t, ok, "Expected ReadArrayStatement")
+	assert.Equal(t, "A", fill.Array)
+	assert.Equal(t, "READ A(), 3", fill.String())
+	
+	_, err = createParser("READ A()").ParseStatement()
+	assert.Error(t, err, "the count is required")
+	
+	_, err = createParser("READ A(1), 3").ParseStatement()
+	assert.Error(t, err)
+}
+
+func TestParser_ParseRestoreStatement(t *testing.T) {
+	stmt, err := createParser("RESTORE").ParseStatement()
+	require.NoError(t, err)
+	assert.Equal(t, 0, stmt.(*ast.RestoreStatement).LineNumber)
+	
+	stmt, err = createParser("RESTORE 100").ParseStatement()
+	require.NoError(t, err)
+	assert.Equal(t, 100, stmt.(*ast.RestoreStatement).LineNumber)
+	
+	program, err := createParser("10 DATA 1, 2 : RESTORE\n20 READ A").ParseProgram()
+	require.NoError(t, err)
+	assert.IsType(t, &ast.CompoundStatement{}, program.Lines[10])
+	assert.IsType(t, &ast.ReadStatement{}, program.Lines[20])
+}
+
+func TestParser_ParseRandomizeStatement(t *testing.T) {
+	stmt, err := createParser("RANDOMIZE TIMER").ParseStatement()
+	require.NoError(t, err)
+	randomize, ok := stmt.(*ast.RandomizeStatement)
+	require.True(t, ok, "Expected RandomizeStatement")
+	call, ok := randomize.Seed.(*ast.FunctionCallExpression)
+	require.True(t, ok, "TIMER is a function call without parentheses")
+	assert.Equal(t, "TIMER", call.Name)
+
+	stmt, err = createParser("RANDOMIZE 42").ParseStatement()
+	require.NoError(t, err)
+	assert.NotNil(t, stmt.(*ast.RandomizeStatement).Seed)
+
+	stmt, err = createParser("RANDOMIZE").ParseStatement()
+	require.NoError(t, err)
+	assert.Nil(t, stmt.(*ast.RandomizeStatement).Seed)
+}
+
+func TestParser_StatementString(t *testing.T) {
+	tests := []struct {
+		source    string
+		canonical string
+	}{
+		{`print   "hi";a,b;`, `PRINT "hi"; A, B;`},
+		{`PRINT`, `PRINT`},
+		{`PRINT TAB(10); "X"; SPC(2)`, `PRINT TAB(10); "X"; SPC(2)`},
+		{`let x=1+2*3`, `X = 1 + 2 * 3`},
+		{`X = (1 + 2) * 3`, `X = (1 + 2) * 3`},
+		{`X = 2 ^ 3 ^ 2`, `X = 2 ^ 3 ^ 2`},
+		{`X = 10 - 4 - 3`, `X = 10 - 4 - 3`},
+		{`X = -Y ^ 2`, `X = -Y ^ 2`},
+		{`X = 0 - Y * 2`, `X = 0 - Y * 2`},
+		{`X = A MOD 3 + B \ 2`, `X = A MOD 3 + B \ 2`},
+		{`X = 0.5 + 6.02E23 + &HFF`, `X = 0.5 + 6.02E+23 + 255`},
+		{`X = NOT A = B AND C OR D`, `X = NOT A = B AND C OR D`},
+		{`x$ = mid$(a$, 2, 3) + str$(rnd)`, `X$ = MID$(A$, 2, 3) + STR$(RND)`},
+		{`S$ = SPRINT$(A; B,)`, `S$ = SPRINT$(A; B,)`},
+		{`input "Name"; n$`, `INPUT "Name"; N$`},
+		{`INPUT A RANGE 1 TO 10`, `INPUT A RANGE 1 TO 10`},
+		{`LINE INPUT L$`, `LINE INPUT L$`},
+		{`IF A > 1 THEN PRINT "big": B = 2 ELSE GOTO 100`, `IF A > 1 THEN PRINT "big": B = 2 ELSE GOTO 100`},
+		{`IF A GOTO 50`, `IF A THEN GOTO 50`},
+		{`FOR I = 1 TO 10`, `FOR I = 1 TO 10`},
+		{`for i = 10 to 1 step -1`, `FOR I = 10 TO 1 STEP -1`},
+		{`NEXT I`, `NEXT I`},
+		{`GOSUB 100: RETURN`, `GOSUB 100: RETURN`},
+		{`DIM A(10), M(3, 4)`, `DIM A(10), M(3, 4)`},
+		{`DIM A(3): A(1) = A(2) + 1`, `DIM A(3): A(1) = A(2) + 1`},
+		{`DATA 1, -2.5, "three"`, `DATA 1, -2.5, "three"`},
+		{`READ A, B$: RESTORE 200`, `READ A, B$: RESTORE 200`},
+		{`DEF FNsq(x) = x * x`, `DEF FNSQ(X) = X * X`},
+		{`DO WHILE A < 3`, `DO WHILE A < 3`},
+		{`LOOP UNTIL A >= 3`, `LOOP UNTIL A >= 3`},
+		{`WHILE A: WEND`, `WHILE A: WEND`},
+		{`END 2`, `END 2`},
+		{`STOP`, `STOP`},
+		{`RANDOMIZE TIMER`, `RANDOMIZE TIMER`},
+		{`PAUSE "Press a key"`, `PAUSE "Press a key"`},
+		{`ASSERT A <> 0`, `ASSERT A <> 0`},
+		{`SHELL "ls", OUT$`, `SHELL "ls", OUT$`},
+		{`REM   keep Case`, `REM keep Case`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			stmt, err := createParser(tt.source).parseStatementList()
+			require.NoError(t, err)
+			assert.Equal(t, tt.canonical, stmt.String())
+
+			// The canonical form parses back to a statement with the same canonical form
+			reparsed, err := createParser(stmt.String()).parseStatementList()
+			require.NoError(t, err)
+			assert.Equal(t, tt.canonical, reparsed.String())
+		})
+	}
+}
+
+func TestParser_StatementString_KeepsMeaning(t *testing.T) {
+	// Rendering adds the parentheses needed to keep the tree the parser built
+	stmt, err := createParser("X = -Y ^ 2 + (0 - Z) * 3").parseStatementList()
+	require.NoError(t, err)
+
+	env := runtime.NewEnvironment()
+	env.SetVariable("Y", runtime.NewNumericValue(3))
+	env.SetVariable("Z", runtime.NewNumericValue(2))
+	require.NoError(t, stmt.Execute(env))
+	original := env.GetVariable("X")
+
+	reparsed, err := createParser(stmt.String()).parseStatementList()
+	require.NoError(t, err)
+	require.NoError(t, reparsed.Execute(env))
+	assert.Equal(t, original, env.GetVariable("X"))
+	assert.Equal(t, runtime.NewNumericValue(3), original, "(-3)^2 + (-2)*3")
+}
+
+func TestParser_ProgramRoundTrip(t *testing.T) {
+	// Parsing the rendered source of a program gives the same program back
+	programs := map[string]string{
+		"loops and arithmetic": `10 REM SUM OF SQUARES
+20 S = 0
+30 FOR I = 1 TO 10 STEP 2
+40 S = S + I ^ 2 * (I - 1) / 3
+50 NEXT I
+60 PRINT "SUM"; S, -S
+70 END`,
+		"branches and subroutines": `10 INPUT "NAME"; N$
+20 IF LEN(N$) = 0 OR N$ = "X" THEN GOTO 10 ELSE GOSUB 100
+30 IF NOT (A > 1 AND B <= 2) THEN PRINT "NO": STOP
+40 END
+100 PRINT LEFT$(N$, 1) + MID$(N$, 2, 3);
+110 RETURN`,
+		"arrays, data and functions": `10 DIM A(10), B$(2, 3)
+20 DEF FNSQ(X) = X * X
+30 READ A, B$
+40 DATA 1.5, "HI", -2
+50 A(2) = FNSQ(A) + INT(RND * 6)
+60 PRINT TAB(5); SPC(2); SPRINT$(A(2); "!")
+70 RESTORE 40`,
+		"do and while loops": `10 DO WHILE X < 5
+20 X = X + 1
+30 LOOP
+40 DO
+50 X = X - 1
+60 LOOP UNTIL X <= 0
+70 WHILE X <> 3
+80 X = X + 1
+90 WEND`,
+	}
+
+	for name, source := range programs {
+		t.Run(name, func(t *testing.T) {
+			program, err := createParser(source).ParseProgram()
+			require.NoError(t, err)
+
+			var rendered []string
+			for _, lineNum := range program.Order {
+				rendered = append(rendered, fmt.Sprintf("%d %s", lineNum, program.Lines[lineNum].String()))
+			}
+			reparsed, err := createParser(strings.Join(rendered, "\n")).ParseProgram()
+			require.NoError(t, err, strings.Join(rendered, "\n"))
+
+			assert.Equal(t, program, reparsed)
+		})
+	}
+}
+
+func TestParser_ErrorPositions(t *testing.T) {
+	// Errors point at the token where the statement went wrong
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"missing TO in FOR", "FOR I = 1 10", "expected TO in FOR statement at line 1, column 11"},
+		{"missing = in FOR", "FOR I 1 TO 10", "expected = in FOR statement at line 1, column 7"},
+		{"missing GOTO target", "GOTO X", "expected line number after GOTO at line 1, column 6"},
+		{"missing closing parenthesis", "PRINT (1 + 2", "expected ) at line 1, column 13"},
+		{"unclosed function call", "X = LEFT$(A$, 2", "expected ) in function call at line 1, column 16"},
+		{"operator without operand", "X = 1 +", "unexpected end of input after operator + at line 1, column 8"},
+		{"unexpected token", "X = * 2", "unexpected token in expression: * at line 1, column 5"},
+		{"missing assignment", "X 5", "expected assignment operator at line 1, column 3"},
+		{"TAB outside PRINT", "X = TAB(3)", "TAB can only be used in PRINT at line 1, column 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := createParser(tt.input).ParseStatement()
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+
+	t.Run("positions count source lines", func(t *testing.T) {
+		_, err := createParser("10 PRINT 1\n20 FOR I = 1 10").ParseProgram()
+
+		var syntaxError *SyntaxError
+		require.ErrorAs(t, err, &syntaxError)
+		assert.Equal(t, 20, syntaxError.Line)
+		assert.EqualError(t, err, "error parsing statement at BASIC line 20: expected TO in FOR statement at line 2, column 14",
+			"the position is given once")
+	})
+}
+
+func TestParser_ParseProgramCollectingErrors(t *testing.T) {
+	t.Run("every bad line is reported and the others are kept", func(t *testing.T) {
+		source := "10 PRINT \"START\"\n20 FOR I = 1 10\n30 GOTO 50\n40 X = * 2\n50 END"
+		program, syntaxErrors := createParser(source).ParseProgramCollectingErrors()
+
+		require.Len(t, syntaxErrors, 2)
+		assert.Equal(t, 20, syntaxErrors[0].Line)
+		assert.Equal(t, 2, syntaxErrors[0].SourceLine)
+		assert.Equal(t, 14, syntaxErrors[0].Column)
+		assert.EqualError(t, syntaxErrors[0].Err, "expected TO in FOR statement at line 2, column 14")
+		assert.Equal(t, 40, syntaxErrors[1].Line)
+		assert.Equal(t, 4, syntaxErrors[1].SourceLine)
+		assert.Equal(t, 8, syntaxErrors[1].Column)
+		assert.ErrorContains(t, syntaxErrors[1].Err, "unexpected token in expression: * at line 4, column 8")
+
+		assert.Equal(t, []int{10, 30, 50}, program.Order)
+		gotoStmt, ok := program.Lines[30].(*ast.GotoStatement)
+		require.True(t, ok, "Expected GotoStatement")
+		assert.Same(t, program, gotoStmt.Program)
+	})
+
+	t.Run("errors in line numbers are reported where the line starts", func(t *testing.T) {
+		source := "10 PRINT 1\n100000 PRINT 2\n10 PRINT 3\n20 END"
+		program, syntaxErrors := createParser(source).ParseProgramCollectingErrors()
+
+		require.Len(t, syntaxErrors, 2)
+		assert.Equal(t, 0, syntaxErrors[0].Line)
+		assert.Equal(t, 2, syntaxErrors[0].SourceLine)
+		assert.Equal(t, 1, syntaxErrors[0].Column)
+		assert.EqualError(t, syntaxErrors[0], "error parsing line: invalid line number: 100000 (must be <= 99999) at line 2, column 1")
+		assert.Equal(t, 10, syntaxErrors[1].Line)
+		assert.ErrorContains(t, syntaxErrors[1], "duplicate line number: 10")
+
+		assert.Equal(t, []int{10, 20}, program.Order)
+	})
+
+	t.Run("adjacent bad lines are each reported on their own line", func(t *testing.T) {
+		source := "10 PRINT \"A\"\n30 PRINT (\n40 X =\n50 END"
+		program, syntaxErrors := createParser(source).ParseProgramCollectingErrors()
+
+		require.Len(t, syntaxErrors, 2)
+		assert.Equal(t, 30, syntaxErrors[0].Line)
+		assert.Equal(t, 2, syntaxErrors[0].SourceLine, "the error is where line 30 ends, not on the next line")
+		assert.ErrorContains(t, syntaxErrors[0].Err, "missing expression at end of line 2")
+		assert.Equal(t, 40, syntaxErrors[1].Line)
+		assert.Equal(t, 3, syntaxErrors[1].SourceLine)
+		assert.ErrorContains(t, syntaxErrors[1].Err, "missing expression at end of line 3")
+
+		assert.Equal(t, []int{10, 50}, program.Order)
+	})
+
+	t.Run("a program without errors parses as ParseProgram does", func(t *testing.T) {
+		source := "10 X = 1\n20 PRINT X"
+		expected, err := createParser(source).ParseProgram()
+		require.NoError(t, err)
+
+		program, syntaxErrors := createParser(source).ParseProgramCollectingErrors()
+		assert.Empty(t, syntaxErrors)
+		assert.Equal(t, expected, program)
+	})
+}
+
+func TestParser_ParseProgram_MissingJumpTargets(t *testing.T) {
+	t.Run("forward GOTO to a missing line", func(t *testing.T) {
+		_, err := createParser("10 GOTO 50\n20 PRINT \"HI\"\n30 END").ParseProgram()
+		assert.EqualError(t, err, "line 10 jumps to line 50, which does not exist")
+	})
+
+	t.Run("every missing target is listed", func(t *testing.T) {
+		source := "10 GOSUB 100\n20 IF X THEN GOTO 10 ELSE GOTO 25\n30 END"
+		_, err := createParser(source).ParseProgram()
+		assert.EqualError(t, err, "line 10 jumps to line 100, which does not exist; line 20 jumps to line 25, which does not exist")
+	})
+
+	t.Run("jumps to existing lines are accepted", func(t *testing.T) {
+		_, err := createParser("10 GOSUB 40\n20 IF X THEN GOTO 10\n30 END\n40 RETURN").ParseProgram()
+		assert.NoError(t, err)
+	})
+
+	t.Run("ParseProgramLines leaves jumps unchecked", func(t *testing.T) {
+		program, err := createParser("10 GOTO 50").ParseProgramLines()
+		require.NoError(t, err)
+		assert.Equal(t, []int{10}, program.Order)
+	})
+}
@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Array holds the elements of an array declared with DIM
+// The elements are stored in one flat slice in row-major order: the last index
+// varies fastest, and each index is multiplied by the stride of its dimension
+type Array struct {
+	Sizes    []int   // Highest index of each dimension, as given to DIM
+	Strides  []int   // Number of elements one step in each dimension moves over
+	Elements []Value // All elements, row-major
+}
+
+// newArray allocates an array with the given sizes, filling it with the initial value
+// Arrays of more than limit elements are rejected before anything is allocated
+func newArray(name string, sizes []int, initial Value, limit int) (*Array, error) {
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("array %s needs at least one dimension", name)
+	}
+
+	strides := make([]int, len(sizes))
+	count := 1
+	for d := len(sizes) - 1; d >= 0; d-- {
+		if sizes[d] < 0 {
+			return nil, fmt.Errorf("invalid size %d for array %s", sizes[d], name)
+		}
+		// Compare before multiplying, so huge sizes cannot overflow the count
+		if sizes[d] >= limit || count > limit/(sizes[d]+1) {
+			return nil, fmt.Errorf("out of memory: array %s needs more than the %d values left", declaration(name, sizes), max(limit, 0))
+		}
+		strides[d] = count
+		count *= sizes[d] + 1
+	}
+
+	elements := make([]Value, count)
+	for i := range elements {
+		elements[i] = initial
+	}
+	return &Array{Sizes: append([]int(nil), sizes...), Strides: strides, Elements: elements}, nil
+}
+
+// offset returns the position in Elements of the element at the given indexes
+// The error names the dimension whose index is out of range
+func (a *Array) offset(name string, indexes []int) (int, error) {
+	if len(indexes) != len(a.Sizes) {
+		return 0, fmt.Errorf("array %s has %d dimension(s), got %d index(es)", a.declaration(name), len(a.Sizes), len(indexes))
+	}
+
+	offset := 0
+	for d, index := range indexes {
+		if index < 0 || index > a.Sizes[d] {
+			if len(a.Sizes) == 1 {
+				return 0, fmt.Errorf("index %d out of bounds for array %s", index, a.declaration(name))
+			}
+			return 0, fmt.Errorf("index %d out of bounds in dimension %d of array %s", index, d+1, a.declaration(name))
+		}
+		offset += index * a.Strides[d]
+	}
+	return offset, nil
+}
+
+// store sets the element at an offset; as with SetVariable, numbers stored in
+// integer arrays (names ending in %) are truncated
+func (a *Array) store(name string, offset int, value Value) {
+	if IsIntegerVariableName(name) && value.Type == NumericValue {
+		value = NewNumericValue(math.Trunc(value.NumValue))
+	}
+	a.Elements[offset] = value
+}
+
+// declaration renders the array as it was declared, such as M(3,4)
+func (a *Array) declaration(name string) string {
+	return declaration(name, a.Sizes)
+}
+
+// declaration writes an array name with its sizes, as DIM gives them
+func declaration(name string, sizes []int) string {
+	written := make([]string, len(sizes))
+	for i, size := range sizes {
+		written[i] = strconv.Itoa(size)
+	}
+	return name + "(" + strings.Join(written, ",") + ")"
+}
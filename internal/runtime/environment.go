@@ -1,11 +1,24 @@
 package runtime
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
 )
 
+// ResumePoint is a place execution can continue from: a line, and a statement on it
+// Statements are numbered from 0 in the order they are written, counting those after
+// colons and inside IF branches, with the ELSE keyword itself taking a number so that
+// the statement after the last one of a THEN branch is the end of the IF
+type ResumePoint struct {
+	Line      int
+	Statement int
+}
+
 // ForLoopState represents the state of a FOR loop
 type ForLoopState struct {
 	Variable string
@@ -13,16 +26,125 @@ type ForLoopState struct {
 	End      float64
 	Step     float64
 	LineNum  int
+	Body     int // Number on LineNum of the statement after the FOR, where each pass begins
+}
+
+// WhileLoopState represents an active WHILE loop
+type WhileLoopState struct {
+	LineNum   int           // Line of the WHILE statement
+	Body      int           // Number on LineNum of the statement after the WHILE, where each pass begins
+	Condition LoopCondition // Evaluated again by WEND to decide whether to repeat
+}
+
+// DoLoopState represents an active DO loop
+type DoLoopState struct {
+	LineNum   int           // Line of the DO statement
+	Body      int           // Number on LineNum of the statement after the DO, where each pass begins
+	Condition LoopCondition // Condition written after DO, nil when LOOP tests one or the loop has none
+	Until     bool          // The condition ends the loop when true, rather than when false
+}
+
+// LoopCondition is an expression deciding whether a WHILE or DO loop runs its body again
+type LoopCondition interface {
+	Evaluate(env *Environment) (Value, error)
+}
+
+// UserFunction is a function defined with DEF FN: one expression of a single parameter
+type UserFunction struct {
+	Name      string
+	Parameter string
+	Body      FunctionBody
+}
+
+// FunctionBody is the expression a DEF FN function evaluates
+type FunctionBody interface {
+	Evaluate(env *Environment) (Value, error)
+}
+
+// Call evaluates the function's body with its parameter set to the argument
+// The parameter shadows any variable of the same name, which gets its value back afterwards
+func (f UserFunction) Call(argument Value, env *Environment) (Value, error) {
+	key := env.normalizeVariableName(f.Parameter)
+	saved, existed := env.Variables[key]
+	defer func() {
+		if existed {
+			env.Variables[key] = saved
+		} else {
+			delete(env.Variables, key)
+		}
+	}()
+
+	env.SetVariable(f.Parameter, argument)
+	return f.Body.Evaluate(env)
 }
 
+// DeterministicSeed is the fixed random seed used when runs must be reproducible
+const DeterministicSeed int64 = 20250101
+
+// DefaultMaxExpressionDepth is the default limit on nested expression evaluation
+const DefaultMaxExpressionDepth = 1000
+
+// Default capacities reported by FRE; there is no real memory model, so these are budgets
+const (
+	DefaultMemoryBudget = 32768 // Number of values that can be stored
+	DefaultStringSpace  = 65536 // Total characters that string values can hold
+)
+
+// ErrExpressionTooComplex is returned when expression nesting exceeds MaxExpressionDepth
+var ErrExpressionTooComplex = errors.New("expression too complex")
+
+// ErrFeatureDisabled is returned when a program uses a statement or function the host disabled
+var ErrFeatureDisabled = errors.New("feature disabled")
+
 // Environment represents the runtime environment for BASIC program execution
+// An Environment holds the state of a single run and is not safe for concurrent use
 type Environment struct {
-	Variables      map[string]Value    // Case-insensitive variable storage
-	ProgramCounter int                 // Current line number being executed
-	CallStack      []int               // Stack for nested control structures
-	ForLoops       []ForLoopState      // Stack for nested FOR loops
-	RandomSeed     int64               // Seed for random number generation
-	rng            *rand.Rand          // Random number generator
+	Variables      map[string]Value        // Case-insensitive variable storage
+	Arrays         map[string]*Array       // Arrays declared with DIM, by normalized name
+	ProgramCounter int                     // Current line number being executed
+	Statement      int                     // Number of the running statement on its line, as ResumePoint counts
+	Jumped         bool                    // Set when a statement transfers control, even to its own line
+	JumpTarget     ResumePoint             // Where control goes when Jumped is set
+	CallStack      []ResumePoint           // Statements that RETURN resumes at, innermost GOSUB last
+	ForLoops       []ForLoopState          // Stack for nested FOR loops
+	WhileLoops     []WhileLoopState        // Stack for nested WHILE loops
+	DoLoops        []DoLoopState           // Stack for nested DO loops
+	Functions      map[string]UserFunction // Functions defined with DEF FN, by normalized name
+	RandomSeed     int64                   // Seed for random number generation
+	rng            *rand.Rand              // Random number generator
+	Clock          func() time.Time        // Current time for TIMER and RANDOMIZE; replaceable in tests
+	
+	MaxExpressionDepth int // Maximum nesting of expression evaluation
+	expressionDepth    int // Current nesting of expression evaluation
+	
+	MemoryBudget int // Number of values that can be stored, as reported by FRE
+	StringSpace  int // Total characters available to string values, as reported by FRE
+	
+	Halted   bool // Set when the program has stopped, by END or by running off its last line
+	Stopped  bool // Set along with Halted when a STOP statement ended the run
+	ExitCode int  // Exit code given to END, 0 when none
+	
+	// CleanNumbers stops PRINT from putting a space before non-negative numbers, where
+	// classic BASIC prints the sign. Off by default, which keeps the authentic output;
+	// turn it on for clean output such as data export
+	CleanNumbers bool
+	
+	// CoerceComparisons lets comparisons mix a number with a string holding a number,
+	// such as 5 = "5", by comparing both as numbers. Off by default, where mixing types
+	// in a comparison is an error
+	CoerceComparisons bool
+	
+	// NumberFormat sets how PRINT and STR$ write numbers and how VAL and INPUT read them
+	NumberFormat NumberFormat
+	
+	Data       []Value // Values of the program's DATA statements, in program order
+	DataCursor int     // Index in Data of the next value READ takes
+	dataLines  []int   // Line each value in Data was written on, for RESTORE n
+	
+	disabledFeatures map[string]bool // Statements and functions the program may not use
+	
+	appendBuffers map[string]*strings.Builder // Buffers behind string variables built by AppendString
+	arguments     []Value                     // Arguments of the built-in function calls being evaluated
 }
 
 // NewEnvironment creates a new runtime environment
@@ -30,14 +152,149 @@ func NewEnvironment() *Environment {
 	seed := time.Now().UnixNano()
 	return &Environment{
 		Variables:      make(map[string]Value),
+		Arrays:         make(map[string]*Array),
 		ProgramCounter: 0,
-		CallStack:      make([]int, 0),
+		CallStack:      make([]ResumePoint, 0),
 		ForLoops:       make([]ForLoopState, 0),
+		WhileLoops:     make([]WhileLoopState, 0),
+		DoLoops:        make([]DoLoopState, 0),
+		Functions:      make(map[string]UserFunction),
 		RandomSeed:     seed,
 		rng:            rand.New(rand.NewSource(seed)),
+		Clock:          time.Now,
+		
+		MaxExpressionDepth: DefaultMaxExpressionDepth,
+		
+		MemoryBudget: DefaultMemoryBudget,
+		StringSpace:  DefaultStringSpace,
+		
+		NumberFormat: DefaultNumberFormat,
 	}
 }
 
+// JumpTo transfers control to the first statement of a line, as GOTO does
+func (env *Environment) JumpTo(lineNumber int) {
+	env.ResumeAt(ResumePoint{Line: lineNumber})
+}
+
+// ResumeAt transfers control to a statement, which may be on the line that is running
+func (env *Environment) ResumeAt(point ResumePoint) {
+	env.Jumped = true
+	env.JumpTarget = point
+	env.ProgramCounter = point.Line
+}
+
+// NextStatement returns where execution goes on after the running statement
+func (env *Environment) NextStatement() ResumePoint {
+	return ResumePoint{Line: env.ProgramCounter, Statement: env.Statement + 1}
+}
+
+// Halt stops the program with the given exit code
+func (env *Environment) Halt(exitCode int) {
+	env.Halted = true
+	env.ExitCode = exitCode
+}
+
+// Stop halts the program at a STOP statement, which ends the run like END but is
+// reported as a break rather than a normal end
+func (env *Environment) Stop() {
+	env.Halt(0)
+	env.Stopped = true
+}
+
+// DisableFeature forbids a statement or function, by keyword or function name.
+// Only names that are checked with CheckFeature have an effect; the interpreter
+// validates its DisabledFeatures before passing them here
+func (env *Environment) DisableFeature(name string) {
+	if env.disabledFeatures == nil {
+		env.disabledFeatures = make(map[string]bool)
+	}
+	env.disabledFeatures[strings.ToUpper(name)] = true
+}
+
+// CheckFeature returns ErrFeatureDisabled if the named statement or function was disabled
+func (env *Environment) CheckFeature(name string) error {
+	if env.disabledFeatures[strings.ToUpper(name)] {
+		return fmt.Errorf("%w: %s", ErrFeatureDisabled, strings.ToUpper(name))
+	}
+	return nil
+}
+
+// ClearData empties the data pool and rewinds the read cursor
+func (env *Environment) ClearData() {
+	env.Data = env.Data[:0]
+	env.dataLines = env.dataLines[:0]
+	env.DataCursor = 0
+}
+
+// AddData appends the values of a DATA statement on the given line to the data pool
+func (env *Environment) AddData(line int, values ...Value) {
+	for _, value := range values {
+		env.Data = append(env.Data, value)
+		env.dataLines = append(env.dataLines, line)
+	}
+}
+
+// ReadData returns the next value in the data pool and advances the cursor
+func (env *Environment) ReadData() (Value, error) {
+	if env.DataCursor >= len(env.Data) {
+		return Value{}, fmt.Errorf("out of DATA")
+	}
+	value := env.Data[env.DataCursor]
+	env.DataCursor++
+	return value, nil
+}
+
+// RestoreData moves the cursor to the first value written on or after the given line
+// RestoreData(0) rewinds to the first value of the program
+func (env *Environment) RestoreData(line int) {
+	env.DataCursor = sort.SearchInts(env.dataLines, line)
+}
+
+// FreeMemory returns the memory budget minus the number of stored values,
+// counting each element of an array declared with DIM
+func (env *Environment) FreeMemory() int {
+	used := len(env.Variables)
+	for _, array := range env.Arrays {
+		used += len(array.Elements)
+	}
+	return env.MemoryBudget - used
+}
+
+// FreeStringSpace returns the string space minus the characters held by string variables
+// and by the elements of string arrays
+func (env *Environment) FreeStringSpace() int {
+	used := 0
+	for _, value := range env.Variables {
+		if value.Type == StringValue {
+			used += len(value.StrValue)
+		}
+	}
+	for _, array := range env.Arrays {
+		for _, element := range array.Elements {
+			if element.Type == StringValue {
+				used += len(element.StrValue)
+			}
+		}
+	}
+	return env.StringSpace - used
+}
+
+// EnterExpression records the start of a nested expression evaluation
+// It returns ErrExpressionTooComplex, without entering, when the depth limit would be exceeded
+func (env *Environment) EnterExpression() error {
+	if env.expressionDepth >= env.MaxExpressionDepth {
+		return ErrExpressionTooComplex
+	}
+	env.expressionDepth++
+	return nil
+}
+
+// LeaveExpression records the end of an expression evaluation started with EnterExpression
+func (env *Environment) LeaveExpression() {
+	env.expressionDepth--
+}
+
 // GetVariable retrieves a variable value (case-insensitive)
 func (env *Environment) GetVariable(name string) Value {
 	key := env.normalizeVariableName(name)
@@ -50,11 +307,152 @@ func (env *Environment) GetVariable(name string) Value {
 }
 
 // SetVariable sets a variable value (case-insensitive)
+// Numeric values stored in integer variables (names ending in %) are truncated toward zero,
+// so expressions are evaluated in floating point and only the assignment drops the fraction
 func (env *Environment) SetVariable(name string, value Value) {
 	key := env.normalizeVariableName(name)
+	if IsIntegerVariableName(key) && value.Type == NumericValue {
+		value = NewNumericValue(math.Trunc(value.NumValue))
+	}
 	env.Variables[key] = value
 }
 
+// AppendString sets a string variable to prefix followed by suffix, as S$ = S$ + X$ does
+// Each variable keeps a buffer with room to grow: when prefix is what the buffer holds, the
+// suffix is added in place, so building a string in a loop copies each character about once
+// instead of on every append. Strings taken from the buffer earlier are left unchanged
+func (env *Environment) AppendString(name, prefix, suffix string) {
+	key := env.normalizeVariableName(name)
+	buffer, exists := env.appendBuffers[key]
+	if !exists || buffer.String() != prefix {
+		if env.appendBuffers == nil {
+			env.appendBuffers = make(map[string]*strings.Builder)
+		}
+		buffer = &strings.Builder{}
+		buffer.Grow(len(prefix) + len(suffix))
+		buffer.WriteString(prefix)
+		env.appendBuffers[key] = buffer
+	}
+	buffer.WriteString(suffix)
+	env.Variables[key] = NewStringValue(buffer.String())
+}
+
+// ArgumentHeight returns the number of values on the argument stack, where the arguments
+// of a function call about to be evaluated will start
+func (env *Environment) ArgumentHeight() int {
+	return len(env.arguments)
+}
+
+// PushArgument adds the value of a function argument to the argument stack
+// Reusing the stack saves allocating a slice of arguments for every call
+func (env *Environment) PushArgument(value Value) {
+	env.arguments = append(env.arguments, value)
+}
+
+// PopArguments removes the values pushed since the stack had the given height and returns them
+// They stay valid only until the next PushArgument, so a function must not keep its arguments
+func (env *Environment) PopArguments(height int) []Value {
+	args := env.arguments[height:]
+	env.arguments = env.arguments[:height]
+	return args
+}
+
+// DefineFunction registers a DEF FN function, replacing any earlier one of the same name
+func (env *Environment) DefineFunction(function UserFunction) {
+	env.Functions[env.normalizeVariableName(function.Name)] = function
+}
+
+// LookupFunction returns the DEF FN function with the given name (case-insensitive)
+func (env *Environment) LookupFunction(name string) (UserFunction, bool) {
+	function, exists := env.Functions[env.normalizeVariableName(name)]
+	return function, exists
+}
+
+// DimArray declares an array with one dimension per size, each indexed from 0 to its size,
+// as DIM M(3, 4) does. Elements start out as 0, or as "" for string arrays; an array can
+// be declared only once, and only if its elements fit in the memory FreeMemory reports
+func (env *Environment) DimArray(name string, sizes []int) error {
+	key := env.normalizeVariableName(name)
+	if _, exists := env.Arrays[key]; exists {
+		return fmt.Errorf("array %s already dimensioned", key)
+	}
+	
+	array, err := newArray(key, sizes, env.getDefaultValue(key), env.FreeMemory())
+	if err != nil {
+		return err
+	}
+	if env.Arrays == nil {
+		env.Arrays = make(map[string]*Array)
+	}
+	env.Arrays[key] = array
+	return nil
+}
+
+// GetArrayElement returns an element of an array declared with DimArray
+func (env *Environment) GetArrayElement(name string, indexes []int) (Value, error) {
+	array, offset, err := env.arrayElement(name, indexes)
+	if err != nil {
+		return Value{}, err
+	}
+	return array.Elements[offset], nil
+}
+
+// SetArrayElement stores an element of an array declared with DimArray
+// As with SetVariable, numbers stored in integer arrays (names ending in %) are truncated
+func (env *Environment) SetArrayElement(name string, indexes []int, value Value) error {
+	array, offset, err := env.arrayElement(name, indexes)
+	if err != nil {
+		return err
+	}
+	array.store(name, offset, value)
+	return nil
+}
+
+// ArrayElements returns all elements of an array declared with DimArray, in row-major
+// order: for DIM M(1, 2) that is M(0,0), M(0,1), M(0,2), M(1,0) and so on.
+// The slice is the array's own storage, so callers must not change it
+func (env *Environment) ArrayElements(name string) ([]Value, error) {
+	key := env.normalizeVariableName(name)
+	array, exists := env.Arrays[key]
+	if !exists {
+		return nil, fmt.Errorf("array %s not dimensioned", key)
+	}
+	return array.Elements, nil
+}
+
+// SetArrayElementAt stores the element at a position in the order ArrayElements lists them
+func (env *Environment) SetArrayElementAt(name string, position int, value Value) error {
+	key := env.normalizeVariableName(name)
+	array, exists := env.Arrays[key]
+	if !exists {
+		return fmt.Errorf("array %s not dimensioned", key)
+	}
+	if position < 0 || position >= len(array.Elements) {
+		return fmt.Errorf("element %d out of bounds for array %s", position, array.declaration(key))
+	}
+	array.store(name, position, value)
+	return nil
+}
+
+// arrayElement looks up a declared array and the position of an element in it
+func (env *Environment) arrayElement(name string, indexes []int) (*Array, int, error) {
+	key := env.normalizeVariableName(name)
+	array, exists := env.Arrays[key]
+	if !exists {
+		return nil, 0, fmt.Errorf("array %s not dimensioned", key)
+	}
+	offset, err := array.offset(key, indexes)
+	if err != nil {
+		return nil, 0, err
+	}
+	return array, offset, nil
+}
+
+// IsIntegerVariableName checks if a variable name indicates an integer variable (ends with %)
+func IsIntegerVariableName(name string) bool {
+	return strings.HasSuffix(name, "%")
+}
+
 // normalizeVariableName converts variable names to uppercase for case-insensitive storage
 func (env *Environment) normalizeVariableName(name string) string {
 	return strings.ToUpper(name)
@@ -79,6 +477,14 @@ func (env *Environment) SetRandomSeed(seed int64) {
 	env.rng = rand.New(rand.NewSource(seed))
 }
 
+// Now returns the current time from the environment's clock
+func (env *Environment) Now() time.Time {
+	if env.Clock == nil {
+		return time.Now()
+	}
+	return env.Clock()
+}
+
 // GetRandomSeed returns the current random number generator seed
 func (env *Environment) GetRandomSeed() int64 {
 	return env.RandomSeed
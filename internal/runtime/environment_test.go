@@ -152,17 +152,17 @@ func TestEnvironmentStateManagement(t *testing.T) {
 		assert.Empty(t, env.CallStack)
 		
 		// Push values onto call stack
-		env.CallStack = append(env.CallStack, 10)
-		env.CallStack = append(env.CallStack, 20)
-		env.CallStack = append(env.CallStack, 30)
+		env.CallStack = append(env.CallStack, ResumePoint{Line: 10})
+		env.CallStack = append(env.CallStack, ResumePoint{Line: 20, Statement: 1})
+		env.CallStack = append(env.CallStack, ResumePoint{Line: 30})
 		
 		assert.Len(t, env.CallStack, 3)
-		assert.Equal(t, []int{10, 20, 30}, env.CallStack)
+		assert.Equal(t, []ResumePoint{{Line: 10}, {Line: 20, Statement: 1}, {Line: 30}}, env.CallStack)
 		
 		// Pop from call stack
 		env.CallStack = env.CallStack[:len(env.CallStack)-1]
 		assert.Len(t, env.CallStack, 2)
-		assert.Equal(t, []int{10, 20}, env.CallStack)
+		assert.Equal(t, []ResumePoint{{Line: 10}, {Line: 20, Statement: 1}}, env.CallStack)
 	})
 
 	t.Run("FOR loop state management", func(t *testing.T) {
@@ -198,7 +198,7 @@ func TestEnvironmentVariableScoping(t *testing.T) {
 		
 		// Modify program counter and call stack
 		env.ProgramCounter = 50
-		env.CallStack = append(env.CallStack, 100)
+		env.CallStack = append(env.CallStack, ResumePoint{Line: 100})
 		
 		// Variables should still be accessible
 		assert.Equal(t, 10.0, env.GetVariable("X").NumValue)
@@ -309,4 +309,177 @@ func TestEnvironmentEdgeCases(t *testing.T) {
 		assert.Equal(t, 100.0, env1.GetVariable("X").NumValue)
 		assert.Equal(t, 200.0, env2.GetVariable("X").NumValue)
 	})
-}
\ No newline at end of file
+}
+
+func TestIntegerVariables(t *testing.T) {
+	t.Run("numeric value is truncated on assignment", func(t *testing.T) {
+		env := NewEnvironment()
+		env.SetVariable("I%", NewNumericValue(3.5))
+		assert.Equal(t, 3.0, env.GetVariable("I%").NumValue)
+	})
+
+	t.Run("negative value is truncated toward zero", func(t *testing.T) {
+		env := NewEnvironment()
+		env.SetVariable("i%", NewNumericValue(-3.5))
+		assert.Equal(t, -3.0, env.GetVariable("I%").NumValue)
+	})
+
+	t.Run("integer and float variables are distinct", func(t *testing.T) {
+		env := NewEnvironment()
+		env.SetVariable("A", NewNumericValue(2.75))
+		env.SetVariable("A%", NewNumericValue(2.75))
+		assert.Equal(t, 2.75, env.GetVariable("A").NumValue)
+		assert.Equal(t, 2.0, env.GetVariable("A%").NumValue)
+	})
+
+	t.Run("undefined integer variable defaults to zero", func(t *testing.T) {
+		env := NewEnvironment()
+		result := env.GetVariable("N%")
+		assert.Equal(t, NumericValue, result.Type)
+		assert.Equal(t, 0.0, result.NumValue)
+	})
+
+	t.Run("name suffix detection", func(t *testing.T) {
+		assert.True(t, IsIntegerVariableName("COUNT%"))
+		assert.False(t, IsIntegerVariableName("COUNT"))
+		assert.False(t, IsIntegerVariableName("COUNT$"))
+	})
+}
+
+func TestEnvironmentArrays(t *testing.T) {
+	t.Run("elements default to zero or empty string", func(t *testing.T) {
+		env := NewEnvironment()
+		require.NoError(t, env.DimArray("a", []int{10}))
+		require.NoError(t, env.DimArray("N$", []int{2}))
+
+		value, err := env.GetArrayElement("A", []int{10})
+		require.NoError(t, err)
+		assert.Equal(t, NewNumericValue(0), value)
+
+		value, err = env.GetArrayElement("n$", []int{0})
+		require.NoError(t, err)
+		assert.Equal(t, NewStringValue(""), value)
+	})
+
+	t.Run("elements are stored by index and apart from variables", func(t *testing.T) {
+		env := NewEnvironment()
+		require.NoError(t, env.DimArray("A", []int{5}))
+		require.NoError(t, env.SetArrayElement("A", []int{3}, NewNumericValue(7)))
+		env.SetVariable("A", NewNumericValue(1))
+
+		value, err := env.GetArrayElement("a", []int{3})
+		require.NoError(t, err)
+		assert.Equal(t, 7.0, value.NumValue)
+		assert.Equal(t, 1.0, env.GetVariable("A").NumValue)
+	})
+
+	t.Run("integer arrays truncate numbers", func(t *testing.T) {
+		env := NewEnvironment()
+		require.NoError(t, env.DimArray("C%", []int{1}))
+		require.NoError(t, env.SetArrayElement("C%", []int{1}, NewNumericValue(-2.7)))
+		value, err := env.GetArrayElement("C%", []int{1})
+		require.NoError(t, err)
+		assert.Equal(t, -2.0, value.NumValue)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		env := NewEnvironment()
+		require.NoError(t, env.DimArray("A", []int{10}))
+
+		_, err := env.GetArrayElement("A", []int{11})
+		assert.EqualError(t, err, "index 11 out of bounds for array A(10)")
+		err = env.SetArrayElement("A", []int{-1}, NewNumericValue(1))
+		assert.EqualError(t, err, "index -1 out of bounds for array A(10)")
+		_, err = env.GetArrayElement("B", []int{0})
+		assert.EqualError(t, err, "array B not dimensioned")
+		assert.EqualError(t, env.DimArray("a", []int{5}), "array A already dimensioned")
+		assert.EqualError(t, env.DimArray("D", []int{-1}), "invalid size -1 for array D")
+	})
+
+	t.Run("multi-dimensional arrays are stored row-major", func(t *testing.T) {
+		env := NewEnvironment()
+		require.NoError(t, env.DimArray("M", []int{2, 3, 1}))
+		grid := env.Arrays["M"]
+		assert.Len(t, grid.Elements, 3*4*2)
+		assert.Equal(t, []int{8, 2, 1}, grid.Strides)
+
+		require.NoError(t, env.SetArrayElement("M", []int{1, 2, 1}, NewNumericValue(5)))
+		assert.Equal(t, 5.0, grid.Elements[1*8+2*2+1].NumValue)
+
+		_, err := env.GetArrayElement("M", []int{3, 0, 0})
+		assert.EqualError(t, err, "index 3 out of bounds in dimension 1 of array M(2,3,1)")
+		_, err = env.GetArrayElement("M", []int{0, 0, 2})
+		assert.EqualError(t, err, "index 2 out of bounds in dimension 3 of array M(2,3,1)")
+		_, err = env.GetArrayElement("M", []int{0, 0})
+		assert.EqualError(t, err, "array M(2,3,1) has 3 dimension(s), got 2 index(es)")
+	})
+
+	t.Run("arrays must fit in the free memory", func(t *testing.T) {
+		env := NewEnvironment()
+		env.MemoryBudget = 100
+		env.SetVariable("X", NewNumericValue(1))
+
+		assert.EqualError(t, env.DimArray("A", []int{99}), "out of memory: array A(99) needs more than the 99 values left")
+		assert.EqualError(t, env.DimArray("B", []int{100000, 100000}), "out of memory: array B(100000,100000) needs more than the 99 values left")
+		assert.Error(t, env.DimArray("C", []int{3037000500, 3037000500}), "a size product that overflows is rejected")
+		assert.Empty(t, env.Arrays)
+
+		require.NoError(t, env.DimArray("D", []int{9, 8}))
+		assert.Equal(t, 9, env.FreeMemory())
+		assert.Error(t, env.DimArray("E", []int{9}), "memory taken by other arrays is not free")
+	})
+}
+
+func TestEnvironmentDataPool(t *testing.T) {
+	env := NewEnvironment()
+	env.AddData(100, NewNumericValue(1), NewNumericValue(2))
+	env.AddData(200, NewStringValue("three"))
+	env.AddData(300, NewNumericValue(4))
+
+	t.Run("values are read in order", func(t *testing.T) {
+		env.RestoreData(0)
+		for _, expected := range []Value{NewNumericValue(1), NewNumericValue(2), NewStringValue("three"), NewNumericValue(4)} {
+			value, err := env.ReadData()
+			require.NoError(t, err)
+			assert.Equal(t, expected, value)
+		}
+		_, err := env.ReadData()
+		assert.EqualError(t, err, "out of DATA")
+	})
+
+	t.Run("restore goes to the first value on or after the line", func(t *testing.T) {
+		env.RestoreData(200)
+		value, err := env.ReadData()
+		require.NoError(t, err)
+		assert.Equal(t, NewStringValue("three"), value)
+
+		env.RestoreData(250)
+		value, err = env.ReadData()
+		require.NoError(t, err)
+		assert.Equal(t, NewNumericValue(4), value)
+
+		env.RestoreData(400)
+		_, err = env.ReadData()
+		assert.Error(t, err, "no DATA after the last DATA line")
+	})
+
+	t.Run("clearing empties the pool", func(t *testing.T) {
+		env.ClearData()
+		assert.Empty(t, env.Data)
+		assert.Equal(t, 0, env.DataCursor)
+		_, err := env.ReadData()
+		assert.Error(t, err)
+	})
+}
+
+func TestEnvironmentDisabledFeatures(t *testing.T) {
+	env := NewEnvironment()
+	assert.NoError(t, env.CheckFeature("INPUT"), "features are enabled by default")
+
+	env.DisableFeature("input")
+	err := env.CheckFeature("INPUT")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrFeatureDisabled)
+	assert.Contains(t, err.Error(), "feature disabled: INPUT")
+	assert.NoError(t, env.CheckFeature("PRINT"))
+}
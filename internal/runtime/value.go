@@ -56,22 +56,65 @@ func (v Value) ToNumber() (float64, error) {
 	case NumericValue:
 		return v.NumValue, nil
 	case StringValue:
-		// Trim whitespace before parsing
-		trimmed := strings.TrimSpace(v.StrValue)
-		if val, err := strconv.ParseFloat(trimmed, 64); err == nil {
-			return val, nil
-		}
-		return 0, fmt.Errorf("cannot convert string '%s' to number", v.StrValue)
+		return DefaultNumberFormat.Parse(v.StrValue)
 	default:
 		return 0, fmt.Errorf("unknown value type")
 	}
 }
 
+// NumberFormat holds how numbers are written: the magnitudes at which they switch to
+// exponential notation and the decimal separator
+type NumberFormat struct {
+	ExponentAbove float64 // Magnitudes at or above this use exponential notation
+	ExponentBelow float64 // Non-zero magnitudes below this use exponential notation
+	DecimalComma  bool    // Use a comma as decimal separator and a period between thousands, as in 1.234,5
+}
+
+// DefaultNumberFormat switches to exponential notation past 999999 and below 0.0001,
+// like classic BASIC
+var DefaultNumberFormat = NumberFormat{ExponentAbove: 1e6, ExponentBelow: 1e-4}
+
+// Format writes a number in plain or exponential notation depending on its magnitude
+func (f NumberFormat) Format(n float64) string {
+	magnitude := math.Abs(n)
+	if n != 0 && (magnitude >= f.ExponentAbove || magnitude < f.ExponentBelow) {
+		return f.localize(strconv.FormatFloat(n, 'e', -1, 64))
+	}
+	return f.localize(strconv.FormatFloat(n, 'f', -1, 64))
+}
+
+// localize swaps the decimal point of a formatted number for a comma when the format asks for it
+func (f NumberFormat) localize(text string) string {
+	if f.DecimalComma {
+		return strings.Replace(text, ".", ",", 1)
+	}
+	return text
+}
+
+// Parse reads a number written in this format, ignoring surrounding whitespace
+// With DecimalComma, periods are thousands separators and are dropped: "1.234,5" reads as 1234.5
+func (f NumberFormat) Parse(text string) (float64, error) {
+	trimmed := strings.TrimSpace(text)
+	if f.DecimalComma {
+		trimmed = strings.ReplaceAll(trimmed, ".", "")
+		trimmed = strings.Replace(trimmed, ",", ".", 1)
+	}
+	if val, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return val, nil
+	}
+	return 0, fmt.Errorf("cannot convert string '%s' to number", text)
+}
+
 // ToString converts the value to a string value
 func (v Value) ToString() string {
+	return v.Format(DefaultNumberFormat)
+}
+
+// Format converts the value to a string, writing numbers according to the given format
+func (v Value) Format(format NumberFormat) string {
 	switch v.Type {
 	case NumericValue:
-		return fmt.Sprintf("%g", v.NumValue)
+		return format.Format(v.NumValue)
 	case StringValue:
 		return v.StrValue
 	default:
@@ -146,7 +189,7 @@ func compareString(a, b string) int {
 func (v Value) Add(other Value) (Value, error) {
 	// If both are explicitly strings, do string concatenation
 	if v.Type == StringValue && other.Type == StringValue {
-		return NewStringValue(v.ToString() + other.ToString()), nil
+		return NewStringValue(v.StrValue + other.StrValue), nil
 	}
 
 	// Try numeric addition
@@ -194,9 +237,48 @@ func (v Value) Divide(other Value) (Value, error) {
 	})
 }
 
+// IntDivide performs the BASIC integer division operation (\)
+// The quotient is truncated toward zero: 7 \ 2 = 3 and -7 \ 2 = -3
+func (v Value) IntDivide(other Value) (Value, error) {
+	return v.performNumericOperation(other, "divide", func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		quotient := math.Trunc(a / b)
+		if quotient == 0 {
+			return 0, nil // Avoid -0 for small negative quotients
+		}
+		return quotient, nil
+	})
+}
+
+// Modulo performs the BASIC MOD operation
+// The remainder takes the sign of the dividend, as in Microsoft BASIC:
+// 7 MOD 3 = 1, -7 MOD 3 = -1, 7 MOD -3 = 1, -7 MOD -3 = -1.
+// This is truncated division (a - b*TRUNC(a/b)), not Euclidean modulo,
+// and a zero remainder is always reported as 0 rather than -0
+func (v Value) Modulo(other Value) (Value, error) {
+	return v.performNumericOperation(other, "take modulo of", func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		remainder := math.Mod(a, b) // math.Mod truncates, so the sign follows a
+		if remainder == 0 {
+			return 0, nil
+		}
+		return remainder, nil
+	})
+}
+
 // Power performs exponentiation operation
+// A negative base may only be raised to an integer power: (-2)^3 = -8, but (-4)^0.5
+// has no real result and is an error instead of NaN. This includes roots written as
+// fractions, so (-8)^(1/3) is an error too, because 1/3 is not an integer
 func (v Value) Power(other Value) (Value, error) {
 	return v.performNumericOperation(other, "raise strings to power", func(a, b float64) (float64, error) {
+		if a < 0 && b != math.Trunc(b) {
+			return 0, fmt.Errorf("negative base to fractional power")
+		}
 		return math.Pow(a, b), nil
 	})
 }
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
@@ -62,6 +63,84 @@ func TestValueToString(t *testing.T) {
 	assert.Equal(t, "42", intVal.ToString())
 }
 
+func TestValueFormat_ExponentThresholds(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   NumberFormat
+		value    float64
+		expected string
+	}{
+		{"default below upper threshold", DefaultNumberFormat, 999999, "999999"},
+		{"default at upper threshold", DefaultNumberFormat, 1000000, "1e+06"},
+		{"default negative at upper threshold", DefaultNumberFormat, -1000000, "-1e+06"},
+		{"default at lower threshold", DefaultNumberFormat, 0.0001, "0.0001"},
+		{"default below lower threshold", DefaultNumberFormat, 0.00001, "1e-05"},
+		{"default zero", DefaultNumberFormat, 0, "0"},
+		{"wide upper threshold", NumberFormat{ExponentAbove: 1e12, ExponentBelow: 1e-4}, 1000000, "1000000"},
+		{"wide at upper threshold", NumberFormat{ExponentAbove: 1e12, ExponentBelow: 1e-4}, 1e12, "1e+12"},
+		{"scientific below upper threshold", NumberFormat{ExponentAbove: 1000, ExponentBelow: 0.01}, 999.5, "999.5"},
+		{"scientific at upper threshold", NumberFormat{ExponentAbove: 1000, ExponentBelow: 0.01}, 1234, "1.234e+03"},
+		{"scientific at lower threshold", NumberFormat{ExponentAbove: 1000, ExponentBelow: 0.01}, 0.01, "0.01"},
+		{"scientific below lower threshold", NumberFormat{ExponentAbove: 1000, ExponentBelow: 0.01}, 0.005, "5e-03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewNumericValue(tt.value).Format(tt.format))
+		})
+	}
+}
+
+func TestNumberFormat_DecimalComma(t *testing.T) {
+	comma := DefaultNumberFormat
+	comma.DecimalComma = true
+
+	t.Run("formats with a decimal comma", func(t *testing.T) {
+		assert.Equal(t, "3,14", NewNumericValue(3.14).Format(comma))
+		assert.Equal(t, "-0,5", NewNumericValue(-0.5).Format(comma))
+		assert.Equal(t, "1234", NewNumericValue(1234).Format(comma), "no thousands separators are written")
+		assert.Equal(t, "1,5e+06", NewNumericValue(1500000).Format(comma))
+	})
+
+	t.Run("parses a decimal comma and period thousands", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected float64
+		}{
+			{"3,14", 3.14},
+			{" -2,5 ", -2.5},
+			{"1.234,5", 1234.5},
+			{"1.000.000", 1000000},
+			{"42", 42},
+		}
+		for _, tt := range tests {
+			number, err := comma.Parse(tt.input)
+			require.NoError(t, err, tt.input)
+			assert.Equal(t, tt.expected, number, tt.input)
+		}
+
+		_, err := comma.Parse("3,1,4")
+		assert.EqualError(t, err, "cannot convert string '3,1,4' to number")
+	})
+
+	t.Run("default format keeps the decimal point", func(t *testing.T) {
+		number, err := DefaultNumberFormat.Parse("3.14")
+		require.NoError(t, err)
+		assert.Equal(t, 3.14, number)
+
+		_, err = DefaultNumberFormat.Parse("3,14")
+		assert.Error(t, err)
+		assert.Equal(t, "3.14", NewNumericValue(3.14).Format(DefaultNumberFormat))
+	})
+}
+
+func TestValueToString_MatchesDefaultNumberFormat(t *testing.T) {
+	for _, n := range []float64{42, -42.5, 123456, 1234567, 0.001, 0.00001234} {
+		assert.Equal(t, fmt.Sprintf("%g", n), NewNumericValue(n).ToString(), "ToString keeps the classic output for %v", n)
+	}
+	assert.Equal(t, "text", NewStringValue("text").Format(NumberFormat{ExponentAbove: 1}), "strings are not affected by the format")
+}
+
 // Additional tests for comprehensive value system coverage as required by task 3.1
 
 func TestValueTypeConversions_EdgeCases(t *testing.T) {
@@ -227,6 +306,64 @@ func TestValueArithmetic(t *testing.T) {
 		assert.Contains(t, err.Error(), "division by zero")
 	})
 
+	t.Run("IntDivide", func(t *testing.T) {
+		// The quotient is truncated toward zero
+		cases := []struct {
+			dividend, divisor, expected float64
+		}{
+			{7, 2, 3},
+			{-7, 2, -3},
+			{7, -2, -3},
+			{-7, -2, 3},
+			{6, 3, 2},
+			{7.5, 2, 3},
+		}
+		for _, c := range cases {
+			result, err := NewNumericValue(c.dividend).IntDivide(NewNumericValue(c.divisor))
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, result.NumValue, "%v \\ %v", c.dividend, c.divisor)
+		}
+
+		negativeZero, err := NewNumericValue(-1).IntDivide(NewNumericValue(2))
+		require.NoError(t, err)
+		assert.Equal(t, "0", negativeZero.String(), "a zero quotient should not print as -0")
+
+		_, err = NewNumericValue(7).IntDivide(NewNumericValue(0))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "division by zero")
+	})
+
+	t.Run("Modulo", func(t *testing.T) {
+		// The remainder takes the sign of the dividend
+		cases := []struct {
+			dividend, divisor, expected float64
+		}{
+			{7, 3, 1},
+			{-7, 3, -1},
+			{7, -3, 1},
+			{-7, -3, -1},
+			{6, 3, 0},
+			{-6, 3, 0},
+			{7.5, 2, 1.5},
+		}
+		for _, c := range cases {
+			result, err := NewNumericValue(c.dividend).Modulo(NewNumericValue(c.divisor))
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, result.NumValue, "%v MOD %v", c.dividend, c.divisor)
+		}
+
+		negativeZero, err := NewNumericValue(-6).Modulo(NewNumericValue(3))
+		require.NoError(t, err)
+		assert.Equal(t, "0", negativeZero.String(), "a zero remainder should not print as -0")
+
+		_, err = NewNumericValue(7).Modulo(NewNumericValue(0))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "division by zero")
+
+		_, err = NewStringValue("7").Modulo(NewNumericValue(3))
+		assert.Error(t, err)
+	})
+
 	t.Run("Power", func(t *testing.T) {
 		val1 := NewNumericValue(2)
 		val2 := NewNumericValue(3)
@@ -235,6 +372,24 @@ func TestValueArithmetic(t *testing.T) {
 		assert.Equal(t, 8.0, result.NumValue)
 		assert.Equal(t, NumericValue, result.Type)
 
+		// Negative bases work with integer exponents
+		result, err = NewNumericValue(-2).Power(NewNumericValue(3))
+		require.NoError(t, err)
+		assert.Equal(t, -8.0, result.NumValue)
+
+		result, err = NewNumericValue(-2).Power(NewNumericValue(-2))
+		require.NoError(t, err)
+		assert.Equal(t, 0.25, result.NumValue)
+
+		// Fractional exponents on negative bases have no real result
+		_, err = NewNumericValue(-4).Power(NewNumericValue(0.5))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "negative base to fractional power")
+
+		// Cube roots are not special-cased: 1/3 is fractional, so (-8)^(1/3) errors
+		_, err = NewNumericValue(-8).Power(NewNumericValue(1.0 / 3.0))
+		assert.Error(t, err)
+
 		// String power should error
 		str1 := NewStringValue("Hello")
 		str2 := NewStringValue("World")